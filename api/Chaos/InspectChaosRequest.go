@@ -0,0 +1,5 @@
+package api
+
+type InspectChaosRequest struct {
+	Id string `json:"id"`
+}
@@ -0,0 +1,13 @@
+package api
+
+import (
+	"github.com/David-Antunes/gone/api"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+)
+
+// Events holds every fault injected and healed by the run, in order
+type InspectChaosResponse struct {
+	Run    api.ChaosRun     `json:"run"`
+	Events []api.ChaosEvent `json:"events"`
+	Error  apiErrors.Error  `json:"err"`
+}
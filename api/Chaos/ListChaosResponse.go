@@ -0,0 +1,11 @@
+package api
+
+import (
+	"github.com/David-Antunes/gone/api"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+)
+
+type ListChaosResponse struct {
+	Runs  []api.ChaosRun  `json:"runs"`
+	Error apiErrors.Error `json:"err"`
+}
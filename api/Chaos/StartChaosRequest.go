@@ -0,0 +1,21 @@
+package api
+
+// Randomly injects and heals faults for DurationMs. Rate is the average number of faults per second and
+// Faults the allowed fault types: disrupt, latencySpike, lossBurst, pause and partition.
+// Each fault lasts between MinFaultMs and MaxFaultMs. Latency spikes add LatencySpikeMs to the link
+// and loss bursts set the drop rate of each direction to LossBurst.
+// Without Nodes, Bridges or Routers every component of the emulation can be targeted.
+// Starting a run with the same seed over the same topology replays the same faults.
+type StartChaosRequest struct {
+	Seed           int64      `json:"seed"`
+	Rate           float64    `json:"rate"`
+	DurationMs     float64    `json:"durationMs"`
+	Faults         []string   `json:"faults"`
+	MinFaultMs     float64    `json:"minFaultMs"`
+	MaxFaultMs     float64    `json:"maxFaultMs"`
+	LatencySpikeMs float64    `json:"latencySpikeMs"`
+	LossBurst      float64    `json:"lossBurst"`
+	Nodes          []string   `json:"nodes"`
+	Bridges        []string   `json:"bridges"`
+	Routers        [][]string `json:"routers"`
+}
@@ -0,0 +1,12 @@
+package api
+
+import (
+	"github.com/David-Antunes/gone/api"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+)
+
+type StartChaosResponse struct {
+	Id    string          `json:"id"`
+	Run   api.ChaosRun    `json:"run"`
+	Error apiErrors.Error `json:"err"`
+}
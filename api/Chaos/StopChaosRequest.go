@@ -0,0 +1,5 @@
+package api
+
+type StopChaosRequest struct {
+	Id string `json:"id"`
+}
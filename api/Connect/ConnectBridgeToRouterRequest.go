@@ -1,5 +1,7 @@
 package api
 
+import "github.com/David-Antunes/gone/api"
+
 type ConnectBridgeToRouterRequest struct {
 	Bridge    string  `json:"bridge"`
 	Router    string  `json:"router"`
@@ -8,4 +10,11 @@ type ConnectBridgeToRouterRequest struct {
 	DropRate  float64 `json:"dropRate"`
 	Bandwidth int     `json:"bandwidth"`
 	Weight    int     `json:"weight"`
+	api.Impairments
+	Loss         *api.LossModel         `json:"loss,omitempty"`
+	Distribution *api.DelayDistribution `json:"distribution,omitempty"`
+	Queue        *api.QueueConfig       `json:"queue,omitempty"`
+	Trace        *api.Trace             `json:"trace,omitempty"`
+	Upstream     *LinkDirection         `json:"upstream,omitempty"`
+	Downstream   *LinkDirection         `json:"downstream,omitempty"`
 }
@@ -1,5 +1,7 @@
 package api
 
+import "github.com/David-Antunes/gone/api"
+
 type ConnectRouterToRouterRequest struct {
 	From      string  `json:"from"`
 	To        string  `json:"to"`
@@ -9,4 +11,11 @@ type ConnectRouterToRouterRequest struct {
 	Bandwidth int     `json:"bandwidth"`
 	Weight    int     `json:"weight"`
 	Propagate bool    `json:"propagate"`
+	api.Impairments
+	Loss         *api.LossModel         `json:"loss,omitempty"`
+	Distribution *api.DelayDistribution `json:"distribution,omitempty"`
+	Queue        *api.QueueConfig       `json:"queue,omitempty"`
+	Trace        *api.Trace             `json:"trace,omitempty"`
+	Upstream     *LinkDirection         `json:"upstream,omitempty"`
+	Downstream   *LinkDirection         `json:"downstream,omitempty"`
 }
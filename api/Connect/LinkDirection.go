@@ -0,0 +1,20 @@
+package api
+
+import "github.com/David-Antunes/gone/api"
+
+// One-way link properties. Unlike the symmetric fields of the connect requests,
+// these values are not split between both directions of the link. Unset fields keep the symmetric values.
+type LinkDirection struct {
+	Latency      *float64               `json:"latency,omitempty"`
+	Jitter       *float64               `json:"jitter,omitempty"`
+	DropRate     *float64               `json:"dropRate,omitempty"`
+	Bandwidth    *int                   `json:"bandwidth,omitempty"`
+	Loss         *api.LossModel         `json:"loss,omitempty"`
+	Distribution *api.DelayDistribution `json:"distribution,omitempty"`
+	Queue        *api.QueueConfig       `json:"queue,omitempty"`
+	Trace        *api.Trace             `json:"trace,omitempty"`
+	// Seed of the direction, derived from the emulation seed and the link id when 0
+	Seed int64 `json:"seed,omitempty"`
+	// Impairments of the connect request are kept when not set
+	*api.Impairments
+}
@@ -1,8 +1,12 @@
 package api
 
-import api "github.com/David-Antunes/gone/api/Errors"
+import (
+	"github.com/David-Antunes/gone/api"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+)
 
 type InspectLinkResponse struct {
-	Name  string    `json:"name"`
-	Error api.Error `json:"err"`
+	Name  string          `json:"name"`
+	Link  api.LinkState   `json:"link"`
+	Error apiErrors.Error `json:"err"`
 }
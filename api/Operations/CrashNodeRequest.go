@@ -0,0 +1,9 @@
+package Operations
+
+// Kill stops the container without letting it shut down. The node is restarted after RestartMs,
+// a value of 0 keeps the node down until it is restarted.
+type CrashNodeRequest struct {
+	Node      string  `json:"node"`
+	Kill      bool    `json:"kill,omitempty"`
+	RestartMs float64 `json:"restartMs,omitempty"`
+}
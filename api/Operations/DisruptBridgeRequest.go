@@ -1,5 +1,10 @@
 package Operations
 
+// The bridge is restored after DurationMs. A duration of 0 keeps the disruption until it is stopped.
+// Direction disrupts only the upstream (bridge -> router) or downstream (router -> bridge) direction of the link,
+// both directions are disrupted when it is empty.
 type DisruptBridgeRequest struct {
-	Bridge string `json:"bridge"`
+	Bridge     string  `json:"bridge"`
+	Direction  string  `json:"direction,omitempty"`
+	DurationMs float64 `json:"durationMs,omitempty"`
 }
@@ -1,5 +1,10 @@
 package Operations
 
+// The node is restored after DurationMs. A duration of 0 keeps the disruption until it is stopped.
+// Direction disrupts only the upstream (node -> bridge) or downstream (bridge -> node) direction of the link,
+// both directions are disrupted when it is empty.
 type DisruptNodeRequest struct {
-	Node string `json:"node"`
+	Node       string  `json:"node"`
+	Direction  string  `json:"direction,omitempty"`
+	DurationMs float64 `json:"durationMs,omitempty"`
 }
@@ -1,6 +1,11 @@
 package Operations
 
+// The routers are restored after DurationMs. A duration of 0 keeps the disruption until it is stopped.
+// Direction disrupts only the upstream (router1 -> router2) or downstream (router2 -> router1) direction of the link,
+// both directions are disrupted when it is empty.
 type DisruptRoutersRequest struct {
-	Router1 string `json:"router1"`
-	Router2 string `json:"router2"`
+	Router1    string  `json:"router1"`
+	Router2    string  `json:"router2"`
+	Direction  string  `json:"direction,omitempty"`
+	DurationMs float64 `json:"durationMs,omitempty"`
 }
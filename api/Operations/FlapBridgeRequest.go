@@ -0,0 +1,10 @@
+package Operations
+
+import "github.com/David-Antunes/gone/api"
+
+// Repeatedly disrupts and restores the link of the bridge. Direction works as in DisruptBridgeRequest.
+type FlapBridgeRequest struct {
+	Bridge    string `json:"bridge"`
+	Direction string `json:"direction,omitempty"`
+	api.FlapConfig
+}
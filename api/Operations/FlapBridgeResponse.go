@@ -0,0 +1,9 @@
+package Operations
+
+import api "github.com/David-Antunes/gone/api/Errors"
+
+type FlapBridgeResponse struct {
+	Bridge string    `json:"bridge"`
+	Id     string    `json:"id"`
+	Error  api.Error `json:"error"`
+}
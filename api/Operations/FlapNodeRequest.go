@@ -0,0 +1,10 @@
+package Operations
+
+import "github.com/David-Antunes/gone/api"
+
+// Repeatedly disrupts and restores the link of the node. Direction works as in DisruptNodeRequest.
+type FlapNodeRequest struct {
+	Node      string `json:"node"`
+	Direction string `json:"direction,omitempty"`
+	api.FlapConfig
+}
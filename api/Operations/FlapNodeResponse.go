@@ -0,0 +1,9 @@
+package Operations
+
+import api "github.com/David-Antunes/gone/api/Errors"
+
+type FlapNodeResponse struct {
+	Node  string    `json:"node"`
+	Id    string    `json:"id"`
+	Error api.Error `json:"error"`
+}
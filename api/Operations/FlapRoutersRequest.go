@@ -0,0 +1,11 @@
+package Operations
+
+import "github.com/David-Antunes/gone/api"
+
+// Repeatedly disrupts and restores the link between the routers. Direction works as in DisruptRoutersRequest.
+type FlapRoutersRequest struct {
+	Router1   string `json:"router1"`
+	Router2   string `json:"router2"`
+	Direction string `json:"direction,omitempty"`
+	api.FlapConfig
+}
@@ -0,0 +1,6 @@
+package Operations
+
+type HealRequest struct {
+	Id  string `json:"id"`
+	All bool   `json:"all"`
+}
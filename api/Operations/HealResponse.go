@@ -0,0 +1,9 @@
+package Operations
+
+import api "github.com/David-Antunes/gone/api/Errors"
+
+type HealResponse struct {
+	Id    string    `json:"id"`
+	All   bool      `json:"all"`
+	Error api.Error `json:"error"`
+}
@@ -0,0 +1,11 @@
+package Operations
+
+import (
+	"github.com/David-Antunes/gone/api"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+)
+
+type ListDisruptionsResponse struct {
+	Disruptions []api.Disruption `json:"disruptions"`
+	Error       apiErrors.Error  `json:"error"`
+}
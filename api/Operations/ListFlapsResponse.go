@@ -0,0 +1,11 @@
+package Operations
+
+import (
+	"github.com/David-Antunes/gone/api"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+)
+
+type ListFlapsResponse struct {
+	Flaps []api.Flap      `json:"flaps"`
+	Error apiErrors.Error `json:"error"`
+}
@@ -0,0 +1,11 @@
+package Operations
+
+import (
+	"github.com/David-Antunes/gone/api"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+)
+
+type ListPartitionsResponse struct {
+	Partitions []api.Partition `json:"partitions"`
+	Error      apiErrors.Error `json:"error"`
+}
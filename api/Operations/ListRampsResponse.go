@@ -0,0 +1,11 @@
+package Operations
+
+import (
+	"github.com/David-Antunes/gone/api"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+)
+
+type ListRampsResponse struct {
+	Ramps []api.Ramp      `json:"ramps"`
+	Error apiErrors.Error `json:"error"`
+}
@@ -0,0 +1,6 @@
+package Operations
+
+// Blocks the traffic between groups of nodes. Traffic inside each group is kept.
+type PartitionRequest struct {
+	Groups [][]string `json:"groups"`
+}
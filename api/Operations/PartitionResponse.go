@@ -0,0 +1,9 @@
+package Operations
+
+import api "github.com/David-Antunes/gone/api/Errors"
+
+type PartitionResponse struct {
+	Id     string     `json:"id"`
+	Groups [][]string `json:"groups"`
+	Error  api.Error  `json:"error"`
+}
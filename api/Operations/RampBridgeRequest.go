@@ -0,0 +1,10 @@
+package Operations
+
+import "github.com/David-Antunes/gone/api"
+
+// Gradually moves the properties of the link of the bridge to the targets of the ramp. Direction works as in DisruptBridgeRequest.
+type RampBridgeRequest struct {
+	Bridge    string `json:"bridge"`
+	Direction string `json:"direction,omitempty"`
+	api.RampConfig
+}
@@ -0,0 +1,9 @@
+package Operations
+
+import api "github.com/David-Antunes/gone/api/Errors"
+
+type RampBridgeResponse struct {
+	Bridge string    `json:"bridge"`
+	Id     string    `json:"id"`
+	Error  api.Error `json:"error"`
+}
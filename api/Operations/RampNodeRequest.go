@@ -0,0 +1,10 @@
+package Operations
+
+import "github.com/David-Antunes/gone/api"
+
+// Gradually moves the properties of the link of the node to the targets of the ramp. Direction works as in DisruptNodeRequest.
+type RampNodeRequest struct {
+	Node      string `json:"node"`
+	Direction string `json:"direction,omitempty"`
+	api.RampConfig
+}
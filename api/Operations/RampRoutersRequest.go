@@ -0,0 +1,11 @@
+package Operations
+
+import "github.com/David-Antunes/gone/api"
+
+// Gradually moves the properties of the link between the routers to the targets of the ramp. Direction works as in DisruptRoutersRequest.
+type RampRoutersRequest struct {
+	Router1   string `json:"router1"`
+	Router2   string `json:"router2"`
+	Direction string `json:"direction,omitempty"`
+	api.RampConfig
+}
@@ -0,0 +1,10 @@
+package Operations
+
+import api "github.com/David-Antunes/gone/api/Errors"
+
+type RampRoutersResponse struct {
+	Router1 string    `json:"router1"`
+	Router2 string    `json:"router2"`
+	Id      string    `json:"id"`
+	Error   api.Error `json:"error"`
+}
@@ -0,0 +1,5 @@
+package Operations
+
+type RestartNodeRequest struct {
+	Node string `json:"node"`
+}
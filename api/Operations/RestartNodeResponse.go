@@ -0,0 +1,8 @@
+package Operations
+
+import apiErrors "github.com/David-Antunes/gone/api/Errors"
+
+type RestartNodeResponse struct {
+	Node  string          `json:"node"`
+	Error apiErrors.Error `json:"error"`
+}
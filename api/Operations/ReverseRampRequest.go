@@ -0,0 +1,6 @@
+package Operations
+
+// Moves the links back to the values they had before the ramp, over the time the ramp has run so far
+type ReverseRampRequest struct {
+	Id string `json:"id"`
+}
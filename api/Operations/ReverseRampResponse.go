@@ -0,0 +1,8 @@
+package Operations
+
+import api "github.com/David-Antunes/gone/api/Errors"
+
+type ReverseRampResponse struct {
+	Id    string    `json:"id"`
+	Error api.Error `json:"error"`
+}
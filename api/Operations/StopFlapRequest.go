@@ -0,0 +1,5 @@
+package Operations
+
+type StopFlapRequest struct {
+	Id string `json:"id"`
+}
@@ -0,0 +1,7 @@
+package Operations
+
+// Restore sets the links back to the values they had before the ramp, otherwise they keep their current values
+type StopRampRequest struct {
+	Id      string `json:"id"`
+	Restore bool   `json:"restore,omitempty"`
+}
@@ -0,0 +1,5 @@
+package api
+
+type CancelTimelineRequest struct {
+	Id string `json:"id"`
+}
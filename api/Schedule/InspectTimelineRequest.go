@@ -0,0 +1,5 @@
+package api
+
+type InspectTimelineRequest struct {
+	Id string `json:"id"`
+}
@@ -0,0 +1,11 @@
+package api
+
+import (
+	"github.com/David-Antunes/gone/api"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+)
+
+type InspectTimelineResponse struct {
+	Timeline api.Timeline    `json:"timeline"`
+	Error    apiErrors.Error `json:"err"`
+}
@@ -0,0 +1,11 @@
+package api
+
+import (
+	"github.com/David-Antunes/gone/api"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+)
+
+type ListTimelinesResponse struct {
+	Timelines []api.Timeline  `json:"timelines"`
+	Error     apiErrors.Error `json:"err"`
+}
@@ -0,0 +1,10 @@
+package api
+
+import "github.com/David-Antunes/gone/api"
+
+// Actions run relative to Start, given in RFC 3339. When Start is empty the timeline starts DelayMs after being received.
+type ScheduleTimelineRequest struct {
+	Start   string               `json:"start"`
+	DelayMs float64              `json:"delayMs"`
+	Actions []api.TimelineAction `json:"actions"`
+}
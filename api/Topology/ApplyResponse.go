@@ -0,0 +1,9 @@
+package api
+
+import apiErrors "github.com/David-Antunes/gone/api/Errors"
+
+// Steps run in order and stop at the first failure, the remaining steps are skipped
+type ApplyResponse struct {
+	Steps []Step          `json:"steps"`
+	Error apiErrors.Error `json:"err"`
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"github.com/David-Antunes/gone/api"
+	connectApi "github.com/David-Antunes/gone/api/Connect"
+)
+
+// Declarative description of an emulation. Documents can be written in YAML or JSON,
+// with the same field names in both formats.
+// Links reference the names of the components, and run from node to bridge, bridge to router or router to router.
+type Document struct {
+	Nodes     []NodeSpec      `json:"nodes"`
+	Bridges   []ComponentSpec `json:"bridges"`
+	Routers   []ComponentSpec `json:"routers"`
+	Links     []LinkSpec      `json:"links"`
+	Propagate bool            `json:"propagate"`
+	Unpause   bool            `json:"unpause"`
+}
+
+// Node created by running DockerCmd on MachineId. Name is the container name used by the links,
+// Mac and Ip are assigned by docker.
+type NodeSpec struct {
+	Name      string   `json:"name"`
+	MachineId string   `json:"machineId"`
+	DockerCmd []string `json:"dockerCmd"`
+	Mac       string   `json:"mac,omitempty"`
+	Ip        string   `json:"ip,omitempty"`
+}
+
+type ComponentSpec struct {
+	Name      string `json:"name"`
+	MachineId string `json:"machineId"`
+}
+
+// Link between two components, with the same properties as the connect requests
+type LinkSpec struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	LinkProperties
+	Upstream   *connectApi.LinkDirection `json:"upstream,omitempty"`
+	Downstream *connectApi.LinkDirection `json:"downstream,omitempty"`
+}
+
+// Symmetric properties of a link, split between both directions
+type LinkProperties struct {
+	Latency   float64 `json:"latency"`
+	Jitter    float64 `json:"jitter"`
+	DropRate  float64 `json:"dropRate"`
+	Bandwidth int     `json:"bandwidth"`
+	Weight    int     `json:"weight"`
+	api.Impairments
+	Loss         *api.LossModel         `json:"loss,omitempty"`
+	Distribution *api.DelayDistribution `json:"distribution,omitempty"`
+	Queue        *api.QueueConfig       `json:"queue,omitempty"`
+	Trace        *api.Trace             `json:"trace,omitempty"`
+}
+
+// Result of a step of an applied document. Status is planned, done, failed or skipped,
+// and Result holds the id docker gave to a new node.
+type Step struct {
+	Index  int    `json:"index"`
+	Action string `json:"action"`
+	Target string `json:"target"`
+	Status string `json:"status"`
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
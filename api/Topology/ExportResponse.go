@@ -0,0 +1,9 @@
+package api
+
+import apiErrors "github.com/David-Antunes/gone/api/Errors"
+
+// Document can be sent as is to /apply to rebuild the emulation
+type ExportResponse struct {
+	Document Document        `json:"document"`
+	Error    apiErrors.Error `json:"err"`
+}
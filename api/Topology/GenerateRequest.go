@@ -0,0 +1,24 @@
+package api
+
+// Parameters of a generated topology. Shape is star, line, ring, mesh, tree, fatTree, dumbbell, waxman or barabasi.
+// Degree is the fan-out of a tree, the k of a fat-tree and the links of each new router in a Barabási–Albert graph.
+// Alpha and Beta shape the link probability of a Waxman graph. Random shapes use the emulation seed when Seed is 0.
+// Routers are spread over Machines roundRobin or in blocks, and bridges are connected to the edge routers of the shape.
+type GenerateRequest struct {
+	Shape    string   `json:"shape"`
+	Prefix   string   `json:"prefix"`
+	Routers  int      `json:"routers"`
+	Bridges  int      `json:"bridges"`
+	Degree   int      `json:"degree"`
+	Alpha    float64  `json:"alpha"`
+	Beta     float64  `json:"beta"`
+	Seed     int64    `json:"seed"`
+	Machines []string `json:"machines"`
+	Spread   string   `json:"spread"`
+	// Properties of every generated link
+	Link LinkProperties `json:"link"`
+	// Link between the two sides of a dumbbell, Link is used when not set
+	Bottleneck *LinkProperties `json:"bottleneck,omitempty"`
+	// Returns the document without applying it
+	DryRun bool `json:"dryRun"`
+}
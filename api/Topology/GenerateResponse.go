@@ -0,0 +1,10 @@
+package api
+
+import apiErrors "github.com/David-Antunes/gone/api/Errors"
+
+// Document holds the generated topology, which can be kept and applied later
+type GenerateResponse struct {
+	Document Document        `json:"document"`
+	Steps    []Step          `json:"steps"`
+	Error    apiErrors.Error `json:"err"`
+}
@@ -0,0 +1,32 @@
+package api
+
+// Node-link description of the running emulation. Every node, bridge and router is a graph node,
+// and every link between them is an undirected graph link.
+type Graph struct {
+	Directed bool        `json:"directed"`
+	Nodes    []GraphNode `json:"nodes"`
+	Links    []GraphLink `json:"links"`
+}
+
+// Kind is node, bridge or router
+type GraphNode struct {
+	Id        string `json:"id"`
+	Kind      string `json:"kind"`
+	MachineId string `json:"machineId"`
+}
+
+// Link with the symmetric properties of both directions. Disrupted, Sniffed and Intercepted
+// are set when any direction of the link is affected. Frames, Bytes and Drops count the traffic of both directions.
+type GraphLink struct {
+	Source      string  `json:"source"`
+	Target      string  `json:"target"`
+	Latency     float64 `json:"latency"`
+	Bandwidth   int     `json:"bandwidth"`
+	DropRate    float64 `json:"dropRate"`
+	Disrupted   bool    `json:"disrupted"`
+	Sniffed     bool    `json:"sniffed"`
+	Intercepted bool    `json:"intercepted"`
+	Frames      uint64  `json:"frames"`
+	Bytes       uint64  `json:"bytes"`
+	Drops       uint64  `json:"drops"`
+}
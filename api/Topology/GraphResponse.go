@@ -0,0 +1,10 @@
+package api
+
+import apiErrors "github.com/David-Antunes/gone/api/Errors"
+
+// Graph holds the node-link description of the emulation and Dot the same graph in Graphviz DOT
+type GraphResponse struct {
+	Graph Graph           `json:"graph"`
+	Dot   string          `json:"dot"`
+	Error apiErrors.Error `json:"err"`
+}
@@ -0,0 +1,16 @@
+package api
+
+// Topology written in another format. Format is graphml (Topology Zoo), containerlab or mininet (MiniEdit JSON).
+// Components are placed on MachineId, the leader when not set, and hosts run Image when they don't name one.
+// Bridges connects a bridge to every router of GraphML topologies, which only describe routers.
+type ImportRequest struct {
+	Format    string `json:"format"`
+	Contents  string `json:"contents"`
+	MachineId string `json:"machineId"`
+	Image     string `json:"image"`
+	// Properties of links without attributes and of the links added to connect components
+	Link    LinkProperties `json:"link"`
+	Bridges bool           `json:"bridges"`
+	// Returns the document without applying it
+	DryRun bool `json:"dryRun"`
+}
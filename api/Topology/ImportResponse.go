@@ -0,0 +1,11 @@
+package api
+
+import apiErrors "github.com/David-Antunes/gone/api/Errors"
+
+// Document holds the imported topology and Untranslated the constructs it could not express
+type ImportResponse struct {
+	Document     Document        `json:"document"`
+	Untranslated []string        `json:"untranslated"`
+	Steps        []Step          `json:"steps"`
+	Error        apiErrors.Error `json:"err"`
+}
@@ -0,0 +1,8 @@
+package api
+
+// Desired state of the emulation. Components and links of the running emulation missing from Document are removed.
+type ReconcileRequest struct {
+	Document Document `json:"document"`
+	// Returns the plan without changing the emulation
+	DryRun bool `json:"dryRun"`
+}
@@ -0,0 +1,10 @@
+package api
+
+import apiErrors "github.com/David-Antunes/gone/api/Errors"
+
+// Steps of the plan. In a dry run every step is planned, otherwise they are applied like the steps of /apply.
+type ReconcileResponse struct {
+	Steps  []Step          `json:"steps"`
+	DryRun bool            `json:"dryRun"`
+	Error  apiErrors.Error `json:"err"`
+}
@@ -0,0 +1,8 @@
+package api
+
+import api "github.com/David-Antunes/gone/api/Errors"
+
+type UpdateBridgeLinkResponse struct {
+	Bridge string    `json:"bridge"`
+	Error  api.Error `json:"err"`
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"github.com/David-Antunes/gone/api"
+	connectApi "github.com/David-Antunes/gone/api/Connect"
+)
+
+type UpdateNodeLinkRequest struct {
+	Node      string  `json:"node"`
+	Latency   float64 `json:"latency"`
+	Jitter    float64 `json:"jitter"`
+	DropRate  float64 `json:"dropRate"`
+	Bandwidth int     `json:"bandwidth"`
+	api.Impairments
+	Loss         *api.LossModel            `json:"loss,omitempty"`
+	Distribution *api.DelayDistribution    `json:"distribution,omitempty"`
+	Queue        *api.QueueConfig          `json:"queue,omitempty"`
+	Trace        *api.Trace                `json:"trace,omitempty"`
+	Upstream     *connectApi.LinkDirection `json:"upstream,omitempty"`
+	Downstream   *connectApi.LinkDirection `json:"downstream,omitempty"`
+}
@@ -0,0 +1,8 @@
+package api
+
+import api "github.com/David-Antunes/gone/api/Errors"
+
+type UpdateNodeLinkResponse struct {
+	Node  string    `json:"node"`
+	Error api.Error `json:"err"`
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"github.com/David-Antunes/gone/api"
+	connectApi "github.com/David-Antunes/gone/api/Connect"
+)
+
+type UpdateRouterLinkRequest struct {
+	Router1   string  `json:"router1"`
+	Router2   string  `json:"router2"`
+	Latency   float64 `json:"latency"`
+	Jitter    float64 `json:"jitter"`
+	DropRate  float64 `json:"dropRate"`
+	Bandwidth int     `json:"bandwidth"`
+	api.Impairments
+	Loss         *api.LossModel            `json:"loss,omitempty"`
+	Distribution *api.DelayDistribution    `json:"distribution,omitempty"`
+	Queue        *api.QueueConfig          `json:"queue,omitempty"`
+	Trace        *api.Trace                `json:"trace,omitempty"`
+	Upstream     *connectApi.LinkDirection `json:"upstream,omitempty"`
+	Downstream   *connectApi.LinkDirection `json:"downstream,omitempty"`
+}
@@ -0,0 +1,9 @@
+package api
+
+import api "github.com/David-Antunes/gone/api/Errors"
+
+type UpdateRouterLinkResponse struct {
+	Router1 string    `json:"router1"`
+	Router2 string    `json:"router2"`
+	Error   api.Error `json:"err"`
+}
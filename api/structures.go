@@ -1,5 +1,10 @@
 package api
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type Node struct {
 	Id        string
 	Mac       string
@@ -25,9 +30,13 @@ type Router struct {
 }
 
 type Link struct {
-	To        string
-	From      string
-	LinkProps LinkProps
+	To                  string
+	From                string
+	LinkProps           LinkProps
+	Upstream            LinkProps
+	Downstream          LinkProps
+	UpstreamDisrupted   bool
+	DownstreamDisrupted bool
 }
 
 type BiLink struct {
@@ -35,12 +44,114 @@ type BiLink struct {
 	From Link
 }
 
+// State of a topology link, keyed by its BiLink id.
+// Upstream follows the ConnectsTo direction of the link and Downstream the ConnectsFrom direction.
+type LinkState struct {
+	Id         string
+	Upstream   LinkDirectionState
+	Downstream LinkDirectionState
+}
+
+type LinkDirectionState struct {
+	From      string
+	To        string
+	MachineId string
+	Shaper    string
+	Disrupted bool
+	LinkProps LinkProps
+	Stats     LinkStats
+}
+
+type LinkStats struct {
+	Frames         uint64
+	Bytes          uint64
+	LossDrops      uint64
+	QueueDrops     uint64
+	DisruptedDrops uint64
+	QueueDepth     int
+}
+
 type LinkProps struct {
-	Latency   int
-	Bandwidth int
-	Jitter    float64
-	DropRate  float64
-	Weight    int
+	Latency      int
+	Bandwidth    int
+	Jitter       float64
+	DropRate     float64
+	Weight       int
+	Loss         LossModel
+	Distribution string
+	Correlation  float64
+	Queue        QueueConfig
+	QueueStats   QueueStats
+	Reorder      float64
+	ReorderGap   int
+	Duplicate    float64
+	Corrupt      float64
+	// Format of the trace driving the link, empty when the bandwidth is fixed
+	Trace     string
+	TraceLoop bool
+	// Seed of the link random number generator, used to replay a run
+	Seed int64
+}
+
+// Delay distribution of a link: normal, uniform, pareto, paretonormal or table.
+// Latency is used as the mean and jitter as the standard deviation of the distribution.
+type DelayDistribution struct {
+	Type        string  `json:"type"`
+	Correlation float64 `json:"correlation"`
+	// Contents of a netem .dist file, required by the table distribution
+	Table string `json:"table,omitempty"`
+}
+
+// Bandwidth trace replayed on a link instead of its fixed bandwidth.
+// Mahimahi traces hold one delivery opportunity timestamp in milliseconds per line.
+// CSV traces hold time in milliseconds, bandwidth in bits per second and optionally latency in milliseconds and drop rate.
+type Trace struct {
+	Format   string  `json:"format"`
+	Contents string  `json:"contents"`
+	Loop     bool    `json:"loop"`
+	OffsetMs float64 `json:"offsetMs"`
+}
+
+// Buffer limits and queue discipline of a link: taildrop (default), red, codel or fq_codel.
+// Limits set to 0 are not enforced.
+type QueueConfig struct {
+	LimitPackets int     `json:"limitPackets"`
+	LimitBytes   int     `json:"limitBytes"`
+	LimitMs      float64 `json:"limitMs"`
+	Discipline   string  `json:"discipline"`
+	ECN          bool    `json:"ecn"`
+	// RED thresholds in packets
+	MinThreshold   int     `json:"minThreshold"`
+	MaxThreshold   int     `json:"maxThreshold"`
+	MaxProbability float64 `json:"maxProbability"`
+	// CoDel target and interval in milliseconds
+	Target   float64 `json:"target"`
+	Interval float64 `json:"interval"`
+}
+
+type QueueStats struct {
+	Packets   int
+	Bytes     int
+	TailDrops uint64
+	AQMDrops  uint64
+	ECNMarks  uint64
+}
+
+// netem style impairments. Probabilities are between 0 and 1.
+// Only one in every ReorderGap frames can be reordered.
+type Impairments struct {
+	Reorder    float64 `json:"reorder"`
+	ReorderGap int     `json:"reorderGap"`
+	Duplicate  float64 `json:"duplicate"`
+	Corrupt    float64 `json:"corrupt"`
+}
+
+// Gilbert-Elliott loss model. Each field is a probability between 0 and 1.
+type LossModel struct {
+	GoodToBad float64 `json:"goodToBad"`
+	BadToGood float64 `json:"badToGood"`
+	GoodLoss  float64 `json:"goodLoss"`
+	BadLoss   float64 `json:"badLoss"`
 }
 
 type SniffComponent struct {
@@ -57,3 +168,131 @@ type InterceptComponent struct {
 	From      string
 	Path      string
 }
+
+// Action of a timeline. Request holds the body accepted by the endpoint with the same name as Action.
+type TimelineAction struct {
+	OffsetMs float64         `json:"offsetMs"`
+	Action   string          `json:"action"`
+	Request  json.RawMessage `json:"request"`
+}
+
+type Timeline struct {
+	Id      string
+	Start   time.Time
+	Status  string
+	Actions []TimelineActionStatus
+}
+
+type TimelineActionStatus struct {
+	OffsetMs   float64
+	Action     string
+	Status     string
+	ExecutedAt time.Time
+	Error      string
+}
+
+// Active disruption of a component. Disruptions without a duration have DurationMs and RemainingMs set to 0.
+// Direction is empty when both directions are disrupted.
+type Disruption struct {
+	Id          string
+	Type        string
+	Components  []string
+	Direction   string
+	MachineId   string
+	Start       time.Time
+	DurationMs  float64
+	RemainingMs float64
+}
+
+// Active partition. Each group holds node ids.
+type Partition struct {
+	Id     string
+	Groups [][]string
+	Start  time.Time
+}
+
+// Timings of a flapping link in milliseconds. When UpMaxMs or DownMaxMs are greater than UpMs or DownMs,
+// each period is drawn uniformly between both values. Cycles set to 0 flap the link until the flap is stopped.
+type FlapConfig struct {
+	UpMs      float64 `json:"upMs"`
+	UpMaxMs   float64 `json:"upMaxMs,omitempty"`
+	DownMs    float64 `json:"downMs"`
+	DownMaxMs float64 `json:"downMaxMs,omitempty"`
+	Cycles    int     `json:"cycles"`
+}
+
+// Flap of a link, identified by the flapped component and direction
+type Flap struct {
+	Id              string
+	Type            string
+	Components      []string
+	Direction       string
+	MachineId       string
+	Config          FlapConfig
+	State           string
+	CompletedCycles int
+	Start           time.Time
+}
+
+// Targets of a ramp. Without a direction the latency and drop rate are split between both directions of the
+// link, like the values of a connect request, otherwise they are one-way like the fields of a LinkDirection.
+// Properties without a target keep their value. Curve is linear, the default, or exponential,
+// and StepMs sets how often the link is updated.
+type RampConfig struct {
+	DurationMs float64  `json:"durationMs"`
+	StepMs     float64  `json:"stepMs,omitempty"`
+	Curve      string   `json:"curve,omitempty"`
+	LatencyMs  *float64 `json:"latencyMs,omitempty"`
+	DropRate   *float64 `json:"dropRate,omitempty"`
+	Bandwidth  *int     `json:"bandwidth,omitempty"`
+}
+
+// Ramp of a link, identified by the ramped component and direction.
+// Progress goes from 0 to 1 and restarts when the ramp is reversed.
+type Ramp struct {
+	Id         string
+	Type       string
+	Components []string
+	Direction  string
+	MachineId  string
+	Config     RampConfig
+	State      string
+	Progress   float64
+	Start      time.Time
+}
+
+// Chaos run of the leader. Plan holds every fault the seed produced, in injection order.
+type ChaosRun struct {
+	Id             string
+	Seed           int64
+	Rate           float64
+	DurationMs     float64
+	Faults         []string
+	MinFaultMs     float64
+	MaxFaultMs     float64
+	LatencySpikeMs float64
+	LossBurst      float64
+	Start          time.Time
+	Status         string
+	Plan           []ChaosFault
+}
+
+// Fault of a chaos run. Link faults identify the link by the components it connects,
+// partitions hold the node groups instead.
+type ChaosFault struct {
+	Index      int
+	OffsetMs   float64
+	DurationMs float64
+	Type       string
+	Kind       string
+	Components []string
+	Groups     [][]string
+}
+
+type ChaosEvent struct {
+	Time     time.Time
+	OffsetMs float64
+	Action   string
+	Fault    ChaosFault
+	Error    string
+}
@@ -0,0 +1,6 @@
+package api
+
+type ApplyPartitionRequest struct {
+	Id     string     `json:"id"`
+	Groups [][]string `json:"groups"`
+}
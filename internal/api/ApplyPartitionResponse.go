@@ -0,0 +1,8 @@
+package api
+
+import apiErrors "github.com/David-Antunes/gone/api/Errors"
+
+type ApplyPartitionResponse struct {
+	Id    string          `json:"id"`
+	Error apiErrors.Error `json:"err"`
+}
@@ -1,15 +1,14 @@
 package api
 
-import "time"
+import connectApi "github.com/David-Antunes/gone/api/Connect"
 
+// Each direction is forwarded with its one-way properties. Weight is not part of a direction.
 type ConnectRouterToRouterRequest struct {
-	R1        string        `json:"r1"`
-	R2        string        `json:"r2"`
-	MachineID string        `json:"machineID"`
-	Latency   time.Duration `json:"latency"`
-	Jitter    float64       `json:"jitter"`
-	DropRate  float64       `json:"dropRate"`
-	Bandwidth int           `json:"bandwidth"`
-	Weight    int           `json:"weight"`
-	Propagate bool          `json:"propagate"`
+	R1        string                    `json:"r1"`
+	R2        string                    `json:"r2"`
+	MachineID string                    `json:"machineID"`
+	Up        *connectApi.LinkDirection `json:"up"`
+	Down      *connectApi.LinkDirection `json:"down"`
+	Weight    int                       `json:"weight"`
+	Propagate bool                      `json:"propagate"`
 }
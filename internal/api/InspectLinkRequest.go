@@ -0,0 +1,7 @@
+package api
+
+// Inspects the link between two components, as each machine numbers its links differently
+type InspectLinkRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
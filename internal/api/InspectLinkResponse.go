@@ -0,0 +1,11 @@
+package api
+
+import (
+	"github.com/David-Antunes/gone/api"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+)
+
+type InspectLinkResponse struct {
+	Link  api.LinkState   `json:"link"`
+	Error apiErrors.Error `json:"err"`
+}
@@ -0,0 +1,9 @@
+package api
+
+// Requests the properties of the link between two components. Upstream is the from -> to direction.
+type LinkDirectionsRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Only the directions owned by the machine are returned, the others are nil
+	Owned bool `json:"owned"`
+}
@@ -0,0 +1,13 @@
+package api
+
+import (
+	connectApi "github.com/David-Antunes/gone/api/Connect"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+)
+
+type LinkDirectionsResponse struct {
+	Upstream   *connectApi.LinkDirection `json:"upstream"`
+	Downstream *connectApi.LinkDirection `json:"downstream"`
+	Weight     int                       `json:"weight"`
+	Error      apiErrors.Error           `json:"err"`
+}
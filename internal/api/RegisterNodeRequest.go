@@ -1,8 +1,9 @@
 package api
 
 type RegisterNodeRequest struct {
-	Id        string `json:"id"`
-	Ip        string `json:"ip"`
-	Mac       string `json:"mac"`
-	MachineId string `json:"machineId"`
+	Id        string   `json:"id"`
+	Ip        string   `json:"ip"`
+	Mac       string   `json:"mac"`
+	MachineId string   `json:"machineId"`
+	DockerCmd []string `json:"dockerCmd"`
 }
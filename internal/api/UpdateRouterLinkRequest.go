@@ -0,0 +1,10 @@
+package api
+
+import connectApi "github.com/David-Antunes/gone/api/Connect"
+
+type UpdateRouterLinkRequest struct {
+	R1     string                    `json:"r1"`
+	R2     string                    `json:"r2"`
+	Props  *connectApi.LinkDirection `json:"props"`
+	Weight int                       `json:"weight"`
+}
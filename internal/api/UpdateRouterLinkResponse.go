@@ -0,0 +1,9 @@
+package api
+
+import api "github.com/David-Antunes/gone/api/Errors"
+
+type UpdateRouterLinkResponse struct {
+	R1    string    `json:"r1"`
+	R2    string    `json:"r2"`
+	Error api.Error `json:"err"`
+}
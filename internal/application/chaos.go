@@ -0,0 +1,118 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	connectApi "github.com/David-Antunes/gone/api/Connect"
+	internalApi "github.com/David-Antunes/gone/internal/api"
+	"github.com/David-Antunes/gone/internal/chaos"
+	"github.com/David-Antunes/gone/internal/topology"
+)
+
+// Links and nodes a chaos run can target. When no component is given every node and link of the emulation is targeted.
+// Links between routers of different machines can't be disrupted, so they are never targeted.
+func (app *Leader) ChaosTargets(nodes []string, bridges []string, routers [][]string) (chaos.Targets, error) {
+	targets := chaos.Targets{
+		Links: make([]chaos.Target, 0),
+		Nodes: make([]string, 0),
+	}
+
+	if len(nodes) == 0 && len(bridges) == 0 && len(routers) == 0 {
+		for _, n := range app.topo.GetNodes() {
+			targets.Nodes = append(targets.Nodes, n.ID())
+		}
+		for _, link := range app.topo.GetBiLinks() {
+			targets.Links = append(targets.Links, chaosLinkTarget(link))
+		}
+		return targets, nil
+	}
+
+	for _, id := range nodes {
+		n, ok := app.topo.GetNode(id)
+		if !ok {
+			return chaos.Targets{}, errors.New("invalid node id: " + id)
+		}
+		targets.Nodes = append(targets.Nodes, id)
+		if n.Link != nil {
+			targets.Links = append(targets.Links, chaosLinkTarget(n.Link))
+		}
+	}
+	for _, id := range bridges {
+		b, ok := app.topo.GetBridge(id)
+		if !ok {
+			return chaos.Targets{}, errors.New("invalid bridge id: " + id)
+		}
+		if b.RouterLink == nil {
+			return chaos.Targets{}, errors.New(id + " is not connected to a router")
+		}
+		targets.Links = append(targets.Links, chaosLinkTarget(b.RouterLink))
+	}
+	for _, pair := range routers {
+		if len(pair) != 2 {
+			return chaos.Targets{}, errors.New("router links are given as pairs of routers")
+		}
+		r1, ok := app.topo.GetRouter(pair[0])
+		if !ok {
+			return chaos.Targets{}, errors.New("invalid router id: " + pair[0])
+		}
+		r2, ok := app.topo.GetRouter(pair[1])
+		if !ok {
+			return chaos.Targets{}, errors.New("invalid router id: " + pair[1])
+		}
+		link, ok := r1.RouterLinks[r2.ID()]
+		if !ok {
+			return chaos.Targets{}, errors.New(pair[0] + " and " + pair[1] + " are not connected")
+		}
+		if r1.MachineId != r2.MachineId {
+			return chaos.Targets{}, errors.New("links between routers of different machines can't be targeted")
+		}
+		targets.Links = append(targets.Links, chaosLinkTarget(link))
+	}
+	return targets, nil
+}
+
+// Links are identified by the components they connect in the upstream direction
+func chaosLinkTarget(link *topology.BiLink) chaos.Target {
+	components := []string{link.ConnectsTo.From.ID(), link.ConnectsTo.To.ID()}
+	switch link.ConnectsTo.From.(type) {
+	case *topology.Node:
+		return chaos.Target{Kind: chaos.NodeTarget, Components: components}
+	case *topology.Bridge:
+		return chaos.Target{Kind: chaos.BridgeTarget, Components: components}
+	default:
+		return chaos.Target{Kind: chaos.RoutersTarget, Components: components}
+	}
+}
+
+// Properties of the link between two components of any machine. Upstream is the from -> to direction.
+func (app *Leader) LinkDirectionsBetween(from string, to string) (*connectApi.LinkDirection, *connectApi.LinkDirection, error) {
+	link, err := findBiLinkBetween(app.topo, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	machineId := componentMachine(link.ConnectsTo.From)
+	if machineId == app.GetMachineId() {
+		return linkDirectionsBetween(app.topo, from, to)
+	}
+
+	resp, err := app.cl.SendMsg(machineId, &internalApi.LinkDirectionsRequest{
+		From: from,
+		To:   to,
+	}, "linkDirectionsRemote")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &internalApi.LinkDirectionsResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return nil, nil, errors.New(req.Error.ErrMsg)
+	}
+	return req.Upstream, req.Downstream, nil
+}
@@ -0,0 +1,138 @@
+package application
+
+import (
+	"errors"
+	"github.com/David-Antunes/gone/internal/docker"
+	"github.com/David-Antunes/gone/internal/network"
+	"github.com/David-Antunes/gone/internal/topology"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+var crashLog = log.New(os.Stdout, "CRASH INFO: ", log.Ltime)
+
+// Owner of the disruptions of crashed nodes
+const crashDisruption = "crash"
+
+type crash struct {
+	start time.Time
+	timer *time.Timer
+	// Set while docker stops or starts the container, which happens without holding the registry lock
+	busy bool
+}
+
+// Crashed containers of this machine. While a node is down, the frames sent to it are dropped by its bridge link.
+type crashRegistry struct {
+	sync.Mutex
+	crashes map[string]*crash
+}
+
+func newCrashRegistry() *crashRegistry {
+	return &crashRegistry{
+		Mutex:   sync.Mutex{},
+		crashes: make(map[string]*crash),
+	}
+}
+
+// Stops the container of a local node. The node is restarted once restartAfter elapses, unless it is 0.
+func (reg *crashRegistry) crash(topo *topology.Topology, dm *docker.DockerManager, id string, kill bool, restartAfter time.Duration) error {
+	reg.Lock()
+	if _, ok := reg.crashes[id]; ok {
+		reg.Unlock()
+		return errors.New(id + " is already crashed")
+	}
+	n, ok := topo.GetNode(id)
+	if !ok {
+		reg.Unlock()
+		return errors.New("invalid node id")
+	}
+	c := &crash{
+		start: time.Now(),
+		timer: nil,
+		busy:  true,
+	}
+	reg.crashes[id] = c
+	reg.Unlock()
+
+	if err := dm.Crash(id, kill); err != nil {
+		reg.Lock()
+		delete(reg.crashes, id)
+		reg.Unlock()
+		return err
+	}
+
+	if n.Link != nil {
+		n.Link.NetworkBILink.DisruptDirection(network.Downstream, crashDisruption)
+	}
+
+	reg.Lock()
+	defer reg.Unlock()
+	c.start = time.Now()
+	c.busy = false
+	if restartAfter > 0 {
+		c.timer = time.AfterFunc(restartAfter, func() {
+			if err := reg.restart(topo, dm, id); err != nil {
+				crashLog.Println("Could not restart", id+":", err)
+			}
+		})
+	}
+	crashLog.Println("Crashed", id)
+	return nil
+}
+
+// Starts the container of a crashed node with its previous addresses and reconnects it to its bridge link
+func (reg *crashRegistry) restart(topo *topology.Topology, dm *docker.DockerManager, id string) error {
+	reg.Lock()
+	c, ok := reg.crashes[id]
+	if !ok {
+		reg.Unlock()
+		return errors.New(id + " is not crashed")
+	}
+	if c.busy {
+		reg.Unlock()
+		return errors.New(id + " is being crashed or restarted")
+	}
+	n, ok := topo.GetNode(id)
+	if !ok {
+		reg.Unlock()
+		return errors.New("invalid node id")
+	}
+	mac, ip, ok := dm.GetAddresses(id)
+	if !ok {
+		reg.Unlock()
+		return errors.New("container not found")
+	}
+	c.busy = true
+	reg.Unlock()
+
+	if err := dm.Restart(id); err != nil {
+		reg.Lock()
+		c.busy = false
+		reg.Unlock()
+		return err
+	}
+
+	reg.Lock()
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	delete(reg.crashes, id)
+	reg.Unlock()
+
+	if err := topo.ReattachNode(id, mac); err != nil {
+		return err
+	}
+	if err := dm.BootstrapContainer(id); err != nil {
+		return err
+	}
+	if err := dm.PropagateArp(ip, mac); err != nil {
+		return err
+	}
+	if n.Link != nil {
+		n.Link.NetworkBILink.StopDisruptDirection(network.Downstream, crashDisruption)
+	}
+	crashLog.Println("Restarted", id, "after", time.Since(c.start))
+	return nil
+}
@@ -0,0 +1,156 @@
+package application
+
+import (
+	"github.com/David-Antunes/gone/api"
+	"github.com/David-Antunes/gone/internal/network"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var disruptionLog = log.New(os.Stdout, "DISRUPTION INFO: ", log.Ltime)
+
+const (
+	NodeDisruption    = "node"
+	BridgeDisruption  = "bridge"
+	RoutersDisruption = "routers"
+)
+
+type disruption struct {
+	id         string
+	kind       string
+	components []string
+	direction  string
+	start      time.Time
+	duration   time.Duration
+	timer      *time.Timer
+}
+
+// Disruptions of the components of this machine. Timed disruptions are restored by the machine that owns the component.
+type disruptionRegistry struct {
+	sync.Mutex
+	disruptions map[string]*disruption
+}
+
+func newDisruptionRegistry() *disruptionRegistry {
+	return &disruptionRegistry{
+		Mutex:       sync.Mutex{},
+		disruptions: make(map[string]*disruption),
+	}
+}
+
+// The routers of a link can be given in any order, so their directions are kept relative to the first router
+// of the request and the id only depends on the link.
+func disruptionId(kind string, direction string, components ...string) string {
+	sorted := append([]string{}, components...)
+	if kind == RoutersDisruption && !sort.StringsAreSorted(sorted) {
+		sort.Strings(sorted)
+		direction = reverseDirection(direction)
+	}
+	id := kind + ":" + strings.Join(sorted, ":")
+	if direction != "" {
+		id += ":" + direction
+	}
+	return id
+}
+
+func reverseDirection(direction string) string {
+	switch direction {
+	case network.Upstream:
+		return network.Downstream
+	case network.Downstream:
+		return network.Upstream
+	default:
+		return direction
+	}
+}
+
+// Registers a disruption. restore is called once duration elapses, unless the duration is 0.
+func (reg *disruptionRegistry) add(kind string, components []string, direction string, duration time.Duration, restore func() error) {
+	reg.Lock()
+	defer reg.Unlock()
+
+	id := disruptionId(kind, direction, components...)
+	if previous, ok := reg.disruptions[id]; ok && previous.timer != nil {
+		previous.timer.Stop()
+	}
+	d := &disruption{
+		id:         id,
+		kind:       kind,
+		components: components,
+		direction:  direction,
+		start:      time.Now(),
+		duration:   duration,
+		timer:      nil,
+	}
+	if duration > 0 {
+		d.timer = time.AfterFunc(duration, func() {
+			// A stopped timer can still fire, so a replaced or removed disruption is not restored
+			if !reg.active(d) {
+				return
+			}
+			if err := restore(); err != nil {
+				disruptionLog.Println("Failed to restore", id+":", err)
+			} else {
+				disruptionLog.Println("Restored", id)
+			}
+		})
+	}
+	reg.disruptions[id] = d
+}
+
+func (reg *disruptionRegistry) active(d *disruption) bool {
+	reg.Lock()
+	defer reg.Unlock()
+	return reg.disruptions[d.id] == d
+}
+
+// Removes the disruption of a direction. Without a direction every disruption of the components is removed.
+func (reg *disruptionRegistry) remove(kind string, direction string, components ...string) {
+	reg.Lock()
+	defer reg.Unlock()
+
+	ids := []string{disruptionId(kind, direction, components...)}
+	if direction == "" {
+		ids = append(ids, disruptionId(kind, network.Upstream, components...), disruptionId(kind, network.Downstream, components...))
+	}
+	for _, id := range ids {
+		if d, ok := reg.disruptions[id]; ok {
+			if d.timer != nil {
+				d.timer.Stop()
+			}
+			delete(reg.disruptions, id)
+		}
+	}
+}
+
+func (reg *disruptionRegistry) list(machineId string) []api.Disruption {
+	reg.Lock()
+	defer reg.Unlock()
+
+	list := make([]api.Disruption, 0, len(reg.disruptions))
+	for _, d := range reg.disruptions {
+		remaining := time.Duration(0)
+		if d.duration > 0 {
+			remaining = max(time.Until(d.start.Add(d.duration)), 0)
+		}
+		list = append(list, api.Disruption{
+			Id:          d.id,
+			Type:        d.kind,
+			Components:  d.components,
+			Direction:   d.direction,
+			MachineId:   machineId,
+			Start:       d.start,
+			DurationMs:  toMilliseconds(d.duration),
+			RemainingMs: toMilliseconds(remaining),
+		})
+	}
+	return list
+}
+
+func toMilliseconds(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
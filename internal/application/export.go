@@ -0,0 +1,152 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	connectApi "github.com/David-Antunes/gone/api/Connect"
+	topologyApi "github.com/David-Antunes/gone/api/Topology"
+	internalApi "github.com/David-Antunes/gone/internal/api"
+	"github.com/David-Antunes/gone/internal/network"
+	"github.com/David-Antunes/gone/internal/topology"
+	"sort"
+)
+
+func (app *Leader) OwnedLinkDirections(from string, to string) (*connectApi.LinkDirection, *connectApi.LinkDirection, int, error) {
+	return ownedLinkDirections(app.topo, from, to)
+}
+
+func (app *Leader) ownedLinkDirectionsIn(machineId string, from string, to string) (*connectApi.LinkDirection, *connectApi.LinkDirection, int, error) {
+	if machineId == app.GetMachineId() {
+		return app.OwnedLinkDirections(from, to)
+	}
+
+	resp, err := app.cl.SendMsg(machineId, &internalApi.LinkDirectionsRequest{
+		From:  from,
+		To:    to,
+		Owned: true,
+	}, "linkDirectionsRemote")
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &internalApi.LinkDirectionsResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return nil, nil, 0, errors.New(req.Error.ErrMsg)
+	}
+	return req.Upstream, req.Downstream, req.Weight, nil
+}
+
+// Describes the running emulation as a document that can be applied to rebuild it.
+// The properties of each link direction are fetched from the machine that owns it.
+func (app *Leader) Export() (topologyApi.Document, error) {
+	doc := topologyApi.Document{
+		Nodes:     make([]topologyApi.NodeSpec, 0),
+		Bridges:   make([]topologyApi.ComponentSpec, 0),
+		Routers:   make([]topologyApi.ComponentSpec, 0),
+		Links:     make([]topologyApi.LinkSpec, 0),
+		Propagate: true,
+		Unpause:   false,
+	}
+	links := make([][2]topology.Component, 0)
+
+	for _, n := range app.topo.GetNodes() {
+		dockerCmd, _ := app.dm.GetDockerCmd(n.ID())
+		mac, ip, _ := app.dm.GetAddresses(n.ID())
+		doc.Nodes = append(doc.Nodes, topologyApi.NodeSpec{
+			Name:      n.ID(),
+			MachineId: n.MachineId,
+			DockerCmd: dockerCmd,
+			Mac:       mac,
+			Ip:        ip,
+		})
+		if n.Bridge != nil {
+			links = append(links, [2]topology.Component{n, n.Bridge})
+		}
+	}
+
+	for _, b := range app.topo.GetBridges() {
+		doc.Bridges = append(doc.Bridges, topologyApi.ComponentSpec{
+			Name:      b.ID(),
+			MachineId: b.MachineId,
+		})
+		if b.Router != nil {
+			links = append(links, [2]topology.Component{b, b.Router})
+		}
+	}
+
+	for _, r := range app.topo.GetRouters() {
+		doc.Routers = append(doc.Routers, topologyApi.ComponentSpec{
+			Name:      r.ID(),
+			MachineId: r.MachineId,
+		})
+		// Each router link is seen by both routers
+		for id, other := range r.ConnectedRouters {
+			if r.ID() < id {
+				links = append(links, [2]topology.Component{r, other})
+			}
+		}
+	}
+
+	for _, link := range links {
+		spec, err := app.exportLink(link[0], link[1])
+		if err != nil {
+			return topologyApi.Document{}, err
+		}
+		doc.Links = append(doc.Links, spec)
+	}
+
+	sort.Slice(doc.Nodes, func(i, j int) bool { return doc.Nodes[i].Name < doc.Nodes[j].Name })
+	sort.Slice(doc.Bridges, func(i, j int) bool { return doc.Bridges[i].Name < doc.Bridges[j].Name })
+	sort.Slice(doc.Routers, func(i, j int) bool { return doc.Routers[i].Name < doc.Routers[j].Name })
+	sort.Slice(doc.Links, func(i, j int) bool {
+		if doc.Links[i].From != doc.Links[j].From {
+			return doc.Links[i].From < doc.Links[j].From
+		}
+		return doc.Links[i].To < doc.Links[j].To
+	})
+	return doc, nil
+}
+
+// Both directions of a link are exported as they are, the symmetric properties only hold valid defaults
+func (app *Leader) exportLink(from topology.Component, to topology.Component) (topologyApi.LinkSpec, error) {
+	up, down, weight, err := app.ownedLinkDirectionsIn(componentMachine(from), from.ID(), to.ID())
+	if err != nil {
+		return topologyApi.LinkSpec{}, err
+	}
+	// Routers in different machines own the direction leaving them
+	if componentMachine(to) != componentMachine(from) {
+		_, down, _, err = app.ownedLinkDirectionsIn(componentMachine(to), from.ID(), to.ID())
+		if err != nil {
+			return topologyApi.LinkSpec{}, err
+		}
+	}
+	if up == nil || down == nil {
+		return topologyApi.LinkSpec{}, errors.New("could not export link between " + from.ID() + " and " + to.ID())
+	}
+
+	return topologyApi.LinkSpec{
+		From: from.ID(),
+		To:   to.ID(),
+		LinkProperties: topologyApi.LinkProperties{
+			Latency:   *up.Latency + *down.Latency,
+			Jitter:    *up.Jitter + *down.Jitter,
+			DropRate:  min(*up.DropRate+*down.DropRate, 1),
+			Bandwidth: max(*up.Bandwidth, *down.Bandwidth),
+			Weight:    weight,
+		},
+		Upstream:   up,
+		Downstream: down,
+	}, nil
+}
+
+// Properties of a link direction as Export describes them, so documents can be compared with the running emulation
+func ExportLinkDirection(props network.LinkProps) *connectApi.LinkDirection {
+	return toLinkDirection(props)
+}
@@ -0,0 +1,108 @@
+package application
+
+import (
+	"errors"
+	"github.com/David-Antunes/gone/api"
+	"github.com/David-Antunes/gone/internal/network"
+	"sort"
+	"sync"
+	"time"
+)
+
+type flap struct {
+	id         string
+	kind       string
+	components []string
+	direction  string
+	start      time.Time
+	flap       *network.Flap
+}
+
+// Flaps of the links of this machine. A flap is identified by its link and direction, so ids are unique in the cluster.
+type flapRegistry struct {
+	sync.Mutex
+	flaps map[string]*flap
+}
+
+func newFlapRegistry() *flapRegistry {
+	return &flapRegistry{
+		Mutex: sync.Mutex{},
+		flaps: make(map[string]*flap),
+	}
+}
+
+// Starts flapping a link. A finished or stopped flap of the same link is replaced.
+func (reg *flapRegistry) start(kind string, components []string, direction string, link *network.BiLink, linkDirection string, props network.FlapProps) (string, error) {
+	reg.Lock()
+	defer reg.Unlock()
+
+	id := "flap:" + disruptionId(kind, direction, components...)
+	if f, ok := reg.flaps[id]; ok && f.flap.Running() {
+		return "", errors.New(id + " is already running")
+	}
+	reg.flaps[id] = &flap{
+		id:         id,
+		kind:       kind,
+		components: components,
+		direction:  direction,
+		start:      time.Now(),
+		flap:       network.StartFlap(id, link, linkDirection, props.Seeded(id)),
+	}
+	return id, nil
+}
+
+func (reg *flapRegistry) stop(id string) error {
+	reg.Lock()
+	defer reg.Unlock()
+
+	f, ok := reg.flaps[id]
+	if !ok {
+		return errors.New("invalid flap id: " + id)
+	}
+	if !f.flap.Stop() {
+		return errors.New(id + " is not running")
+	}
+	return nil
+}
+
+func (reg *flapRegistry) contains(id string) bool {
+	reg.Lock()
+	defer reg.Unlock()
+	_, ok := reg.flaps[id]
+	return ok
+}
+
+func (reg *flapRegistry) list(machineId string) []api.Flap {
+	reg.Lock()
+	defer reg.Unlock()
+
+	list := make([]api.Flap, 0, len(reg.flaps))
+	for _, f := range reg.flaps {
+		state, completed := f.flap.Status()
+		list = append(list, api.Flap{
+			Id:              f.id,
+			Type:            f.kind,
+			Components:      f.components,
+			Direction:       f.direction,
+			MachineId:       machineId,
+			Config:          toFlapConfig(f.flap.GetProps()),
+			State:           state,
+			CompletedCycles: completed,
+			Start:           f.start,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Start.Before(list[j].Start)
+	})
+	return list
+}
+
+func toFlapConfig(props network.FlapProps) api.FlapConfig {
+	return api.FlapConfig{
+		UpMs:      toMilliseconds(props.Up),
+		UpMaxMs:   toMilliseconds(props.UpMax),
+		DownMs:    toMilliseconds(props.Down),
+		DownMaxMs: toMilliseconds(props.DownMax),
+		Cycles:    props.Cycles,
+	}
+}
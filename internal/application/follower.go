@@ -7,19 +7,23 @@ import (
 	"github.com/David-Antunes/gone-proxy/xdp"
 	"github.com/David-Antunes/gone/api"
 	addApi "github.com/David-Antunes/gone/api/Add"
+	connectApi "github.com/David-Antunes/gone/api/Connect"
 	disconnectApi "github.com/David-Antunes/gone/api/Disconnect"
 	opApi "github.com/David-Antunes/gone/api/Operations"
+	updateApi "github.com/David-Antunes/gone/api/Update"
 	"github.com/David-Antunes/gone/internal"
 	internalApi "github.com/David-Antunes/gone/internal/api"
 	"github.com/David-Antunes/gone/internal/cluster"
 	"github.com/David-Antunes/gone/internal/docker"
 	"github.com/David-Antunes/gone/internal/graphDB"
+	"github.com/David-Antunes/gone/internal/metrics"
 	"github.com/David-Antunes/gone/internal/network"
 	"github.com/David-Antunes/gone/internal/proxy"
 	redirecttraffic "github.com/David-Antunes/gone/internal/redirect-traffic"
 	"github.com/David-Antunes/gone/internal/topology"
 	"net"
 	"net/http"
+	"time"
 )
 
 type Follower struct {
@@ -30,6 +34,11 @@ type Follower struct {
 	icm             *cluster.InterCommunicationManager
 	rm              *LocalRttManager
 	redirectManager *redirecttraffic.RedirectManager
+	disruptions     *disruptionRegistry
+	partitions      *partitionRegistry
+	flaps           *flapRegistry
+	crashes         *crashRegistry
+	ramps           *rampRegistry
 }
 
 func NewFollower(cl *cluster.Cluster, dm *docker.DockerManager, proxy *proxy.Proxy, icm *cluster.InterCommunicationManager, rm *LocalRttManager) *Follower {
@@ -41,6 +50,11 @@ func NewFollower(cl *cluster.Cluster, dm *docker.DockerManager, proxy *proxy.Pro
 		icm:             icm,
 		rm:              rm,
 		redirectManager: redirecttraffic.NewRedirectManager(),
+		disruptions:     newDisruptionRegistry(),
+		partitions:      newPartitionRegistry(),
+		flaps:           newFlapRegistry(),
+		crashes:         newCrashRegistry(),
+		ramps:           newRampRegistry(),
 	}
 }
 
@@ -96,15 +110,15 @@ func (app *Follower) clearSniffLink(id string) error {
 			bilink := sniffer.Component.(*topology.BiLink).NetworkBILink
 
 			// Convert to individual Link
-			bilink.Left.GetShaper().Pause()
+			bilink.Left.Pause()
 			l := bilink.Left.GetShaper().(*network.SniffShaper).ConvertToNetworkShaper()
 			bilink.Left.SetShaper(l)
-			l.Unpause()
+			bilink.Left.Unpause()
 
-			bilink.Right.GetShaper().Pause()
+			bilink.Right.Pause()
 			r := bilink.Right.GetShaper().(*network.SniffShaper).ConvertToNetworkShaper()
-			bilink.Right.SetShaper(l)
-			r.Unpause()
+			bilink.Right.SetShaper(r)
+			bilink.Right.Unpause()
 		}
 	}
 	return nil
@@ -123,10 +137,10 @@ func (app *Follower) clearInterceptLink(id string) error {
 			// Convert to network link
 			link := intercept.Component.(*topology.Link).NetworkLink
 
-			link.GetShaper().Pause()
+			link.Pause()
 			l := link.GetShaper().(*network.InterceptShaper).ConvertToNetworkShaper()
 			link.SetShaper(l)
-			l.Unpause()
+			link.Unpause()
 		}
 	}
 	return nil
@@ -237,7 +251,7 @@ func (app *Follower) AddNode(machineId string, dockerCmd []string) (string, stri
 	if err != nil {
 		return "", "", "", err
 	}
-	err = app.dm.RegisterContainer(machineId, id, mac, ip)
+	err = app.dm.RegisterContainer(machineId, id, mac, ip, dockerCmd)
 	if err != nil {
 		return "", "", "", err
 	}
@@ -257,6 +271,7 @@ func (app *Follower) AddNode(machineId string, dockerCmd []string) (string, stri
 		Ip:        ip,
 		Mac:       mac,
 		MachineId: machineId,
+		DockerCmd: dockerCmd,
 	}, http.MethodPost, "registerNode")
 
 	_, err = app.topo.RegisterNode(id, mac, machineId)
@@ -267,12 +282,12 @@ func (app *Follower) AddNode(machineId string, dockerCmd []string) (string, stri
 	return id, mac, ip, nil
 }
 
-func (app *Follower) RegisterNode(id string, mac string, ip string, machineId string) error {
+func (app *Follower) RegisterNode(id string, mac string, ip string, machineId string, dockerCmd []string) error {
 
 	if !app.cl.Contains(machineId) {
 		return errors.New("invalid machine id")
 	}
-	err := app.dm.RegisterContainer(machineId, id, mac, ip)
+	err := app.dm.RegisterContainer(machineId, id, mac, ip, dockerCmd)
 	if err != nil {
 		return err
 	}
@@ -322,9 +337,9 @@ func (app *Follower) AddRouter(machineId string, id string) (string, error) {
 
 }
 
-func (app *Follower) ConnectNodeToBridge(nodeID string, bridgeID string, linkProps network.LinkProps) error {
+func (app *Follower) ConnectNodeToBridge(nodeID string, bridgeID string, up network.LinkProps, down network.LinkProps) error {
 
-	_, err := app.topo.ConnectNodeToBridge(nodeID, bridgeID, linkProps)
+	_, err := app.topo.ConnectNodeToBridge(nodeID, bridgeID, up, down)
 
 	if err != nil {
 		return err
@@ -341,9 +356,9 @@ func (app *Follower) ConnectNodeToBridge(nodeID string, bridgeID string, linkPro
 	return err
 }
 
-func (app *Follower) ConnectBridgeToRouter(bridgeID string, routerID string, linkProps network.LinkProps) error {
+func (app *Follower) ConnectBridgeToRouter(bridgeID string, routerID string, up network.LinkProps, down network.LinkProps) error {
 
-	_, err := app.topo.ConnectBridgeToRouter(bridgeID, routerID, linkProps)
+	_, err := app.topo.ConnectBridgeToRouter(bridgeID, routerID, up, down)
 
 	if err != nil {
 		return err
@@ -358,7 +373,7 @@ func (app *Follower) ConnectBridgeToRouter(bridgeID string, routerID string, lin
 	return err
 }
 
-func (app *Follower) ConnectRouterToRouterLocally(router1ID string, router2ID string, linkProps network.LinkProps) error {
+func (app *Follower) ConnectRouterToRouterLocally(router1ID string, router2ID string, up network.LinkProps, down network.LinkProps) error {
 
 	if router1ID == router2ID {
 		return errors.New("can't connect a router to itself")
@@ -375,17 +390,17 @@ func (app *Follower) ConnectRouterToRouterLocally(router1ID string, router2ID st
 		return errors.New("router not found")
 	}
 
-	link, err := app.topo.ConnectRouterToRouterLocal(router1ID, router2ID, linkProps)
+	link, err := app.topo.ConnectRouterToRouterLocal(router1ID, router2ID, up, down)
 
 	if err != nil {
 		return err
 	}
-	graphDB.AddPath(router1ID, router2ID, link.ID(), linkProps.Weight)
+	graphDB.AddPath(router1ID, router2ID, link.ID(), up.Weight)
 	app.PropagateNewRoutes(r1)
 	return nil
 }
 
-func (app *Follower) ConnectRouterToRouter(router1ID string, router2ID string, machineID string, linkProps network.LinkProps, propagate bool) error {
+func (app *Follower) ConnectRouterToRouter(router1ID string, router2ID string, machineID string, up network.LinkProps, down network.LinkProps, propagate bool) error {
 
 	if router1ID == router2ID {
 		return errors.New("can't connect a router to itself")
@@ -416,28 +431,28 @@ func (app *Follower) ConnectRouterToRouter(router1ID string, router2ID string, m
 
 	if r1.MachineId == app.GetMachineId() {
 		if r2.MachineId == app.GetMachineId() {
-			l, err := app.topo.ConnectRouterToRouterLocal(router1ID, router2ID, linkProps)
+			l, err := app.topo.ConnectRouterToRouterLocal(router1ID, router2ID, up, down)
 
 			if err != nil {
 				return err
 			}
 
-			graphDB.AddPath(router1ID, router2ID, l.ID(), linkProps.Weight)
+			graphDB.AddPath(router1ID, router2ID, l.ID(), up.Weight)
 			app.TradeRoutes(r1, r2)
 			if propagate {
 				app.PropagateNewRoutes(r1)
 			}
 			return nil
 		} else {
-			return app.connectRouterToRouterRemote(r1, r2, linkProps, propagate)
+			return app.connectRouterToRouterRemote(r1, r2, up, down, propagate)
 		}
 	} else {
-		return app.RedirectConnection(r1, r2, linkProps, propagate)
+		return app.RedirectConnection(r1, r2, up, down, propagate)
 
 	}
 }
 
-func (app *Follower) connectRouterToRouterRemote(r1 *topology.Router, r2 *topology.Router, linkProps network.LinkProps, propagate bool) error {
+func (app *Follower) connectRouterToRouterRemote(r1 *topology.Router, r2 *topology.Router, up network.LinkProps, down network.LinkProps, propagate bool) error {
 	app.topo.Lock()
 	if _, ok := r1.ConnectedRouters[r2.ID()]; ok {
 		return errors.New(r1.ID() + " is already connected to " + r2.ID())
@@ -453,7 +468,8 @@ func (app *Follower) connectRouterToRouterRemote(r1 *topology.Router, r2 *topolo
 	// Temporary Fix
 	app.icm.AddMachine(conn, r2.MachineId)
 	app.icm.AddConnection(r2.ID(), d, r2.MachineId, r1.ID(), r1.NetworkRouter)
-	toLink := network.CreateLink(router1Channel, nil, linkProps)
+	up = up.Seeded(r1.ID() + "-RemoteLink-" + r2.ID())
+	toLink := network.CreateLink(router1Channel, nil, up)
 	topoLink := &topology.Link{
 		Id:          r1.ID() + "-RemoteLink-" + r2.ID(),
 		NetworkLink: toLink,
@@ -473,7 +489,7 @@ func (app *Follower) connectRouterToRouterRemote(r1 *topology.Router, r2 *topolo
 	r1.AddRouter(r2, BiLink)
 	r2.AddRouter(r1, BiLink)
 
-	s := network.CreateRemoteShaper(r2.ID(), r1.ID(), router1Channel, app.icm.GetoutQueue(), linkProps)
+	s := network.CreateRemoteShaper(r2.ID(), r1.ID(), router1Channel, app.icm.GetoutQueue(), up)
 	s.SetDelay(d)
 	toLink.SetShaper(s)
 	toLink.Start()
@@ -481,11 +497,9 @@ func (app *Follower) connectRouterToRouterRemote(r1 *topology.Router, r2 *topolo
 		R1:        r2.ID(),
 		R2:        r1.ID(),
 		MachineID: r1.MachineId,
-		Latency:   linkProps.Latency * 2.0,
-		Jitter:    linkProps.Jitter * 2.0,
-		DropRate:  linkProps.DropRate * 2.0,
-		Bandwidth: linkProps.Bandwidth * 8,
-		Weight:    linkProps.Weight,
+		Up:        toLinkDirection(down),
+		Down:      toLinkDirection(up),
+		Weight:    up.Weight,
 	}
 
 	app.topo.Unlock()
@@ -495,7 +509,7 @@ func (app *Follower) connectRouterToRouterRemote(r1 *topology.Router, r2 *topolo
 		return errors.New("couldn't contact machine")
 	}
 
-	graphDB.AddPath(r1.ID(), r2.ID(), BiLink.ID(), linkProps.Weight)
+	graphDB.AddPath(r1.ID(), r2.ID(), BiLink.ID(), up.Weight)
 
 	app.TradeRoutesRemote(r1, r2)
 	if propagate {
@@ -549,6 +563,7 @@ func (app *Follower) ApplyConnectRouterToRouterRemote(router1ID string, router2I
 
 	app.icm.AddConnection(r2.ID(), d, r2.MachineId, r1.ID(), r1.NetworkRouter)
 
+	linkProps = linkProps.Seeded(r1.ID() + "-RemoteLink-" + r2.ID())
 	toLink := network.CreateLink(router1Channel, nil, linkProps)
 	topoLink := &topology.Link{
 		Id:          r1.ID() + "-RemoteLink-" + r2.ID(),
@@ -578,17 +593,15 @@ func (app *Follower) ApplyConnectRouterToRouterRemote(router1ID string, router2I
 	return nil
 }
 
-func (app *Follower) RedirectConnection(r1 *topology.Router, r2 *topology.Router, linkProps network.LinkProps, propagate bool) error {
+func (app *Follower) RedirectConnection(r1 *topology.Router, r2 *topology.Router, up network.LinkProps, down network.LinkProps, propagate bool) error {
 
 	b := &internalApi.ConnectRouterToRouterRequest{
 		R1:        r1.ID(),
 		R2:        r2.ID(),
 		MachineID: r1.MachineId,
-		Latency:   linkProps.Latency * 2.0,
-		Jitter:    linkProps.Jitter * 2.0,
-		DropRate:  linkProps.DropRate * 2.0,
-		Bandwidth: linkProps.Bandwidth * 8,
-		Weight:    linkProps.Weight,
+		Up:        toLinkDirection(up),
+		Down:      toLinkDirection(down),
+		Weight:    up.Weight,
 		Propagate: propagate,
 	}
 	resp, err := app.cl.SendMsg(r1.MachineId, b, "connectRouterToRouter")
@@ -2065,17 +2078,78 @@ func (app *Follower) Unpause(id string, all bool) error {
 		}
 	}
 }
-func (app *Follower) DisruptNode(id string) error {
+func (app *Follower) CrashNode(id string, kill bool, restartAfter time.Duration) error {
+	if n, ok := app.topo.GetNode(id); ok {
+		if n.MachineId == app.GetMachineId() {
+			return app.crashes.crash(app.topo, app.dm, id, kill, restartAfter)
+		} else {
+
+			resp, err := app.cl.SendMsg(n.MachineId, &opApi.CrashNodeRequest{Node: id, Kill: kill, RestartMs: toMilliseconds(restartAfter)}, "crashNode")
+			if err != nil {
+				return err
+			}
+
+			d := json.NewDecoder(resp.Body)
+			req := &opApi.CrashNodeResponse{}
+			err = d.Decode(&req)
+
+			if err != nil {
+				return err
+			}
+
+			if req.Error.ErrCode != 0 {
+				return errors.New(req.Error.ErrMsg)
+			}
+			return nil
+		}
+	} else {
+		return errors.New("invalid node id")
+	}
+}
+
+func (app *Follower) RestartNode(id string) error {
 	if n, ok := app.topo.GetNode(id); ok {
 		if n.MachineId == app.GetMachineId() {
-			if n.Link.NetworkBILink.Disrupt() {
+			return app.crashes.restart(app.topo, app.dm, id)
+		} else {
+
+			resp, err := app.cl.SendMsg(n.MachineId, &opApi.RestartNodeRequest{Node: id}, "restartNode")
+			if err != nil {
+				return err
+			}
+
+			d := json.NewDecoder(resp.Body)
+			req := &opApi.RestartNodeResponse{}
+			err = d.Decode(&req)
+
+			if err != nil {
+				return err
+			}
+
+			if req.Error.ErrCode != 0 {
+				return errors.New(req.Error.ErrMsg)
+			}
+			return nil
+		}
+	} else {
+		return errors.New("invalid node id")
+	}
+}
+
+func (app *Follower) DisruptNode(id string, direction string, duration time.Duration) error {
+	if n, ok := app.topo.GetNode(id); ok {
+		if n.MachineId == app.GetMachineId() {
+			if n.Link.NetworkBILink.DisruptDirection(direction, network.ManualDisruption) {
+				app.disruptions.add(NodeDisruption, []string{id}, direction, duration, func() error {
+					return app.StopDisruptNode(id, direction)
+				})
 				return nil
 			} else {
 				return errors.New("could not disrupt link")
 			}
 		} else {
 
-			resp, err := app.cl.SendMsg(n.MachineId, &opApi.DisruptNodeRequest{Node: id}, "disruptNode")
+			resp, err := app.cl.SendMsg(n.MachineId, &opApi.DisruptNodeRequest{Node: id, Direction: direction, DurationMs: toMilliseconds(duration)}, "disruptNode")
 			if err != nil {
 				return err
 			}
@@ -2098,17 +2172,20 @@ func (app *Follower) DisruptNode(id string) error {
 	}
 }
 
-func (app *Follower) DisruptBridge(id string) error {
+func (app *Follower) DisruptBridge(id string, direction string, duration time.Duration) error {
 	if b, ok := app.topo.GetBridge(id); ok {
 		if b.MachineId == app.GetMachineId() {
-			if b.RouterLink.NetworkBILink.Disrupt() {
+			if b.RouterLink.NetworkBILink.DisruptDirection(direction, network.ManualDisruption) {
+				app.disruptions.add(BridgeDisruption, []string{id}, direction, duration, func() error {
+					return app.StopDisruptBridge(id, direction)
+				})
 				return nil
 			} else {
 				return errors.New("could not disrupt link")
 			}
 		} else {
 
-			resp, err := app.cl.SendMsg(b.MachineId, &opApi.DisruptBridgeRequest{Bridge: id}, "disruptBridge")
+			resp, err := app.cl.SendMsg(b.MachineId, &opApi.DisruptBridgeRequest{Bridge: id, Direction: direction, DurationMs: toMilliseconds(duration)}, "disruptBridge")
 			if err != nil {
 				return err
 			}
@@ -2131,7 +2208,7 @@ func (app *Follower) DisruptBridge(id string) error {
 	}
 }
 
-func (app *Follower) DisruptRouters(router1Id string, router2Id string) error {
+func (app *Follower) DisruptRouters(router1Id string, router2Id string, direction string, duration time.Duration) error {
 
 	if r1, ok := app.topo.GetRouter(router1Id); !ok {
 		return errors.New("invalid router id: " + router1Id)
@@ -2140,15 +2217,24 @@ func (app *Follower) DisruptRouters(router1Id string, router2Id string) error {
 	} else if !(r1.MachineId == r2.MachineId) {
 		return errors.New("could not disrupt connnection between remote routers")
 	} else if r1.MachineId == app.GetMachineId() {
-		if r1.RouterLinks[r2.ID()].NetworkBILink.Disrupt() {
+		l, ok := r1.RouterLinks[r2.ID()]
+		if !ok {
+			return errors.New("could not disrupt link")
+		}
+		if l.NetworkBILink.DisruptDirection(routerLinkDirection(l, router1Id, direction), network.ManualDisruption) {
+			app.disruptions.add(RoutersDisruption, []string{router1Id, router2Id}, direction, duration, func() error {
+				return app.StopDisruptRouters(router1Id, router2Id, direction)
+			})
 			return nil
 		} else {
 			return errors.New("could not disrupt link")
 		}
 	} else {
 		resp, err := app.cl.SendMsg(r1.MachineId, &opApi.DisruptRoutersRequest{
-			Router1: r1.ID(),
-			Router2: r2.ID(),
+			Router1:    r1.ID(),
+			Router2:    r2.ID(),
+			Direction:  direction,
+			DurationMs: toMilliseconds(duration),
 		}, "disruptRouters")
 
 		if err != nil {
@@ -2170,17 +2256,18 @@ func (app *Follower) DisruptRouters(router1Id string, router2Id string) error {
 	}
 
 }
-func (app *Follower) StopDisruptNode(id string) error {
+func (app *Follower) StopDisruptNode(id string, direction string) error {
 	if n, ok := app.topo.GetNode(id); ok {
 		if n.MachineId == app.GetMachineId() {
-			if n.Link.NetworkBILink.StopDisrupt() {
+			app.disruptions.remove(NodeDisruption, direction, id)
+			if n.Link.NetworkBILink.StopDisruptDirection(direction, network.ManualDisruption) {
 				return nil
 			} else {
 				return errors.New("could not stop link")
 			}
 		} else {
 
-			resp, err := app.cl.SendMsg(n.MachineId, &opApi.DisruptNodeRequest{Node: id}, "stopDisruptNode")
+			resp, err := app.cl.SendMsg(n.MachineId, &opApi.DisruptNodeRequest{Node: id, Direction: direction}, "stopDisruptNode")
 			if err != nil {
 				return err
 			}
@@ -2203,17 +2290,18 @@ func (app *Follower) StopDisruptNode(id string) error {
 	}
 }
 
-func (app *Follower) StopDisruptBridge(id string) error {
+func (app *Follower) StopDisruptBridge(id string, direction string) error {
 	if b, ok := app.topo.GetBridge(id); ok {
 		if b.MachineId == app.GetMachineId() {
-			if b.RouterLink.NetworkBILink.StopDisrupt() {
+			app.disruptions.remove(BridgeDisruption, direction, id)
+			if b.RouterLink.NetworkBILink.StopDisruptDirection(direction, network.ManualDisruption) {
 				return nil
 			} else {
 				return errors.New("could not stop link")
 			}
 		} else {
 
-			resp, err := app.cl.SendMsg(b.MachineId, &opApi.DisruptBridgeRequest{Bridge: id}, "stopDisruptBridge")
+			resp, err := app.cl.SendMsg(b.MachineId, &opApi.DisruptBridgeRequest{Bridge: id, Direction: direction}, "stopDisruptBridge")
 			if err != nil {
 				return err
 			}
@@ -2236,7 +2324,7 @@ func (app *Follower) StopDisruptBridge(id string) error {
 	}
 }
 
-func (app *Follower) StopDisruptRouters(router1Id string, router2Id string) error {
+func (app *Follower) StopDisruptRouters(router1Id string, router2Id string, direction string) error {
 
 	if r1, ok := app.topo.GetRouter(router1Id); !ok {
 		return errors.New("invalid router id: " + router1Id)
@@ -2246,7 +2334,8 @@ func (app *Follower) StopDisruptRouters(router1Id string, router2Id string) erro
 		return errors.New("could not stop disrupt connnection between remote routers")
 	} else if r1.MachineId == app.GetMachineId() {
 		if l, ok := r1.RouterLinks[r2.ID()]; ok {
-			if l.NetworkBILink.Disrupt() {
+			app.disruptions.remove(RoutersDisruption, direction, router1Id, router2Id)
+			if l.NetworkBILink.StopDisruptDirection(routerLinkDirection(l, router1Id, direction), network.ManualDisruption) {
 				return nil
 			} else {
 				return errors.New("could not stop link")
@@ -2256,8 +2345,9 @@ func (app *Follower) StopDisruptRouters(router1Id string, router2Id string) erro
 		}
 	} else {
 		resp, err := app.cl.SendMsg(r1.MachineId, &opApi.DisruptRoutersRequest{
-			Router1: r1.ID(),
-			Router2: r2.ID(),
+			Router1:   r1.ID(),
+			Router2:   r2.ID(),
+			Direction: direction,
 		}, "stopDisruptRouters")
 
 		if err != nil {
@@ -2411,3 +2501,566 @@ func (app *Follower) StartRouter(id string) error {
 		return errors.New("invalid router id")
 	}
 }
+
+func (app *Follower) UpdateNodeLink(id string, up network.LinkProps, down network.LinkProps) error {
+	n, ok := app.topo.GetNode(id)
+	if !ok {
+		return errors.New("invalid node id")
+	}
+
+	if n.MachineId == app.GetMachineId() {
+		if n.Bridge == nil {
+			return errors.New(id + " is not connected to any bridge")
+		}
+		up.Weight = n.Link.NetworkBILink.Left.GetProps().Weight
+		down.Weight = n.Link.NetworkBILink.Right.GetProps().Weight
+		n.Link.NetworkBILink.UpdateProps(up, down)
+		return nil
+	}
+
+	upstream := toLinkDirection(up)
+	body := &updateApi.UpdateNodeLinkRequest{
+		Node:       id,
+		Latency:    *upstream.Latency,
+		Jitter:     *upstream.Jitter,
+		DropRate:   *upstream.DropRate,
+		Bandwidth:  *upstream.Bandwidth,
+		Upstream:   upstream,
+		Downstream: toLinkDirection(down),
+	}
+	resp, err := app.cl.SendMsg(n.MachineId, body, "updateNodeLink")
+	if err != nil {
+		return err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &updateApi.UpdateNodeLinkResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return errors.New(req.Error.ErrMsg)
+	}
+	return nil
+}
+
+func (app *Follower) UpdateBridgeLink(id string, up network.LinkProps, down network.LinkProps) error {
+	b, ok := app.topo.GetBridge(id)
+	if !ok {
+		return errors.New("invalid bridge id")
+	}
+
+	if b.MachineId == app.GetMachineId() {
+		if b.Router == nil {
+			return errors.New(id + " is not connected to any router")
+		}
+		up.Weight = b.RouterLink.NetworkBILink.Left.GetProps().Weight
+		down.Weight = b.RouterLink.NetworkBILink.Right.GetProps().Weight
+		b.RouterLink.NetworkBILink.UpdateProps(up, down)
+		return nil
+	}
+
+	upstream := toLinkDirection(up)
+	body := &updateApi.UpdateBridgeLinkRequest{
+		Bridge:     id,
+		Latency:    *upstream.Latency,
+		Jitter:     *upstream.Jitter,
+		DropRate:   *upstream.DropRate,
+		Bandwidth:  *upstream.Bandwidth,
+		Upstream:   upstream,
+		Downstream: toLinkDirection(down),
+	}
+	resp, err := app.cl.SendMsg(b.MachineId, body, "updateBridgeLink")
+	if err != nil {
+		return err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &updateApi.UpdateBridgeLinkResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return errors.New(req.Error.ErrMsg)
+	}
+	return nil
+}
+
+// up is applied to the router1 -> router2 direction and down to the router2 -> router1 direction
+func (app *Follower) UpdateRouterLink(router1Id string, router2Id string, up network.LinkProps, down network.LinkProps) error {
+	r1, ok := app.topo.GetRouter(router1Id)
+	if !ok {
+		return errors.New("invalid router id: " + router1Id)
+	}
+	r2, ok := app.topo.GetRouter(router2Id)
+	if !ok {
+		return errors.New("invalid router id: " + router2Id)
+	}
+
+	if r1.MachineId == app.GetMachineId() {
+		link, ok := r1.RouterLinks[router2Id]
+		if !ok {
+			return errors.New(router1Id + " and " + router2Id + " are not connected")
+		}
+		if r2.MachineId == app.GetMachineId() {
+			if link.ConnectsTo.From.ID() != router1Id {
+				up, down = down, up
+			}
+			up.Weight = link.NetworkBILink.Left.GetProps().Weight
+			down.Weight = link.NetworkBILink.Right.GetProps().Weight
+			link.NetworkBILink.UpdateProps(up, down)
+			return nil
+		}
+		up.Weight = link.ConnectsTo.NetworkLink.GetProps().Weight
+		link.ConnectsTo.NetworkLink.UpdateProps(up)
+		return app.updateRouterLinkRemote(r2, r1, down)
+
+	} else if r2.MachineId == app.GetMachineId() {
+		link, ok := r2.RouterLinks[router1Id]
+		if !ok {
+			return errors.New(router1Id + " and " + router2Id + " are not connected")
+		}
+		down.Weight = link.ConnectsTo.NetworkLink.GetProps().Weight
+		link.ConnectsTo.NetworkLink.UpdateProps(down)
+		return app.updateRouterLinkRemote(r1, r2, up)
+	}
+
+	upstream := toLinkDirection(up)
+	body := &updateApi.UpdateRouterLinkRequest{
+		Router1:    router1Id,
+		Router2:    router2Id,
+		Latency:    *upstream.Latency,
+		Jitter:     *upstream.Jitter,
+		DropRate:   *upstream.DropRate,
+		Bandwidth:  *upstream.Bandwidth,
+		Upstream:   upstream,
+		Downstream: toLinkDirection(down),
+	}
+	resp, err := app.cl.SendMsg(r1.MachineId, body, "updateRouterLink")
+	if err != nil {
+		return err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &updateApi.UpdateRouterLinkResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return errors.New(req.Error.ErrMsg)
+	}
+	return nil
+}
+
+// Updates the remote -> local half of a router link, which is owned by the machine of the remote router
+func (app *Follower) updateRouterLinkRemote(remote *topology.Router, local *topology.Router, linkProps network.LinkProps) error {
+	body := &internalApi.UpdateRouterLinkRequest{
+		R1:     remote.ID(),
+		R2:     local.ID(),
+		Props:  toLinkDirection(linkProps),
+		Weight: linkProps.Weight,
+	}
+	resp, err := app.cl.SendMsg(remote.MachineId, body, "updateRouterLinkRemote")
+	if err != nil {
+		return err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &internalApi.UpdateRouterLinkResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return errors.New(req.Error.ErrMsg)
+	}
+	return nil
+}
+
+func (app *Follower) ApplyUpdateRouterLinkRemote(router1Id string, router2Id string, linkProps network.LinkProps) error {
+	r1, ok := app.topo.GetRouter(router1Id)
+	if !ok {
+		return errors.New("invalid router id: " + router1Id)
+	}
+
+	if r1.MachineId != app.GetMachineId() {
+		return errors.New("invalid apply operation")
+	}
+
+	link, ok := r1.RouterLinks[router2Id]
+	if !ok {
+		return errors.New(router1Id + " and " + router2Id + " are not connected")
+	}
+	linkProps.Weight = link.ConnectsTo.NetworkLink.GetProps().Weight
+	link.ConnectsTo.NetworkLink.UpdateProps(linkProps)
+	return nil
+}
+
+func (app *Follower) InspectLink(id string) (api.LinkState, error) {
+	var from, to topology.Component
+	if link, ok := findBiLink(app.topo, id); ok {
+		if link.NetworkBILink != nil {
+			return convertToAPILinkState(link), nil
+		}
+		from, to = link.ConnectsTo.From, link.ConnectsTo.To
+	} else if router1Id, router2Id, ok := remoteBiLinkRouters(id); ok {
+		r1, ok := app.topo.GetRouter(router1Id)
+		if !ok {
+			return api.LinkState{}, errors.New("invalid router id: " + router1Id)
+		}
+		r2, ok := app.topo.GetRouter(router2Id)
+		if !ok {
+			return api.LinkState{}, errors.New("invalid router id: " + router2Id)
+		}
+		from, to = r1, r2
+	} else {
+		return api.LinkState{}, errors.New("invalid link id")
+	}
+
+	// Each direction is inspected on the machine that owns it
+	state, err := app.inspectLinkBetween(componentMachine(from), from.ID(), to.ID())
+	if err != nil {
+		return api.LinkState{}, err
+	}
+	if componentMachine(to) != componentMachine(from) {
+		downstream, err := app.inspectLinkBetween(componentMachine(to), to.ID(), from.ID())
+		if err != nil {
+			return api.LinkState{}, err
+		}
+		state.Downstream = downstream.Upstream
+	}
+	state.Id = id
+	return state, nil
+}
+
+func (app *Follower) inspectLinkBetween(machineId string, from string, to string) (api.LinkState, error) {
+	if machineId == app.GetMachineId() {
+		return app.InspectLinkBetween(from, to)
+	}
+
+	body := &internalApi.InspectLinkRequest{
+		From: from,
+		To:   to,
+	}
+	resp, err := app.cl.SendMsg(machineId, body, "inspectLinkRemote")
+	if err != nil {
+		return api.LinkState{}, err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &internalApi.InspectLinkResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return api.LinkState{}, err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return api.LinkState{}, errors.New(req.Error.ErrMsg)
+	}
+	return req.Link, nil
+}
+
+// Upstream is the from -> to direction
+func (app *Follower) InspectLinkBetween(from string, to string) (api.LinkState, error) {
+	link, err := findBiLinkBetween(app.topo, from, to)
+	if err != nil {
+		return api.LinkState{}, err
+	}
+	state := convertToAPILinkState(link)
+	if link.ConnectsTo.From.ID() != from {
+		state.Upstream, state.Downstream = state.Downstream, state.Upstream
+	}
+	return state, nil
+}
+
+func (app *Follower) Metrics() *metrics.Registry {
+	return collectMetrics(app.GetMachineId(), app.topo, app.proxy, app.icm)
+}
+
+func (app *Follower) ListDisruptions() []api.Disruption {
+	return app.disruptions.list(app.GetMachineId())
+}
+
+func (app *Follower) ApplyPartition(id string, groups [][]string) error {
+	return app.partitions.apply(app.topo, id, groups)
+}
+
+func (app *Follower) Heal(id string, all bool) error {
+	if all {
+		app.partitions.healAll(app.topo)
+		return nil
+	}
+	return app.partitions.heal(app.topo, id)
+}
+
+func (app *Follower) ListPartitions() []api.Partition {
+	return app.partitions.list()
+}
+
+func (app *Follower) FlapNode(id string, direction string, props network.FlapProps) (string, error) {
+	n, ok := app.topo.GetNode(id)
+	if !ok {
+		return "", errors.New("invalid node id")
+	}
+	if n.MachineId == app.GetMachineId() {
+		if n.Link == nil {
+			return "", errors.New(id + " is not connected")
+		}
+		return app.flaps.start(NodeDisruption, []string{id}, direction, n.Link.NetworkBILink, direction, props)
+	}
+
+	resp, err := app.cl.SendMsg(n.MachineId, &opApi.FlapNodeRequest{
+		Node:       id,
+		Direction:  direction,
+		FlapConfig: toFlapConfig(props),
+	}, "flapNode")
+	if err != nil {
+		return "", err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &opApi.FlapNodeResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return "", err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return "", errors.New(req.Error.ErrMsg)
+	}
+	return req.Id, nil
+}
+
+func (app *Follower) FlapBridge(id string, direction string, props network.FlapProps) (string, error) {
+	b, ok := app.topo.GetBridge(id)
+	if !ok {
+		return "", errors.New("invalid bridge id")
+	}
+	if b.MachineId == app.GetMachineId() {
+		if b.RouterLink == nil {
+			return "", errors.New(id + " is not connected to a router")
+		}
+		return app.flaps.start(BridgeDisruption, []string{id}, direction, b.RouterLink.NetworkBILink, direction, props)
+	}
+
+	resp, err := app.cl.SendMsg(b.MachineId, &opApi.FlapBridgeRequest{
+		Bridge:     id,
+		Direction:  direction,
+		FlapConfig: toFlapConfig(props),
+	}, "flapBridge")
+	if err != nil {
+		return "", err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &opApi.FlapBridgeResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return "", err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return "", errors.New(req.Error.ErrMsg)
+	}
+	return req.Id, nil
+}
+
+func (app *Follower) FlapRouters(router1Id string, router2Id string, direction string, props network.FlapProps) (string, error) {
+	r1, ok := app.topo.GetRouter(router1Id)
+	if !ok {
+		return "", errors.New("invalid router id: " + router1Id)
+	}
+	r2, ok := app.topo.GetRouter(router2Id)
+	if !ok {
+		return "", errors.New("invalid router id: " + router2Id)
+	}
+	if r1.MachineId != r2.MachineId {
+		return "", errors.New("could not flap connection between remote routers")
+	}
+	if r1.MachineId == app.GetMachineId() {
+		l, ok := r1.RouterLinks[r2.ID()]
+		if !ok {
+			return "", errors.New(router1Id + " and " + router2Id + " are not connected")
+		}
+		return app.flaps.start(RoutersDisruption, []string{router1Id, router2Id}, direction, l.NetworkBILink, routerLinkDirection(l, router1Id, direction), props)
+	}
+
+	resp, err := app.cl.SendMsg(r1.MachineId, &opApi.FlapRoutersRequest{
+		Router1:    router1Id,
+		Router2:    router2Id,
+		Direction:  direction,
+		FlapConfig: toFlapConfig(props),
+	}, "flapRouters")
+	if err != nil {
+		return "", err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &opApi.FlapRoutersResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return "", err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return "", errors.New(req.Error.ErrMsg)
+	}
+	return req.Id, nil
+}
+
+func (app *Follower) StopFlap(id string) error {
+	return app.flaps.stop(id)
+}
+
+func (app *Follower) ListFlaps() []api.Flap {
+	return app.flaps.list(app.GetMachineId())
+}
+
+func (app *Follower) LinkDirectionsBetween(from string, to string) (*connectApi.LinkDirection, *connectApi.LinkDirection, error) {
+	return linkDirectionsBetween(app.topo, from, to)
+}
+
+func (app *Follower) OwnedLinkDirections(from string, to string) (*connectApi.LinkDirection, *connectApi.LinkDirection, int, error) {
+	return ownedLinkDirections(app.topo, from, to)
+}
+
+func (app *Follower) RampNode(id string, direction string, props network.RampProps) (string, error) {
+	n, ok := app.topo.GetNode(id)
+	if !ok {
+		return "", errors.New("invalid node id")
+	}
+	if n.MachineId == app.GetMachineId() {
+		if n.Link == nil {
+			return "", errors.New(id + " is not connected")
+		}
+		return app.ramps.start(NodeDisruption, []string{id}, direction, n.Link.NetworkBILink, direction, props)
+	}
+
+	resp, err := app.cl.SendMsg(n.MachineId, &opApi.RampNodeRequest{
+		Node:       id,
+		Direction:  direction,
+		RampConfig: toRampConfig(props, direction),
+	}, "rampNode")
+	if err != nil {
+		return "", err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &opApi.RampNodeResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return "", err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return "", errors.New(req.Error.ErrMsg)
+	}
+	return req.Id, nil
+}
+
+func (app *Follower) RampBridge(id string, direction string, props network.RampProps) (string, error) {
+	b, ok := app.topo.GetBridge(id)
+	if !ok {
+		return "", errors.New("invalid bridge id")
+	}
+	if b.MachineId == app.GetMachineId() {
+		if b.RouterLink == nil {
+			return "", errors.New(id + " is not connected to a router")
+		}
+		return app.ramps.start(BridgeDisruption, []string{id}, direction, b.RouterLink.NetworkBILink, direction, props)
+	}
+
+	resp, err := app.cl.SendMsg(b.MachineId, &opApi.RampBridgeRequest{
+		Bridge:     id,
+		Direction:  direction,
+		RampConfig: toRampConfig(props, direction),
+	}, "rampBridge")
+	if err != nil {
+		return "", err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &opApi.RampBridgeResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return "", err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return "", errors.New(req.Error.ErrMsg)
+	}
+	return req.Id, nil
+}
+
+func (app *Follower) RampRouters(router1Id string, router2Id string, direction string, props network.RampProps) (string, error) {
+	r1, ok := app.topo.GetRouter(router1Id)
+	if !ok {
+		return "", errors.New("invalid router id: " + router1Id)
+	}
+	r2, ok := app.topo.GetRouter(router2Id)
+	if !ok {
+		return "", errors.New("invalid router id: " + router2Id)
+	}
+	if r1.MachineId != r2.MachineId {
+		return "", errors.New("could not ramp connection between remote routers")
+	}
+	if r1.MachineId == app.GetMachineId() {
+		l, ok := r1.RouterLinks[r2.ID()]
+		if !ok {
+			return "", errors.New(router1Id + " and " + router2Id + " are not connected")
+		}
+		return app.ramps.start(RoutersDisruption, []string{router1Id, router2Id}, direction, l.NetworkBILink, routerLinkDirection(l, router1Id, direction), props)
+	}
+
+	resp, err := app.cl.SendMsg(r1.MachineId, &opApi.RampRoutersRequest{
+		Router1:    router1Id,
+		Router2:    router2Id,
+		Direction:  direction,
+		RampConfig: toRampConfig(props, direction),
+	}, "rampRouters")
+	if err != nil {
+		return "", err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &opApi.RampRoutersResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return "", err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return "", errors.New(req.Error.ErrMsg)
+	}
+	return req.Id, nil
+}
+
+func (app *Follower) StopRamp(id string, restore bool) error {
+	return app.ramps.stop(id, restore)
+}
+
+func (app *Follower) ReverseRamp(id string) error {
+	return app.ramps.reverse(id)
+}
+
+func (app *Follower) ListRamps() []api.Ramp {
+	return app.ramps.list(app.GetMachineId())
+}
@@ -0,0 +1,170 @@
+package application
+
+import (
+	"fmt"
+	"github.com/David-Antunes/gone/api"
+	topologyApi "github.com/David-Antunes/gone/api/Topology"
+	"github.com/David-Antunes/gone/internal/network"
+	"github.com/David-Antunes/gone/internal/topology"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	nodeKind   = "node"
+	bridgeKind = "bridge"
+	routerKind = "router"
+)
+
+// Describes every component of the emulation and the links between them.
+// Each link direction is inspected on the machine that owns it.
+func (app *Leader) Graph() (topologyApi.Graph, error) {
+	graph := topologyApi.Graph{
+		Directed: false,
+		Nodes:    make([]topologyApi.GraphNode, 0),
+		Links:    make([]topologyApi.GraphLink, 0),
+	}
+	links := make([][2]topology.Component, 0)
+
+	for _, n := range app.topo.GetNodes() {
+		graph.Nodes = append(graph.Nodes, topologyApi.GraphNode{Id: n.ID(), Kind: nodeKind, MachineId: n.MachineId})
+		if n.Bridge != nil {
+			links = append(links, [2]topology.Component{n, n.Bridge})
+		}
+	}
+	for _, b := range app.topo.GetBridges() {
+		graph.Nodes = append(graph.Nodes, topologyApi.GraphNode{Id: b.ID(), Kind: bridgeKind, MachineId: b.MachineId})
+		if b.Router != nil {
+			links = append(links, [2]topology.Component{b, b.Router})
+		}
+	}
+	for _, r := range app.topo.GetRouters() {
+		graph.Nodes = append(graph.Nodes, topologyApi.GraphNode{Id: r.ID(), Kind: routerKind, MachineId: r.MachineId})
+		// Each router link is seen by both routers
+		for id, other := range r.ConnectedRouters {
+			if r.ID() < id {
+				links = append(links, [2]topology.Component{r, other})
+			}
+		}
+	}
+
+	for _, link := range links {
+		state, err := app.inspectLinkDirections(link[0], link[1])
+		if err != nil {
+			return topologyApi.Graph{}, err
+		}
+		graph.Links = append(graph.Links, graphLink(link[0].ID(), link[1].ID(), state))
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].Id < graph.Nodes[j].Id })
+	sort.Slice(graph.Links, func(i, j int) bool {
+		if graph.Links[i].Source != graph.Links[j].Source {
+			return graph.Links[i].Source < graph.Links[j].Source
+		}
+		return graph.Links[i].Target < graph.Links[j].Target
+	})
+	return graph, nil
+}
+
+// Properties are summed like the symmetric properties of the connect requests
+func graphLink(source string, target string, state api.LinkState) topologyApi.GraphLink {
+	up, down := state.Upstream, state.Downstream
+	return topologyApi.GraphLink{
+		Source:      source,
+		Target:      target,
+		Latency:     float64(up.LinkProps.Latency+down.LinkProps.Latency) / float64(time.Millisecond),
+		Bandwidth:   max(up.LinkProps.Bandwidth, down.LinkProps.Bandwidth) * 8,
+		DropRate:    min(up.LinkProps.DropRate+down.LinkProps.DropRate, 1),
+		Disrupted:   up.Disrupted || down.Disrupted,
+		Sniffed:     up.Shaper == network.SniffShaperType || down.Shaper == network.SniffShaperType,
+		Intercepted: up.Shaper == network.InterceptShaperType || down.Shaper == network.InterceptShaperType,
+		Frames:      up.Stats.Frames + down.Stats.Frames,
+		Bytes:       up.Stats.Bytes + down.Stats.Bytes,
+		Drops:       linkDrops(up.Stats) + linkDrops(down.Stats),
+	}
+}
+
+func linkDrops(stats api.LinkStats) uint64 {
+	return stats.LossDrops + stats.QueueDrops + stats.DisruptedDrops
+}
+
+var graphShapes = map[string]string{
+	nodeKind:   "box",
+	bridgeKind: "ellipse",
+	routerKind: "diamond",
+}
+
+// Writes a graph in Graphviz DOT. Components are grouped by machine, and affected links are coloured:
+// disrupted links are dashed and red, intercepted links orange and sniffed links blue.
+func GraphDot(graph topologyApi.Graph) string {
+	var sb strings.Builder
+	sb.WriteString("graph gone {\n")
+
+	machines := make(map[string][]topologyApi.GraphNode)
+	for _, n := range graph.Nodes {
+		machines[n.MachineId] = append(machines[n.MachineId], n)
+	}
+	machineIds := make([]string, 0, len(machines))
+	for machineId := range machines {
+		machineIds = append(machineIds, machineId)
+	}
+	sort.Strings(machineIds)
+
+	for _, machineId := range machineIds {
+		indent := "\t"
+		// Local emulations have no machine id
+		if machineId != "" {
+			fmt.Fprintf(&sb, "\tsubgraph %s {\n\t\tlabel=%s;\n", strconv.Quote("cluster_"+machineId), strconv.Quote(machineId))
+			indent = "\t\t"
+		}
+		for _, n := range machines[machineId] {
+			label := n.Id
+			if n.MachineId != "" {
+				label += "\n" + n.MachineId
+			}
+			fmt.Fprintf(&sb, "%s%s [shape=%s, label=%s];\n", indent, strconv.Quote(n.Id), graphShapes[n.Kind], strconv.Quote(label))
+		}
+		if machineId != "" {
+			sb.WriteString("\t}\n")
+		}
+	}
+
+	for _, l := range graph.Links {
+		label := fmt.Sprintf("%.4gms %s %.4g%% loss", l.Latency, formatBandwidth(l.Bandwidth), l.DropRate*100)
+		attributes := ""
+		if l.Disrupted {
+			label += "\ndisrupted"
+			attributes = ", style=dashed, color=red"
+		}
+		if l.Sniffed {
+			label += "\nsniffed"
+			if attributes == "" {
+				attributes = ", color=blue"
+			}
+		}
+		if l.Intercepted {
+			label += "\nintercepted"
+			if attributes == "" {
+				attributes = ", color=orange"
+			}
+		}
+		fmt.Fprintf(&sb, "\t%s -- %s [label=%s%s];\n", strconv.Quote(l.Source), strconv.Quote(l.Target), strconv.Quote(label), attributes)
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Bandwidth in bits per second with the largest unit that keeps it above 1
+func formatBandwidth(bandwidth int) string {
+	units := []string{"bps", "Kbps", "Mbps", "Gbps"}
+	value := float64(bandwidth)
+	unit := 0
+	for value >= 1000 && unit < len(units)-1 {
+		value /= 1000
+		unit++
+	}
+	return strconv.FormatFloat(value, 'g', 4, 64) + units[unit]
+}
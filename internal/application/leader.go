@@ -11,17 +11,20 @@ import (
 	disconnectApi "github.com/David-Antunes/gone/api/Disconnect"
 	opApi "github.com/David-Antunes/gone/api/Operations"
 	removeApi "github.com/David-Antunes/gone/api/Remove"
+	updateApi "github.com/David-Antunes/gone/api/Update"
 	"github.com/David-Antunes/gone/internal"
 	internalApi "github.com/David-Antunes/gone/internal/api"
 	"github.com/David-Antunes/gone/internal/cluster"
 	"github.com/David-Antunes/gone/internal/docker"
 	"github.com/David-Antunes/gone/internal/graphDB"
+	"github.com/David-Antunes/gone/internal/metrics"
 	"github.com/David-Antunes/gone/internal/network"
 	"github.com/David-Antunes/gone/internal/proxy"
 	redirecttraffic "github.com/David-Antunes/gone/internal/redirect-traffic"
 	"github.com/David-Antunes/gone/internal/topology"
 	"net"
 	"net/http"
+	"time"
 )
 
 type Leader struct {
@@ -32,6 +35,11 @@ type Leader struct {
 	icm             *cluster.InterCommunicationManager
 	rm              *LocalRttManager
 	redirectManager *redirecttraffic.RedirectManager
+	disruptions     *disruptionRegistry
+	partitions      *partitionRegistry
+	flaps           *flapRegistry
+	crashes         *crashRegistry
+	ramps           *rampRegistry
 }
 
 func NewLeader(cl *cluster.Cluster, dm *docker.DockerManager, proxy *proxy.Proxy, icm *cluster.InterCommunicationManager, rm *LocalRttManager) *Leader {
@@ -43,6 +51,11 @@ func NewLeader(cl *cluster.Cluster, dm *docker.DockerManager, proxy *proxy.Proxy
 		icm:             icm,
 		rm:              rm,
 		redirectManager: redirecttraffic.NewRedirectManager(),
+		disruptions:     newDisruptionRegistry(),
+		partitions:      newPartitionRegistry(),
+		flaps:           newFlapRegistry(),
+		crashes:         newCrashRegistry(),
+		ramps:           newRampRegistry(),
 	}
 }
 
@@ -50,6 +63,10 @@ func (app *Leader) GetMachineId() string {
 	return app.dm.GetMachineId()
 }
 
+func (app *Leader) GetNetwork() string {
+	return app.dm.GetNetwork()
+}
+
 func (app *Leader) GetNode(id string) (api.Node, bool) {
 	if n, ok := app.topo.GetNode(id); ok {
 		return convertToAPINode(n), true
@@ -95,15 +112,15 @@ func (app *Leader) clearSniffLink(id string) error {
 			bilink := sniffer.Component.(*topology.BiLink).NetworkBILink
 
 			// Convert to individual Link
-			bilink.Left.GetShaper().Close()
+			bilink.Left.Close()
 			l := bilink.Left.GetShaper().(*network.SniffShaper).ConvertToNetworkShaper()
 			bilink.Left.SetShaper(l)
-			l.Start()
+			bilink.Left.Start()
 
-			bilink.Right.GetShaper().Close()
+			bilink.Right.Close()
 			r := bilink.Right.GetShaper().(*network.SniffShaper).ConvertToNetworkShaper()
-			bilink.Right.SetShaper(l)
-			r.Start()
+			bilink.Right.SetShaper(r)
+			bilink.Right.Start()
 		}
 	}
 	return nil
@@ -122,10 +139,10 @@ func (app *Leader) clearInterceptLink(id string) error {
 			// Convert to network link
 			link := intercept.Component.(*topology.Link).NetworkLink
 
-			link.GetShaper().Close()
+			link.Close()
 			l := link.GetShaper().(*network.InterceptShaper).ConvertToNetworkShaper()
 			link.SetShaper(l)
-			l.Start()
+			link.Start()
 		}
 	}
 	return nil
@@ -235,7 +252,7 @@ func (app *Leader) AddNode(machineId string, dockerCmd []string) (string, string
 	if err != nil {
 		return "", "", "", err
 	}
-	err = app.dm.RegisterContainer(machineId, id, mac, ip)
+	err = app.dm.RegisterContainer(machineId, id, mac, ip, dockerCmd)
 	if err != nil {
 		return "", "", "", err
 	}
@@ -255,6 +272,7 @@ func (app *Leader) AddNode(machineId string, dockerCmd []string) (string, string
 		Ip:        ip,
 		Mac:       mac,
 		MachineId: machineId,
+		DockerCmd: dockerCmd,
 	}, http.MethodPost, "registerNode")
 
 	_, err = app.topo.RegisterNode(id, mac, machineId)
@@ -265,12 +283,12 @@ func (app *Leader) AddNode(machineId string, dockerCmd []string) (string, string
 	return id, mac, ip, nil
 }
 
-func (app *Leader) RegisterNode(id string, mac string, ip string, machineId string) error {
+func (app *Leader) RegisterNode(id string, mac string, ip string, machineId string, dockerCmd []string) error {
 
 	if !app.cl.Contains(machineId) {
 		return errors.New("invalid machine id")
 	}
-	err := app.dm.RegisterContainer(machineId, id, mac, ip)
+	err := app.dm.RegisterContainer(machineId, id, mac, ip, dockerCmd)
 	if err != nil {
 		return err
 	}
@@ -320,7 +338,7 @@ func (app *Leader) AddRouter(machineId string, id string) (string, error) {
 
 }
 
-func (app *Leader) ConnectNodeToBridge(nodeID string, bridgeID string, linkProps network.LinkProps) error {
+func (app *Leader) ConnectNodeToBridge(nodeID string, bridgeID string, up network.LinkProps, down network.LinkProps) error {
 
 	var n *topology.Node
 	var b *topology.Bridge
@@ -337,7 +355,7 @@ func (app *Leader) ConnectNodeToBridge(nodeID string, bridgeID string, linkProps
 		return errors.New("can't connect a node and bridge in different machines")
 	}
 	if n.MachineId == app.GetMachineId() {
-		_, err := app.topo.ConnectNodeToBridge(nodeID, bridgeID, linkProps)
+		_, err := app.topo.ConnectNodeToBridge(nodeID, bridgeID, up, down)
 		if err != nil {
 			return err
 		}
@@ -347,14 +365,17 @@ func (app *Leader) ConnectNodeToBridge(nodeID string, bridgeID string, linkProps
 		}
 		return nil
 	} else {
+		upstream := toLinkDirection(up)
 		body := &connectApi.ConnectNodeToBridgeRequest{
-			Node:      nodeID,
-			Bridge:    bridgeID,
-			Latency:   linkProps.FLatency,
-			Jitter:    linkProps.Jitter * 2.0,
-			DropRate:  linkProps.DropRate * 2.0,
-			Bandwidth: linkProps.Bandwidth * 8,
-			Weight:    linkProps.Weight,
+			Node:       nodeID,
+			Bridge:     bridgeID,
+			Latency:    *upstream.Latency,
+			Jitter:     *upstream.Jitter,
+			DropRate:   *upstream.DropRate,
+			Bandwidth:  *upstream.Bandwidth,
+			Weight:     up.Weight,
+			Upstream:   upstream,
+			Downstream: toLinkDirection(down),
 		}
 
 		resp, err := app.cl.SendMsg(n.MachineId, body, "connectNodeToBridge")
@@ -375,7 +396,7 @@ func (app *Leader) ConnectNodeToBridge(nodeID string, bridgeID string, linkProps
 	return nil
 }
 
-func (app *Leader) ConnectBridgeToRouter(bridgeID string, routerID string, linkProps network.LinkProps) error {
+func (app *Leader) ConnectBridgeToRouter(bridgeID string, routerID string, up network.LinkProps, down network.LinkProps) error {
 
 	var b *topology.Bridge
 	var r *topology.Router
@@ -393,7 +414,7 @@ func (app *Leader) ConnectBridgeToRouter(bridgeID string, routerID string, linkP
 	}
 
 	if app.GetMachineId() == b.MachineId {
-		_, err := app.topo.ConnectBridgeToRouter(bridgeID, routerID, linkProps)
+		_, err := app.topo.ConnectBridgeToRouter(bridgeID, routerID, up, down)
 
 		if err != nil {
 			return err
@@ -404,14 +425,17 @@ func (app *Leader) ConnectBridgeToRouter(bridgeID string, routerID string, linkP
 			fmt.Println("Added", netNode.ID(), "to router", routerID)
 		}
 	} else {
+		upstream := toLinkDirection(up)
 		body := &connectApi.ConnectBridgeToRouterRequest{
-			Bridge:    bridgeID,
-			Router:    routerID,
-			Latency:   linkProps.FLatency,
-			Jitter:    linkProps.Jitter * 2.0,
-			DropRate:  linkProps.DropRate * 2.0,
-			Bandwidth: linkProps.Bandwidth * 8,
-			Weight:    linkProps.Weight,
+			Bridge:     bridgeID,
+			Router:     routerID,
+			Latency:    *upstream.Latency,
+			Jitter:     *upstream.Jitter,
+			DropRate:   *upstream.DropRate,
+			Bandwidth:  *upstream.Bandwidth,
+			Weight:     up.Weight,
+			Upstream:   upstream,
+			Downstream: toLinkDirection(down),
 		}
 
 		resp, err := app.cl.SendMsg(b.MachineId, body, "connectBridgeToRouter")
@@ -432,7 +456,7 @@ func (app *Leader) ConnectBridgeToRouter(bridgeID string, routerID string, linkP
 	return nil
 }
 
-func (app *Leader) ConnectRouterToRouter(router1ID string, router2ID string, linkProps network.LinkProps, propagate bool) error {
+func (app *Leader) ConnectRouterToRouter(router1ID string, router2ID string, up network.LinkProps, down network.LinkProps, propagate bool) error {
 
 	if router1ID == router2ID {
 		return errors.New("can't connect a router to itself")
@@ -451,27 +475,27 @@ func (app *Leader) ConnectRouterToRouter(router1ID string, router2ID string, lin
 
 	if r1.MachineId == app.GetMachineId() {
 		if r2.MachineId == app.GetMachineId() {
-			l, err := app.topo.ConnectRouterToRouterLocal(router1ID, router2ID, linkProps)
+			l, err := app.topo.ConnectRouterToRouterLocal(router1ID, router2ID, up, down)
 
 			if err != nil {
 				return err
 			}
 
-			graphDB.AddPath(router1ID, router2ID, l.ID(), linkProps.Weight)
+			graphDB.AddPath(router1ID, router2ID, l.ID(), up.Weight)
 			app.TradeRoutes(r1, r2)
 			if propagate {
 				app.PropagateNewRoutes(r1)
 			}
 			return nil
 		} else {
-			return app.connectRouterToRouterRemote(r1, r2, linkProps, propagate)
+			return app.connectRouterToRouterRemote(r1, r2, up, down, propagate)
 		}
 	} else {
-		return app.RedirectConnection(r1, r2, linkProps, propagate)
+		return app.RedirectConnection(r1, r2, up, down, propagate)
 	}
 }
 
-func (app *Leader) connectRouterToRouterRemote(r1 *topology.Router, r2 *topology.Router, linkProps network.LinkProps, propagate bool) error {
+func (app *Leader) connectRouterToRouterRemote(r1 *topology.Router, r2 *topology.Router, up network.LinkProps, down network.LinkProps, propagate bool) error {
 	app.topo.Lock()
 	if _, ok := r1.ConnectedRouters[r2.ID()]; ok {
 		return errors.New(r1.ID() + " is already connected to " + r2.ID())
@@ -487,7 +511,8 @@ func (app *Leader) connectRouterToRouterRemote(r1 *topology.Router, r2 *topology
 	// Temporary Fix
 	app.icm.AddMachine(conn, r2.MachineId)
 	app.icm.AddConnection(r2.ID(), d, r2.MachineId, r1.ID(), r1.NetworkRouter)
-	toLink := network.CreateLink(router1Channel, nil, linkProps)
+	up = up.Seeded(r1.ID() + "-RemoteLink-" + r2.ID())
+	toLink := network.CreateLink(router1Channel, nil, up)
 	topoLink := &topology.Link{
 		Id:          r1.ID() + "-RemoteLink-" + r2.ID(),
 		NetworkLink: toLink,
@@ -507,7 +532,7 @@ func (app *Leader) connectRouterToRouterRemote(r1 *topology.Router, r2 *topology
 	r1.AddRouter(r2, BiLink)
 	r2.AddRouter(r1, BiLink)
 
-	s := network.CreateRemoteShaper(r2.ID(), r1.ID(), router1Channel, app.icm.GetoutQueue(), linkProps)
+	s := network.CreateRemoteShaper(r2.ID(), r1.ID(), router1Channel, app.icm.GetoutQueue(), up)
 	s.SetDelay(d)
 	toLink.SetShaper(s)
 	toLink.Start()
@@ -515,11 +540,9 @@ func (app *Leader) connectRouterToRouterRemote(r1 *topology.Router, r2 *topology
 		R1:        r2.ID(),
 		R2:        r1.ID(),
 		MachineID: r1.MachineId,
-		Latency:   linkProps.Latency * 2.0,
-		Jitter:    linkProps.Jitter * 2.0,
-		DropRate:  linkProps.DropRate * 2.0,
-		Bandwidth: linkProps.Bandwidth * 8,
-		Weight:    linkProps.Weight,
+		Up:        toLinkDirection(down),
+		Down:      toLinkDirection(up),
+		Weight:    up.Weight,
 		Propagate: propagate,
 	}
 
@@ -530,7 +553,7 @@ func (app *Leader) connectRouterToRouterRemote(r1 *topology.Router, r2 *topology
 		return errors.New("couldn't contact machine")
 	}
 
-	graphDB.AddPath(r1.ID(), r2.ID(), BiLink.ID(), linkProps.Weight)
+	graphDB.AddPath(r1.ID(), r2.ID(), BiLink.ID(), up.Weight)
 
 	app.TradeRoutesRemote(r1, r2)
 	if propagate {
@@ -578,6 +601,7 @@ func (app *Leader) ApplyConnectRouterToRouterRemote(router1ID string, router2ID
 
 	app.icm.AddConnection(r2.ID(), d, machineId, r1.ID(), r1.NetworkRouter)
 
+	linkProps = linkProps.Seeded(r1.ID() + "-RemoteLink-" + r2.ID())
 	toLink := network.CreateLink(router1Channel, nil, linkProps)
 	topoLink := &topology.Link{
 		Id:          r1.ID() + "-RemoteLink-" + r2.ID(),
@@ -607,17 +631,15 @@ func (app *Leader) ApplyConnectRouterToRouterRemote(router1ID string, router2ID
 	return nil
 }
 
-func (app *Leader) RedirectConnection(r1 *topology.Router, r2 *topology.Router, linkProps network.LinkProps, propagate bool) error {
+func (app *Leader) RedirectConnection(r1 *topology.Router, r2 *topology.Router, up network.LinkProps, down network.LinkProps, propagate bool) error {
 
 	b := &internalApi.ConnectRouterToRouterRequest{
 		R1:        r1.ID(),
 		R2:        r2.ID(),
 		MachineID: r2.MachineId,
-		Latency:   linkProps.Latency * 2.0,
-		Jitter:    linkProps.Jitter * 2.0,
-		DropRate:  linkProps.DropRate * 2.0,
-		Bandwidth: linkProps.Bandwidth * 8,
-		Weight:    linkProps.Weight,
+		Up:        toLinkDirection(up),
+		Down:      toLinkDirection(down),
+		Weight:    up.Weight,
 		Propagate: propagate,
 	}
 	resp, err := app.cl.SendMsg(r1.MachineId, b, "connectRouterToRouter")
@@ -2234,17 +2256,78 @@ func (app *Leader) Unpause(id string, all bool) error {
 	}
 }
 
-func (app *Leader) DisruptNode(id string) error {
+func (app *Leader) CrashNode(id string, kill bool, restartAfter time.Duration) error {
 	if n, ok := app.topo.GetNode(id); ok {
 		if n.MachineId == app.GetMachineId() {
-			if n.Link.NetworkBILink.Disrupt() {
+			return app.crashes.crash(app.topo, app.dm, id, kill, restartAfter)
+		} else {
+
+			resp, err := app.cl.SendMsg(n.MachineId, &opApi.CrashNodeRequest{Node: id, Kill: kill, RestartMs: toMilliseconds(restartAfter)}, "crashNode")
+			if err != nil {
+				return err
+			}
+
+			d := json.NewDecoder(resp.Body)
+			req := &opApi.CrashNodeResponse{}
+			err = d.Decode(&req)
+
+			if err != nil {
+				return err
+			}
+
+			if req.Error.ErrCode != 0 {
+				return errors.New(req.Error.ErrMsg)
+			}
+			return nil
+		}
+	} else {
+		return errors.New("invalid node id")
+	}
+}
+
+func (app *Leader) RestartNode(id string) error {
+	if n, ok := app.topo.GetNode(id); ok {
+		if n.MachineId == app.GetMachineId() {
+			return app.crashes.restart(app.topo, app.dm, id)
+		} else {
+
+			resp, err := app.cl.SendMsg(n.MachineId, &opApi.RestartNodeRequest{Node: id}, "restartNode")
+			if err != nil {
+				return err
+			}
+
+			d := json.NewDecoder(resp.Body)
+			req := &opApi.RestartNodeResponse{}
+			err = d.Decode(&req)
+
+			if err != nil {
+				return err
+			}
+
+			if req.Error.ErrCode != 0 {
+				return errors.New(req.Error.ErrMsg)
+			}
+			return nil
+		}
+	} else {
+		return errors.New("invalid node id")
+	}
+}
+
+func (app *Leader) DisruptNode(id string, direction string, duration time.Duration) error {
+	if n, ok := app.topo.GetNode(id); ok {
+		if n.MachineId == app.GetMachineId() {
+			if n.Link.NetworkBILink.DisruptDirection(direction, network.ManualDisruption) {
+				app.disruptions.add(NodeDisruption, []string{id}, direction, duration, func() error {
+					return app.StopDisruptNode(id, direction)
+				})
 				return nil
 			} else {
 				return errors.New("could not disrupt link")
 			}
 		} else {
 
-			resp, err := app.cl.SendMsg(n.MachineId, &opApi.DisruptNodeRequest{Node: id}, "disruptNode")
+			resp, err := app.cl.SendMsg(n.MachineId, &opApi.DisruptNodeRequest{Node: id, Direction: direction, DurationMs: toMilliseconds(duration)}, "disruptNode")
 			if err != nil {
 				return err
 			}
@@ -2267,17 +2350,20 @@ func (app *Leader) DisruptNode(id string) error {
 	}
 }
 
-func (app *Leader) DisruptBridge(id string) error {
+func (app *Leader) DisruptBridge(id string, direction string, duration time.Duration) error {
 	if b, ok := app.topo.GetBridge(id); ok {
 		if b.MachineId == app.GetMachineId() {
-			if b.RouterLink.NetworkBILink.Disrupt() {
+			if b.RouterLink.NetworkBILink.DisruptDirection(direction, network.ManualDisruption) {
+				app.disruptions.add(BridgeDisruption, []string{id}, direction, duration, func() error {
+					return app.StopDisruptBridge(id, direction)
+				})
 				return nil
 			} else {
 				return errors.New("could not disrupt link")
 			}
 		} else {
 
-			resp, err := app.cl.SendMsg(b.MachineId, &opApi.DisruptBridgeRequest{Bridge: id}, "disruptBridge")
+			resp, err := app.cl.SendMsg(b.MachineId, &opApi.DisruptBridgeRequest{Bridge: id, Direction: direction, DurationMs: toMilliseconds(duration)}, "disruptBridge")
 			if err != nil {
 				return err
 			}
@@ -2300,7 +2386,7 @@ func (app *Leader) DisruptBridge(id string) error {
 	}
 }
 
-func (app *Leader) DisruptRouters(router1Id string, router2Id string) error {
+func (app *Leader) DisruptRouters(router1Id string, router2Id string, direction string, duration time.Duration) error {
 	app.topo.Lock()
 	defer app.topo.Unlock()
 	if r1, ok := app.topo.GetRouter(router1Id); !ok {
@@ -2311,7 +2397,10 @@ func (app *Leader) DisruptRouters(router1Id string, router2Id string) error {
 		return errors.New("could not disrupt connnection between remote routers")
 	} else if r1.MachineId == app.GetMachineId() {
 		if l, ok := r1.RouterLinks[r2.ID()]; ok {
-			if l.NetworkBILink.Disrupt() {
+			if l.NetworkBILink.DisruptDirection(routerLinkDirection(l, router1Id, direction), network.ManualDisruption) {
+				app.disruptions.add(RoutersDisruption, []string{router1Id, router2Id}, direction, duration, func() error {
+					return app.StopDisruptRouters(router1Id, router2Id, direction)
+				})
 				return nil
 			} else {
 				return errors.New("could not stop link")
@@ -2321,8 +2410,10 @@ func (app *Leader) DisruptRouters(router1Id string, router2Id string) error {
 		}
 	} else {
 		resp, err := app.cl.SendMsg(r1.MachineId, &opApi.DisruptRoutersRequest{
-			Router1: r1.ID(),
-			Router2: r2.ID(),
+			Router1:    r1.ID(),
+			Router2:    r2.ID(),
+			Direction:  direction,
+			DurationMs: toMilliseconds(duration),
 		}, "disruptRouters")
 
 		if err != nil {
@@ -2344,17 +2435,18 @@ func (app *Leader) DisruptRouters(router1Id string, router2Id string) error {
 	}
 
 }
-func (app *Leader) StopDisruptNode(id string) error {
+func (app *Leader) StopDisruptNode(id string, direction string) error {
 	if n, ok := app.topo.GetNode(id); ok {
 		if n.MachineId == app.GetMachineId() {
-			if n.Link.NetworkBILink.StopDisrupt() {
+			app.disruptions.remove(NodeDisruption, direction, id)
+			if n.Link.NetworkBILink.StopDisruptDirection(direction, network.ManualDisruption) {
 				return nil
 			} else {
 				return errors.New("could not stop link")
 			}
 		} else {
 
-			resp, err := app.cl.SendMsg(n.MachineId, &opApi.DisruptNodeRequest{Node: id}, "stopDisruptNode")
+			resp, err := app.cl.SendMsg(n.MachineId, &opApi.DisruptNodeRequest{Node: id, Direction: direction}, "stopDisruptNode")
 			if err != nil {
 				return err
 			}
@@ -2377,17 +2469,18 @@ func (app *Leader) StopDisruptNode(id string) error {
 	}
 }
 
-func (app *Leader) StopDisruptBridge(id string) error {
+func (app *Leader) StopDisruptBridge(id string, direction string) error {
 	if b, ok := app.topo.GetBridge(id); ok {
 		if b.MachineId == app.GetMachineId() {
-			if b.RouterLink.NetworkBILink.StopDisrupt() {
+			app.disruptions.remove(BridgeDisruption, direction, id)
+			if b.RouterLink.NetworkBILink.StopDisruptDirection(direction, network.ManualDisruption) {
 				return nil
 			} else {
 				return errors.New("could not stop link")
 			}
 		} else {
 
-			resp, err := app.cl.SendMsg(b.MachineId, &opApi.DisruptBridgeRequest{Bridge: id}, "stopDisruptBridge")
+			resp, err := app.cl.SendMsg(b.MachineId, &opApi.DisruptBridgeRequest{Bridge: id, Direction: direction}, "stopDisruptBridge")
 			if err != nil {
 				return err
 			}
@@ -2410,7 +2503,7 @@ func (app *Leader) StopDisruptBridge(id string) error {
 	}
 }
 
-func (app *Leader) StopDisruptRouters(router1Id string, router2Id string) error {
+func (app *Leader) StopDisruptRouters(router1Id string, router2Id string, direction string) error {
 
 	if r1, ok := app.topo.GetRouter(router1Id); !ok {
 		return errors.New("invalid router id: " + router1Id)
@@ -2419,15 +2512,21 @@ func (app *Leader) StopDisruptRouters(router1Id string, router2Id string) error
 	} else if !(r1.MachineId == r2.MachineId) {
 		return errors.New("could not stop disrupt connnection between remote routers")
 	} else if r1.MachineId == app.GetMachineId() {
-		if r1.RouterLinks[r2.ID()].NetworkBILink.StopDisrupt() {
+		app.disruptions.remove(RoutersDisruption, direction, router1Id, router2Id)
+		l, ok := r1.RouterLinks[r2.ID()]
+		if !ok {
+			return errors.New("could not stop link")
+		}
+		if l.NetworkBILink.StopDisruptDirection(routerLinkDirection(l, router1Id, direction), network.ManualDisruption) {
 			return nil
 		} else {
 			return errors.New("could not stop link")
 		}
 	} else {
 		resp, err := app.cl.SendMsg(r1.MachineId, &opApi.DisruptRoutersRequest{
-			Router1: r1.ID(),
-			Router2: r2.ID(),
+			Router1:   r1.ID(),
+			Router2:   r2.ID(),
+			Direction: direction,
 		}, "stopDisruptRouters")
 
 		if err != nil {
@@ -2581,3 +2680,751 @@ func (app *Leader) StartRouter(id string) error {
 		return errors.New("invalid router id")
 	}
 }
+
+func (app *Leader) UpdateNodeLink(id string, up network.LinkProps, down network.LinkProps) error {
+	n, ok := app.topo.GetNode(id)
+	if !ok {
+		return errors.New("invalid node id")
+	}
+
+	if n.MachineId == app.GetMachineId() {
+		if n.Bridge == nil {
+			return errors.New(id + " is not connected to any bridge")
+		}
+		up.Weight = n.Link.NetworkBILink.Left.GetProps().Weight
+		down.Weight = n.Link.NetworkBILink.Right.GetProps().Weight
+		n.Link.NetworkBILink.UpdateProps(up, down)
+		return nil
+	}
+
+	upstream := toLinkDirection(up)
+	body := &updateApi.UpdateNodeLinkRequest{
+		Node:       id,
+		Latency:    *upstream.Latency,
+		Jitter:     *upstream.Jitter,
+		DropRate:   *upstream.DropRate,
+		Bandwidth:  *upstream.Bandwidth,
+		Upstream:   upstream,
+		Downstream: toLinkDirection(down),
+	}
+	resp, err := app.cl.SendMsg(n.MachineId, body, "updateNodeLink")
+	if err != nil {
+		return err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &updateApi.UpdateNodeLinkResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return errors.New(req.Error.ErrMsg)
+	}
+	return nil
+}
+
+func (app *Leader) UpdateBridgeLink(id string, up network.LinkProps, down network.LinkProps) error {
+	b, ok := app.topo.GetBridge(id)
+	if !ok {
+		return errors.New("invalid bridge id")
+	}
+
+	if b.MachineId == app.GetMachineId() {
+		if b.Router == nil {
+			return errors.New(id + " is not connected to any router")
+		}
+		up.Weight = b.RouterLink.NetworkBILink.Left.GetProps().Weight
+		down.Weight = b.RouterLink.NetworkBILink.Right.GetProps().Weight
+		b.RouterLink.NetworkBILink.UpdateProps(up, down)
+		return nil
+	}
+
+	upstream := toLinkDirection(up)
+	body := &updateApi.UpdateBridgeLinkRequest{
+		Bridge:     id,
+		Latency:    *upstream.Latency,
+		Jitter:     *upstream.Jitter,
+		DropRate:   *upstream.DropRate,
+		Bandwidth:  *upstream.Bandwidth,
+		Upstream:   upstream,
+		Downstream: toLinkDirection(down),
+	}
+	resp, err := app.cl.SendMsg(b.MachineId, body, "updateBridgeLink")
+	if err != nil {
+		return err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &updateApi.UpdateBridgeLinkResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return errors.New(req.Error.ErrMsg)
+	}
+	return nil
+}
+
+// up is applied to the router1 -> router2 direction and down to the router2 -> router1 direction
+func (app *Leader) UpdateRouterLink(router1Id string, router2Id string, up network.LinkProps, down network.LinkProps) error {
+	r1, ok := app.topo.GetRouter(router1Id)
+	if !ok {
+		return errors.New("invalid router id: " + router1Id)
+	}
+	r2, ok := app.topo.GetRouter(router2Id)
+	if !ok {
+		return errors.New("invalid router id: " + router2Id)
+	}
+
+	if r1.MachineId == app.GetMachineId() {
+		link, ok := r1.RouterLinks[router2Id]
+		if !ok {
+			return errors.New(router1Id + " and " + router2Id + " are not connected")
+		}
+		if r2.MachineId == app.GetMachineId() {
+			if link.ConnectsTo.From.ID() != router1Id {
+				up, down = down, up
+			}
+			up.Weight = link.NetworkBILink.Left.GetProps().Weight
+			down.Weight = link.NetworkBILink.Right.GetProps().Weight
+			link.NetworkBILink.UpdateProps(up, down)
+			return nil
+		}
+		up.Weight = link.ConnectsTo.NetworkLink.GetProps().Weight
+		link.ConnectsTo.NetworkLink.UpdateProps(up)
+		return app.updateRouterLinkRemote(r2, r1, down)
+
+	} else if r2.MachineId == app.GetMachineId() {
+		link, ok := r2.RouterLinks[router1Id]
+		if !ok {
+			return errors.New(router1Id + " and " + router2Id + " are not connected")
+		}
+		down.Weight = link.ConnectsTo.NetworkLink.GetProps().Weight
+		link.ConnectsTo.NetworkLink.UpdateProps(down)
+		return app.updateRouterLinkRemote(r1, r2, up)
+	}
+
+	upstream := toLinkDirection(up)
+	body := &updateApi.UpdateRouterLinkRequest{
+		Router1:    router1Id,
+		Router2:    router2Id,
+		Latency:    *upstream.Latency,
+		Jitter:     *upstream.Jitter,
+		DropRate:   *upstream.DropRate,
+		Bandwidth:  *upstream.Bandwidth,
+		Upstream:   upstream,
+		Downstream: toLinkDirection(down),
+	}
+	resp, err := app.cl.SendMsg(r1.MachineId, body, "updateRouterLink")
+	if err != nil {
+		return err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &updateApi.UpdateRouterLinkResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return errors.New(req.Error.ErrMsg)
+	}
+	return nil
+}
+
+// Updates the remote -> local half of a router link, which is owned by the machine of the remote router
+func (app *Leader) updateRouterLinkRemote(remote *topology.Router, local *topology.Router, linkProps network.LinkProps) error {
+	body := &internalApi.UpdateRouterLinkRequest{
+		R1:     remote.ID(),
+		R2:     local.ID(),
+		Props:  toLinkDirection(linkProps),
+		Weight: linkProps.Weight,
+	}
+	resp, err := app.cl.SendMsg(remote.MachineId, body, "updateRouterLinkRemote")
+	if err != nil {
+		return err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &internalApi.UpdateRouterLinkResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return errors.New(req.Error.ErrMsg)
+	}
+	return nil
+}
+
+func (app *Leader) ApplyUpdateRouterLinkRemote(router1Id string, router2Id string, linkProps network.LinkProps) error {
+	r1, ok := app.topo.GetRouter(router1Id)
+	if !ok {
+		return errors.New("invalid router id: " + router1Id)
+	}
+
+	if r1.MachineId != app.GetMachineId() {
+		return errors.New("invalid apply operation")
+	}
+
+	link, ok := r1.RouterLinks[router2Id]
+	if !ok {
+		return errors.New(router1Id + " and " + router2Id + " are not connected")
+	}
+	linkProps.Weight = link.ConnectsTo.NetworkLink.GetProps().Weight
+	link.ConnectsTo.NetworkLink.UpdateProps(linkProps)
+	return nil
+}
+
+func (app *Leader) InspectLink(id string) (api.LinkState, error) {
+	var from, to topology.Component
+	if link, ok := findBiLink(app.topo, id); ok {
+		if link.NetworkBILink != nil {
+			return convertToAPILinkState(link), nil
+		}
+		from, to = link.ConnectsTo.From, link.ConnectsTo.To
+	} else if router1Id, router2Id, ok := remoteBiLinkRouters(id); ok {
+		r1, ok := app.topo.GetRouter(router1Id)
+		if !ok {
+			return api.LinkState{}, errors.New("invalid router id: " + router1Id)
+		}
+		r2, ok := app.topo.GetRouter(router2Id)
+		if !ok {
+			return api.LinkState{}, errors.New("invalid router id: " + router2Id)
+		}
+		from, to = r1, r2
+	} else {
+		return api.LinkState{}, errors.New("invalid link id")
+	}
+
+	state, err := app.inspectLinkDirections(from, to)
+	if err != nil {
+		return api.LinkState{}, err
+	}
+	state.Id = id
+	return state, nil
+}
+
+// Each direction is inspected on the machine that owns it
+func (app *Leader) inspectLinkDirections(from topology.Component, to topology.Component) (api.LinkState, error) {
+	state, err := app.inspectLinkBetween(componentMachine(from), from.ID(), to.ID())
+	if err != nil {
+		return api.LinkState{}, err
+	}
+	if componentMachine(to) != componentMachine(from) {
+		downstream, err := app.inspectLinkBetween(componentMachine(to), to.ID(), from.ID())
+		if err != nil {
+			return api.LinkState{}, err
+		}
+		state.Downstream = downstream.Upstream
+	}
+	return state, nil
+}
+
+func (app *Leader) inspectLinkBetween(machineId string, from string, to string) (api.LinkState, error) {
+	if machineId == app.GetMachineId() {
+		return app.InspectLinkBetween(from, to)
+	}
+
+	body := &internalApi.InspectLinkRequest{
+		From: from,
+		To:   to,
+	}
+	resp, err := app.cl.SendMsg(machineId, body, "inspectLinkRemote")
+	if err != nil {
+		return api.LinkState{}, err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &internalApi.InspectLinkResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return api.LinkState{}, err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return api.LinkState{}, errors.New(req.Error.ErrMsg)
+	}
+	return req.Link, nil
+}
+
+// Upstream is the from -> to direction
+func (app *Leader) InspectLinkBetween(from string, to string) (api.LinkState, error) {
+	link, err := findBiLinkBetween(app.topo, from, to)
+	if err != nil {
+		return api.LinkState{}, err
+	}
+	state := convertToAPILinkState(link)
+	if link.ConnectsTo.From.ID() != from {
+		state.Upstream, state.Downstream = state.Downstream, state.Upstream
+	}
+	return state, nil
+}
+
+func (app *Leader) Metrics() *metrics.Registry {
+	return collectMetrics(app.GetMachineId(), app.topo, app.proxy, app.icm)
+}
+
+func (app *Leader) ListDisruptions() []api.Disruption {
+	list := app.disruptions.list(app.GetMachineId())
+
+	if len(app.cl.Nodes) == 0 {
+		return list
+	}
+
+	broadcast, err := app.cl.Broadcast(nil, http.MethodGet, "listDisruptions")
+	if err != nil {
+		return list
+	}
+	for _, res := range broadcast {
+		response := &opApi.ListDisruptionsResponse{}
+
+		d := json.NewDecoder(res.Body)
+		err = d.Decode(&response)
+		if err != nil {
+			continue
+		}
+		list = append(list, response.Disruptions...)
+	}
+	return list
+}
+
+func (app *Leader) Partition(groups [][]string) (string, error) {
+	id := app.partitions.nextId()
+
+	if err := app.partitions.apply(app.topo, id, groups); err != nil {
+		return "", err
+	}
+
+	if len(app.cl.Nodes) == 0 {
+		return id, nil
+	}
+
+	if err := app.broadcastPartition(id, groups); err != nil {
+		// Machines that did not apply the partition ignore the heal
+		if healErr := app.Heal(id, false); healErr != nil {
+			return "", errors.New(err.Error() + ", could not heal " + id + ": " + healErr.Error())
+		}
+		return "", err
+	}
+	return id, nil
+}
+
+func (app *Leader) broadcastPartition(id string, groups [][]string) error {
+	broadcast, err := app.cl.Broadcast(&internalApi.ApplyPartitionRequest{
+		Id:     id,
+		Groups: groups,
+	}, http.MethodPost, "applyPartition")
+	if err != nil {
+		return err
+	}
+	for _, res := range broadcast {
+		response := &internalApi.ApplyPartitionResponse{}
+
+		d := json.NewDecoder(res.Body)
+		err = d.Decode(&response)
+		if err != nil {
+			return err
+		}
+		if response.Error.ErrCode != 0 {
+			return errors.New(response.Error.ErrMsg)
+		}
+	}
+	return nil
+}
+
+func (app *Leader) Heal(id string, all bool) error {
+	if all {
+		app.partitions.healAll(app.topo)
+	} else if err := app.partitions.heal(app.topo, id); err != nil {
+		return err
+	}
+
+	if len(app.cl.Nodes) == 0 {
+		return nil
+	}
+
+	_, err := app.cl.Broadcast(&opApi.HealRequest{
+		Id:  id,
+		All: all,
+	}, http.MethodPost, "heal")
+	return err
+}
+
+func (app *Leader) ListPartitions() []api.Partition {
+	return app.partitions.list()
+}
+
+func (app *Leader) FlapNode(id string, direction string, props network.FlapProps) (string, error) {
+	n, ok := app.topo.GetNode(id)
+	if !ok {
+		return "", errors.New("invalid node id")
+	}
+	if n.MachineId == app.GetMachineId() {
+		if n.Link == nil {
+			return "", errors.New(id + " is not connected")
+		}
+		return app.flaps.start(NodeDisruption, []string{id}, direction, n.Link.NetworkBILink, direction, props)
+	}
+
+	resp, err := app.cl.SendMsg(n.MachineId, &opApi.FlapNodeRequest{
+		Node:       id,
+		Direction:  direction,
+		FlapConfig: toFlapConfig(props),
+	}, "flapNode")
+	if err != nil {
+		return "", err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &opApi.FlapNodeResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return "", err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return "", errors.New(req.Error.ErrMsg)
+	}
+	return req.Id, nil
+}
+
+func (app *Leader) FlapBridge(id string, direction string, props network.FlapProps) (string, error) {
+	b, ok := app.topo.GetBridge(id)
+	if !ok {
+		return "", errors.New("invalid bridge id")
+	}
+	if b.MachineId == app.GetMachineId() {
+		if b.RouterLink == nil {
+			return "", errors.New(id + " is not connected to a router")
+		}
+		return app.flaps.start(BridgeDisruption, []string{id}, direction, b.RouterLink.NetworkBILink, direction, props)
+	}
+
+	resp, err := app.cl.SendMsg(b.MachineId, &opApi.FlapBridgeRequest{
+		Bridge:     id,
+		Direction:  direction,
+		FlapConfig: toFlapConfig(props),
+	}, "flapBridge")
+	if err != nil {
+		return "", err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &opApi.FlapBridgeResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return "", err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return "", errors.New(req.Error.ErrMsg)
+	}
+	return req.Id, nil
+}
+
+func (app *Leader) FlapRouters(router1Id string, router2Id string, direction string, props network.FlapProps) (string, error) {
+	r1, ok := app.topo.GetRouter(router1Id)
+	if !ok {
+		return "", errors.New("invalid router id: " + router1Id)
+	}
+	r2, ok := app.topo.GetRouter(router2Id)
+	if !ok {
+		return "", errors.New("invalid router id: " + router2Id)
+	}
+	if r1.MachineId != r2.MachineId {
+		return "", errors.New("could not flap connection between remote routers")
+	}
+	if r1.MachineId == app.GetMachineId() {
+		l, ok := r1.RouterLinks[r2.ID()]
+		if !ok {
+			return "", errors.New(router1Id + " and " + router2Id + " are not connected")
+		}
+		return app.flaps.start(RoutersDisruption, []string{router1Id, router2Id}, direction, l.NetworkBILink, routerLinkDirection(l, router1Id, direction), props)
+	}
+
+	resp, err := app.cl.SendMsg(r1.MachineId, &opApi.FlapRoutersRequest{
+		Router1:    router1Id,
+		Router2:    router2Id,
+		Direction:  direction,
+		FlapConfig: toFlapConfig(props),
+	}, "flapRouters")
+	if err != nil {
+		return "", err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &opApi.FlapRoutersResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return "", err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return "", errors.New(req.Error.ErrMsg)
+	}
+	return req.Id, nil
+}
+
+// Flaps run on the machine of the link, so unknown ids are looked up in the followers
+func (app *Leader) StopFlap(id string) error {
+	if app.flaps.contains(id) || len(app.cl.Nodes) == 0 {
+		return app.flaps.stop(id)
+	}
+
+	broadcast, err := app.cl.Broadcast(&opApi.StopFlapRequest{Id: id}, http.MethodPost, "stopFlap")
+	if err != nil {
+		return err
+	}
+	for _, res := range broadcast {
+		response := &opApi.StopFlapResponse{}
+
+		d := json.NewDecoder(res.Body)
+		err = d.Decode(&response)
+		if err != nil {
+			continue
+		}
+		if response.Error.ErrCode == 0 {
+			return nil
+		}
+		err = errors.New(response.Error.ErrMsg)
+	}
+	if err == nil {
+		err = errors.New("invalid flap id: " + id)
+	}
+	return err
+}
+
+func (app *Leader) ListFlaps() []api.Flap {
+	list := app.flaps.list(app.GetMachineId())
+
+	if len(app.cl.Nodes) == 0 {
+		return list
+	}
+
+	broadcast, err := app.cl.Broadcast(nil, http.MethodGet, "listFlaps")
+	if err != nil {
+		return list
+	}
+	for _, res := range broadcast {
+		response := &opApi.ListFlapsResponse{}
+
+		d := json.NewDecoder(res.Body)
+		err = d.Decode(&response)
+		if err != nil {
+			continue
+		}
+		list = append(list, response.Flaps...)
+	}
+	return list
+}
+
+func (app *Leader) RampNode(id string, direction string, props network.RampProps) (string, error) {
+	n, ok := app.topo.GetNode(id)
+	if !ok {
+		return "", errors.New("invalid node id")
+	}
+	if n.MachineId == app.GetMachineId() {
+		if n.Link == nil {
+			return "", errors.New(id + " is not connected")
+		}
+		return app.ramps.start(NodeDisruption, []string{id}, direction, n.Link.NetworkBILink, direction, props)
+	}
+
+	resp, err := app.cl.SendMsg(n.MachineId, &opApi.RampNodeRequest{
+		Node:       id,
+		Direction:  direction,
+		RampConfig: toRampConfig(props, direction),
+	}, "rampNode")
+	if err != nil {
+		return "", err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &opApi.RampNodeResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return "", err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return "", errors.New(req.Error.ErrMsg)
+	}
+	return req.Id, nil
+}
+
+func (app *Leader) RampBridge(id string, direction string, props network.RampProps) (string, error) {
+	b, ok := app.topo.GetBridge(id)
+	if !ok {
+		return "", errors.New("invalid bridge id")
+	}
+	if b.MachineId == app.GetMachineId() {
+		if b.RouterLink == nil {
+			return "", errors.New(id + " is not connected to a router")
+		}
+		return app.ramps.start(BridgeDisruption, []string{id}, direction, b.RouterLink.NetworkBILink, direction, props)
+	}
+
+	resp, err := app.cl.SendMsg(b.MachineId, &opApi.RampBridgeRequest{
+		Bridge:     id,
+		Direction:  direction,
+		RampConfig: toRampConfig(props, direction),
+	}, "rampBridge")
+	if err != nil {
+		return "", err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &opApi.RampBridgeResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return "", err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return "", errors.New(req.Error.ErrMsg)
+	}
+	return req.Id, nil
+}
+
+func (app *Leader) RampRouters(router1Id string, router2Id string, direction string, props network.RampProps) (string, error) {
+	r1, ok := app.topo.GetRouter(router1Id)
+	if !ok {
+		return "", errors.New("invalid router id: " + router1Id)
+	}
+	r2, ok := app.topo.GetRouter(router2Id)
+	if !ok {
+		return "", errors.New("invalid router id: " + router2Id)
+	}
+	if r1.MachineId != r2.MachineId {
+		return "", errors.New("could not ramp connection between remote routers")
+	}
+	if r1.MachineId == app.GetMachineId() {
+		l, ok := r1.RouterLinks[r2.ID()]
+		if !ok {
+			return "", errors.New(router1Id + " and " + router2Id + " are not connected")
+		}
+		return app.ramps.start(RoutersDisruption, []string{router1Id, router2Id}, direction, l.NetworkBILink, routerLinkDirection(l, router1Id, direction), props)
+	}
+
+	resp, err := app.cl.SendMsg(r1.MachineId, &opApi.RampRoutersRequest{
+		Router1:    router1Id,
+		Router2:    router2Id,
+		Direction:  direction,
+		RampConfig: toRampConfig(props, direction),
+	}, "rampRouters")
+	if err != nil {
+		return "", err
+	}
+
+	d := json.NewDecoder(resp.Body)
+	req := &opApi.RampRoutersResponse{}
+	err = d.Decode(&req)
+
+	if err != nil {
+		return "", err
+	}
+
+	if req.Error.ErrCode != 0 {
+		return "", errors.New(req.Error.ErrMsg)
+	}
+	return req.Id, nil
+}
+
+// Ramps run on the machine of the link, so unknown ids are looked up in the followers
+// Ramps run on the machine of the link, so unknown ids are looked up in the followers
+func (app *Leader) StopRamp(id string, restore bool) error {
+	if app.ramps.contains(id) || len(app.cl.Nodes) == 0 {
+		return app.ramps.stop(id, restore)
+	}
+
+	broadcast, err := app.cl.Broadcast(&opApi.StopRampRequest{Id: id, Restore: restore}, http.MethodPost, "stopRamp")
+	if err != nil {
+		return err
+	}
+	for _, res := range broadcast {
+		response := &opApi.StopRampResponse{}
+
+		d := json.NewDecoder(res.Body)
+		err = d.Decode(&response)
+		if err != nil {
+			continue
+		}
+		if response.Error.ErrCode == 0 {
+			return nil
+		}
+		err = errors.New(response.Error.ErrMsg)
+	}
+	if err == nil {
+		err = errors.New("invalid ramp id: " + id)
+	}
+	return err
+}
+
+func (app *Leader) ReverseRamp(id string) error {
+	if app.ramps.contains(id) || len(app.cl.Nodes) == 0 {
+		return app.ramps.reverse(id)
+	}
+
+	broadcast, err := app.cl.Broadcast(&opApi.ReverseRampRequest{Id: id}, http.MethodPost, "reverseRamp")
+	if err != nil {
+		return err
+	}
+	for _, res := range broadcast {
+		response := &opApi.ReverseRampResponse{}
+
+		d := json.NewDecoder(res.Body)
+		err = d.Decode(&response)
+		if err != nil {
+			continue
+		}
+		if response.Error.ErrCode == 0 {
+			return nil
+		}
+		err = errors.New(response.Error.ErrMsg)
+	}
+	if err == nil {
+		err = errors.New("invalid ramp id: " + id)
+	}
+	return err
+}
+
+func (app *Leader) ListRamps() []api.Ramp {
+	list := app.ramps.list(app.GetMachineId())
+
+	if len(app.cl.Nodes) == 0 {
+		return list
+	}
+
+	broadcast, err := app.cl.Broadcast(nil, http.MethodGet, "listRamps")
+	if err != nil {
+		return list
+	}
+	for _, res := range broadcast {
+		response := &opApi.ListRampsResponse{}
+
+		d := json.NewDecoder(res.Body)
+		err = d.Decode(&response)
+		if err != nil {
+			continue
+		}
+		list = append(list, response.Ramps...)
+	}
+	return list
+}
@@ -0,0 +1,106 @@
+package application
+
+import (
+	"github.com/David-Antunes/gone/internal/cluster"
+	"github.com/David-Antunes/gone/internal/metrics"
+	"github.com/David-Antunes/gone/internal/network"
+	"github.com/David-Antunes/gone/internal/proxy"
+	"github.com/David-Antunes/gone/internal/topology"
+	"net"
+)
+
+// Collects the counters of the components emulated by this machine
+func collectMetrics(machineId string, topo *topology.Topology, p *proxy.Proxy, icm *cluster.InterCommunicationManager) *metrics.Registry {
+	reg := metrics.NewRegistry()
+
+	seen := make(map[*topology.BiLink]bool)
+	for _, link := range topo.GetBiLinks() {
+		seen[link] = true
+		if link.NetworkBILink == nil {
+			continue
+		}
+		collectLinkMetrics(reg, machineId, link.ID(), "upstream", link.ConnectsTo)
+		collectLinkMetrics(reg, machineId, link.ID(), "downstream", link.ConnectsFrom)
+	}
+
+	for _, r := range topo.GetRouters() {
+		if r.MachineId != machineId {
+			continue
+		}
+		collectComponentMetrics(reg, machineId, "router", r.ID(), r.NetworkRouter.GetStats(), r.NetworkRouter.IsDisrupted())
+
+		// Remote router links are only kept by the routers
+		for _, link := range r.RouterLinks {
+			if seen[link] || link.NetworkBILink != nil {
+				continue
+			}
+			seen[link] = true
+			if link.ConnectsTo != nil && link.ConnectsTo.From.ID() == r.ID() {
+				collectLinkMetrics(reg, machineId, link.ID(), "upstream", link.ConnectsTo)
+			}
+		}
+	}
+
+	for _, b := range topo.GetBridges() {
+		if b.MachineId != machineId {
+			continue
+		}
+		collectComponentMetrics(reg, machineId, "bridge", b.ID(), b.NetworkBridge.GetStats(), b.NetworkBridge.IsDisrupted())
+	}
+
+	for mac, stats := range p.GetStats() {
+		labels := []string{"machine", machineId, "mac", net.HardwareAddr(mac).String()}
+		reg.Counter("gone_proxy_frames_total", "Frames exchanged with a container.", stats.RxFrames, append(labels, "direction", "rx")...)
+		reg.Counter("gone_proxy_frames_total", "Frames exchanged with a container.", stats.TxFrames, append(labels, "direction", "tx")...)
+		reg.Counter("gone_proxy_bytes_total", "Bytes exchanged with a container.", stats.RxBytes, append(labels, "direction", "rx")...)
+		reg.Counter("gone_proxy_bytes_total", "Bytes exchanged with a container.", stats.TxBytes, append(labels, "direction", "tx")...)
+		reg.Counter("gone_proxy_drops_total", "Frames from a container dropped because the emulation queue was full.", stats.Drops, labels...)
+	}
+
+	stats := icm.GetStats()
+	reg.Counter("gone_remote_frames_total", "Frames exchanged with other machines.", stats.Frames, "machine", machineId, "direction", "rx")
+	reg.Counter("gone_remote_bytes_total", "Bytes exchanged with other machines.", stats.Bytes, "machine", machineId, "direction", "rx")
+	reg.Counter("gone_remote_drops_total", "Frames from other machines dropped by reason.", stats.QueueDrops, "machine", machineId, "reason", "queue")
+	reg.Counter("gone_remote_drops_total", "Frames from other machines dropped by reason.", stats.UnroutedFrames, "machine", machineId, "reason", "unrouted")
+	for remote, s := range stats.Machines {
+		reg.Counter("gone_remote_frames_total", "Frames exchanged with other machines.", s.Frames, "machine", machineId, "direction", "tx", "remote", remote)
+		reg.Counter("gone_remote_bytes_total", "Bytes exchanged with other machines.", s.Bytes, "machine", machineId, "direction", "tx", "remote", remote)
+		reg.Counter("gone_remote_errors_total", "Frames that failed to be sent to another machine.", s.Errors, "machine", machineId, "remote", remote)
+	}
+
+	return reg
+}
+
+func collectLinkMetrics(reg *metrics.Registry, machineId string, id string, direction string, link *topology.Link) {
+	if link == nil || link.NetworkLink == nil {
+		return
+	}
+	shaper := link.NetworkLink.GetShaper()
+	stats := shaper.GetStats()
+	labels := []string{"machine", machineId, "link", id, "direction", direction, "from", link.From.ID(), "to", link.To.ID()}
+
+	reg.Counter("gone_link_frames_total", "Frames forwarded by a link.", stats.Frames, labels...)
+	reg.Counter("gone_link_bytes_total", "Bytes forwarded by a link.", stats.Bytes, labels...)
+	reg.Counter("gone_link_drops_total", "Frames dropped by a link by reason.", stats.LossDrops, append(labels, "reason", "loss")...)
+	reg.Counter("gone_link_drops_total", "Frames dropped by a link by reason.", stats.QueueDrops, append(labels, "reason", "queue")...)
+	reg.Counter("gone_link_drops_total", "Frames dropped by a link by reason.", stats.DisruptedDrops, append(labels, "reason", "disrupted")...)
+	reg.Gauge("gone_link_queue_depth", "Frames waiting in a link queue.", float64(stats.QueueDepth), labels...)
+	reg.Gauge("gone_link_disrupted", "Whether a link is disrupted.", metrics.Bool(shaper.IsDisrupted()), labels...)
+	reg.Gauge("gone_link_info", "Shaper handling a link.", 1, append(labels, "shaper", network.ShaperType(shaper))...)
+}
+
+func collectComponentMetrics(reg *metrics.Registry, machineId string, component string, id string, stats network.ComponentStats, disrupted bool) {
+	labels := []string{"machine", machineId, component, id}
+	prefix := "gone_" + component
+
+	reg.Counter(prefix+"_frames_total", "Frames forwarded by a "+component+".", stats.Frames, labels...)
+	reg.Counter(prefix+"_bytes_total", "Bytes forwarded by a "+component+".", stats.Bytes, labels...)
+	reg.Counter(prefix+"_drops_total", "Frames dropped by a "+component+" by reason.", stats.QueueDrops, append(labels, "reason", "queue")...)
+	reg.Counter(prefix+"_drops_total", "Frames dropped by a "+component+" by reason.", stats.DisruptedDrops, append(labels, "reason", "disrupted")...)
+	reg.Counter(prefix+"_drops_total", "Frames dropped by a "+component+" by reason.", stats.PartitionDrops, append(labels, "reason", "partition")...)
+	reg.Gauge(prefix+"_queue_depth", "Frames waiting in a "+component+" queue.", float64(stats.QueueDepth), labels...)
+	reg.Gauge(prefix+"_disrupted", "Whether a "+component+" is disrupted.", metrics.Bool(disrupted), labels...)
+	if component == "router" {
+		reg.Counter("gone_router_unknown_frames_total", "Frames whose destination was unknown to a router.", stats.UnknownFrames, labels...)
+	}
+}
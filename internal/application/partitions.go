@@ -0,0 +1,118 @@
+package application
+
+import (
+	"errors"
+	"github.com/David-Antunes/gone/api"
+	"github.com/David-Antunes/gone/internal/topology"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Partitions applied to this machine. The leader assigns the ids and every machine keeps the same partitions.
+type partitionRegistry struct {
+	sync.Mutex
+	partitions map[string]api.Partition
+	order      []string
+	counter    int
+}
+
+func newPartitionRegistry() *partitionRegistry {
+	return &partitionRegistry{
+		Mutex:      sync.Mutex{},
+		partitions: make(map[string]api.Partition),
+		order:      make([]string, 0),
+		counter:    0,
+	}
+}
+
+func (reg *partitionRegistry) nextId() string {
+	reg.Lock()
+	defer reg.Unlock()
+	id := "partition" + strconv.Itoa(reg.counter)
+	reg.counter++
+	return id
+}
+
+// Resolves the nodes of each group to their mac addresses and blocks the traffic between the groups
+func (reg *partitionRegistry) apply(topo *topology.Topology, id string, groups [][]string) error {
+	macs, err := partitionMacs(topo, groups)
+	if err != nil {
+		return err
+	}
+
+	reg.Lock()
+	defer reg.Unlock()
+	if _, ok := reg.partitions[id]; !ok {
+		reg.order = append(reg.order, id)
+	}
+	reg.partitions[id] = api.Partition{
+		Id:     id,
+		Groups: groups,
+		Start:  time.Now(),
+	}
+	topo.GetPartitions().Add(id, macs)
+	return nil
+}
+
+func (reg *partitionRegistry) heal(topo *topology.Topology, id string) error {
+	reg.Lock()
+	defer reg.Unlock()
+	if _, ok := reg.partitions[id]; !ok {
+		return errors.New("invalid partition id: " + id)
+	}
+	delete(reg.partitions, id)
+	for i, partition := range reg.order {
+		if partition == id {
+			reg.order = append(reg.order[:i], reg.order[i+1:]...)
+			break
+		}
+	}
+	topo.GetPartitions().Remove(id)
+	return nil
+}
+
+func (reg *partitionRegistry) healAll(topo *topology.Topology) {
+	reg.Lock()
+	defer reg.Unlock()
+	reg.partitions = make(map[string]api.Partition)
+	reg.order = make([]string, 0)
+	topo.GetPartitions().Clear()
+}
+
+func (reg *partitionRegistry) list() []api.Partition {
+	reg.Lock()
+	defer reg.Unlock()
+	list := make([]api.Partition, 0, len(reg.order))
+	for _, id := range reg.order {
+		list = append(list, reg.partitions[id])
+	}
+	return list
+}
+
+func partitionMacs(topo *topology.Topology, groups [][]string) ([][]string, error) {
+	if len(groups) < 2 {
+		return nil, errors.New("a partition needs at least two groups")
+	}
+	seen := make(map[string]bool)
+	macs := make([][]string, 0, len(groups))
+	for _, group := range groups {
+		if len(group) == 0 {
+			return nil, errors.New("partition groups can't be empty")
+		}
+		groupMacs := make([]string, 0, len(group))
+		for _, id := range group {
+			n, ok := topo.GetNode(id)
+			if !ok {
+				return nil, errors.New("invalid node id: " + id)
+			}
+			if seen[id] {
+				return nil, errors.New(id + " belongs to more than one group")
+			}
+			seen[id] = true
+			groupMacs = append(groupMacs, n.NetworkNode.GetMac())
+		}
+		macs = append(macs, groupMacs)
+	}
+	return macs, nil
+}
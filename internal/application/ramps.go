@@ -0,0 +1,144 @@
+package application
+
+import (
+	"errors"
+	"github.com/David-Antunes/gone/api"
+	"github.com/David-Antunes/gone/internal/network"
+	"sort"
+	"sync"
+	"time"
+)
+
+type ramp struct {
+	id         string
+	kind       string
+	components []string
+	direction  string
+	start      time.Time
+	ramp       *network.Ramp
+}
+
+// Ramps of the links of this machine. Like flaps, a ramp is identified by its link and direction.
+type rampRegistry struct {
+	sync.Mutex
+	ramps map[string]*ramp
+}
+
+func newRampRegistry() *rampRegistry {
+	return &rampRegistry{
+		Mutex: sync.Mutex{},
+		ramps: make(map[string]*ramp),
+	}
+}
+
+// Starts ramping the links of a direction. A finished or stopped ramp of the same link is replaced.
+func (reg *rampRegistry) start(kind string, components []string, direction string, link *network.BiLink, linkDirection string, props network.RampProps) (string, error) {
+	reg.Lock()
+	defer reg.Unlock()
+
+	id := "ramp:" + disruptionId(kind, direction, components...)
+	if r, ok := reg.ramps[id]; ok && r.ramp.Running() {
+		return "", errors.New(id + " is already running")
+	}
+	reg.ramps[id] = &ramp{
+		id:         id,
+		kind:       kind,
+		components: components,
+		direction:  direction,
+		start:      time.Now(),
+		ramp:       network.StartRamp(link.Directions(linkDirection), props),
+	}
+	return id, nil
+}
+
+func (reg *rampRegistry) stop(id string, restore bool) error {
+	reg.Lock()
+	defer reg.Unlock()
+
+	r, ok := reg.ramps[id]
+	if !ok {
+		return errors.New("invalid ramp id: " + id)
+	}
+	if !r.ramp.Stop(restore) {
+		return errors.New(id + " is already stopped")
+	}
+	return nil
+}
+
+func (reg *rampRegistry) reverse(id string) error {
+	reg.Lock()
+	defer reg.Unlock()
+
+	r, ok := reg.ramps[id]
+	if !ok {
+		return errors.New("invalid ramp id: " + id)
+	}
+	if !r.ramp.Reverse() {
+		return errors.New(id + " can't be reversed")
+	}
+	return nil
+}
+
+func (reg *rampRegistry) contains(id string) bool {
+	reg.Lock()
+	defer reg.Unlock()
+	_, ok := reg.ramps[id]
+	return ok
+}
+
+func (reg *rampRegistry) list(machineId string) []api.Ramp {
+	reg.Lock()
+	defer reg.Unlock()
+
+	list := make([]api.Ramp, 0, len(reg.ramps))
+	for _, r := range reg.ramps {
+		state, progress := r.ramp.Status()
+		list = append(list, api.Ramp{
+			Id:         r.id,
+			Type:       r.kind,
+			Components: r.components,
+			Direction:  r.direction,
+			MachineId:  machineId,
+			Config:     toRampConfig(r.ramp.GetProps(), r.direction),
+			State:      state,
+			Progress:   progress,
+			Start:      r.start,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Start.Before(list[j].Start)
+	})
+	return list
+}
+
+// Converts the targets of a ramp back to the request values, doubling the split latency and drop rate of a
+// ramp without a direction.
+func toRampConfig(props network.RampProps, direction string) api.RampConfig {
+	config := api.RampConfig{
+		DurationMs: toMilliseconds(props.Duration),
+		StepMs:     toMilliseconds(props.Step),
+		Curve:      props.Curve,
+		LatencyMs:  nil,
+		DropRate:   nil,
+		Bandwidth:  nil,
+	}
+	if props.Latency != nil {
+		latency := toMilliseconds(*props.Latency)
+		if direction == "" {
+			latency *= 2
+		}
+		config.LatencyMs = &latency
+	}
+	if props.DropRate != nil {
+		dropRate := *props.DropRate
+		if direction == "" {
+			dropRate *= 2
+		}
+		config.DropRate = &dropRate
+	}
+	if props.Bandwidth != nil {
+		bandwidth := *props.Bandwidth * 8
+		config.Bandwidth = &bandwidth
+	}
+	return config
+}
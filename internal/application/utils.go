@@ -1,10 +1,16 @@
 package application
 
 import (
+	"errors"
 	"github.com/David-Antunes/gone/api"
+	connectApi "github.com/David-Antunes/gone/api/Connect"
 	"github.com/David-Antunes/gone/internal/network"
 	"github.com/David-Antunes/gone/internal/topology"
+	"math"
 	"net"
+	"strconv"
+	"strings"
+	"time"
 )
 
 func sniffSocketPath(id string) string {
@@ -30,6 +36,149 @@ func isSpecialLink(link *topology.Link) bool {
 	return true
 }
 
+// Converts the properties of a single link back into the one-way values accepted by the connect requests
+func toLinkDirection(props network.LinkProps) *connectApi.LinkDirection {
+	latency := float64(props.Latency) / float64(time.Millisecond)
+	bandwidth := props.Bandwidth * 8
+	return &connectApi.LinkDirection{
+		Latency:      &latency,
+		Jitter:       &props.Jitter,
+		DropRate:     &props.DropRate,
+		Bandwidth:    &bandwidth,
+		Loss:         toLossModel(props.Loss),
+		Distribution: toDelayDistribution(props.Distribution),
+		Queue:        toQueueConfig(props.Queue),
+		Trace:        toTrace(props.Trace),
+		Seed:         props.Seed,
+		Impairments: &api.Impairments{
+			Reorder:    props.Reorder,
+			ReorderGap: props.ReorderGap,
+			Duplicate:  props.Duplicate,
+			Corrupt:    props.Corrupt,
+		},
+	}
+}
+
+func toDelayDistribution(dist network.DelayDistribution) *api.DelayDistribution {
+	if dist.Type == "" {
+		return nil
+	}
+	table := make([]string, 0, len(dist.Table))
+	for _, value := range dist.Table {
+		table = append(table, strconv.Itoa(int(math.Round(value*network.DistributionTableScale))))
+	}
+	return &api.DelayDistribution{
+		Type:        dist.Type,
+		Correlation: dist.Correlation,
+		Table:       strings.Join(table, " "),
+	}
+}
+
+func toQueueConfig(queue network.QueueProps) *api.QueueConfig {
+	return &api.QueueConfig{
+		LimitPackets:   queue.LimitPackets,
+		LimitBytes:     queue.LimitBytes,
+		LimitMs:        float64(queue.LimitTime) / float64(time.Millisecond),
+		Discipline:     queue.Discipline,
+		ECN:            queue.ECN,
+		MinThreshold:   queue.MinThreshold,
+		MaxThreshold:   queue.MaxThreshold,
+		MaxProbability: queue.MaxProbability,
+		Target:         float64(queue.Target) / float64(time.Millisecond),
+		Interval:       float64(queue.Interval) / float64(time.Millisecond),
+	}
+}
+
+// Traces are forwarded as CSV, which keeps the points of both formats
+func toTrace(trace *network.Trace) *api.Trace {
+	if !trace.Enabled() {
+		return nil
+	}
+	rows := make([]string, 0, len(trace.Points))
+	for _, point := range trace.Points {
+		row := []string{
+			strconv.FormatFloat(float64(point.Time)/float64(time.Millisecond), 'f', -1, 64),
+			strconv.Itoa(point.Bandwidth * 8),
+		}
+		if trace.HasLatency || trace.HasLoss {
+			row = append(row, strconv.FormatFloat(float64(point.Latency)/float64(time.Millisecond), 'f', -1, 64))
+		}
+		if trace.HasLoss {
+			row = append(row, strconv.FormatFloat(point.DropRate, 'f', -1, 64))
+		}
+		rows = append(rows, strings.Join(row, ","))
+	}
+	return &api.Trace{
+		Format:   network.CSVTrace,
+		Contents: strings.Join(rows, "\n"),
+		Loop:     trace.Loop,
+		OffsetMs: float64(trace.Offset) / float64(time.Millisecond),
+	}
+}
+
+func toLossModel(model network.GilbertElliott) *api.LossModel {
+	if !model.Enabled() {
+		return nil
+	}
+	return &api.LossModel{
+		GoodToBad: model.GoodToBad,
+		BadToGood: model.BadToGood,
+		GoodLoss:  model.GoodLoss,
+		BadLoss:   model.BadLoss,
+	}
+}
+
+// The shaper holds the current properties, including the changes of a trace replay
+func convertToAPILinkProps(link *network.Link) api.LinkProps {
+	if link == nil {
+		return api.LinkProps{}
+	}
+	props := link.GetShaper().GetProps()
+	stats := link.GetQueueStats()
+	return api.LinkProps{
+		Latency:   int(props.Latency),
+		Bandwidth: props.Bandwidth,
+		Jitter:    props.Jitter,
+		DropRate:  props.DropRate,
+		Weight:    props.Weight,
+		Loss: api.LossModel{
+			GoodToBad: props.Loss.GoodToBad,
+			BadToGood: props.Loss.BadToGood,
+			GoodLoss:  props.Loss.GoodLoss,
+			BadLoss:   props.Loss.BadLoss,
+		},
+		Distribution: props.Distribution.Type,
+		Correlation:  props.Distribution.Correlation,
+		Reorder:      props.Reorder,
+		ReorderGap:   props.ReorderGap,
+		Duplicate:    props.Duplicate,
+		Corrupt:      props.Corrupt,
+		Queue:        *toQueueConfig(props.Queue),
+		QueueStats: api.QueueStats{
+			Packets:   stats.Packets,
+			Bytes:     stats.Bytes,
+			TailDrops: stats.TailDrops,
+			AQMDrops:  stats.AQMDrops,
+			ECNMarks:  stats.ECNMarks,
+		},
+		Trace:     traceFormat(props.Trace),
+		TraceLoop: props.Trace.Enabled() && props.Trace.Loop,
+		Seed:      props.Seed,
+	}
+}
+
+// Links of remote components are nil
+func isDisrupted(link *network.Link) bool {
+	return link != nil && link.IsDisrupted()
+}
+
+func traceFormat(trace *network.Trace) string {
+	if !trace.Enabled() {
+		return ""
+	}
+	return trace.Format
+}
+
 func convertToAPINode(n *topology.Node) api.Node {
 	b := ""
 	link := &api.Link{
@@ -40,15 +189,14 @@ func convertToAPINode(n *topology.Node) api.Node {
 	if n.Bridge != nil {
 		b = n.Bridge.ID()
 		link = &api.Link{
-			To:   n.Link.To.ID(),
-			From: n.Link.From.ID(),
-			LinkProps: api.LinkProps{
-				Latency:   int(n.Link.NetworkBILink.Left.GetProps().Latency),
-				Bandwidth: n.Link.NetworkBILink.Left.GetProps().Bandwidth,
-				Jitter:    n.Link.NetworkBILink.Left.GetProps().Jitter,
-				DropRate:  n.Link.NetworkBILink.Left.GetProps().DropRate,
-				Weight:    n.Link.NetworkBILink.Left.GetProps().Weight,
-			},
+			To:         n.Link.To.ID(),
+			From:       n.Link.From.ID(),
+			LinkProps:  convertToAPILinkProps(n.Link.NetworkBILink.Left),
+			Upstream:   convertToAPILinkProps(n.Link.NetworkBILink.Left),
+			Downstream: convertToAPILinkProps(n.Link.NetworkBILink.Right),
+
+			UpstreamDisrupted:   isDisrupted(n.Link.NetworkBILink.Left),
+			DownstreamDisrupted: isDisrupted(n.Link.NetworkBILink.Right),
 		}
 	}
 	return api.Node{
@@ -71,15 +219,14 @@ func convertToAPIBridge(b *topology.Bridge) api.Bridge {
 	if b.Router != nil {
 		r = b.Router.ID()
 		link = &api.Link{
-			To:   b.Router.Id,
-			From: b.ID(),
-			LinkProps: api.LinkProps{
-				Latency:   int(b.RouterLink.ConnectsTo.NetworkLink.GetProps().Latency),
-				Bandwidth: b.RouterLink.ConnectsTo.NetworkLink.GetProps().Bandwidth,
-				Jitter:    b.RouterLink.ConnectsTo.NetworkLink.GetProps().Jitter,
-				DropRate:  b.RouterLink.ConnectsTo.NetworkLink.GetProps().DropRate,
-				Weight:    b.RouterLink.ConnectsTo.NetworkLink.GetProps().Weight,
-			},
+			To:         b.Router.Id,
+			From:       b.ID(),
+			LinkProps:  convertToAPILinkProps(b.RouterLink.ConnectsTo.NetworkLink),
+			Upstream:   convertToAPILinkProps(b.RouterLink.ConnectsTo.NetworkLink),
+			Downstream: convertToAPILinkProps(b.RouterLink.ConnectsFrom.NetworkLink),
+
+			UpstreamDisrupted:   isDisrupted(b.RouterLink.ConnectsTo.NetworkLink),
+			DownstreamDisrupted: isDisrupted(b.RouterLink.ConnectsFrom.NetworkLink),
 		}
 	}
 	return api.Bridge{
@@ -96,16 +243,20 @@ func convertToAPIRouter(r *topology.Router) api.Router {
 	links := make(map[string]api.Link)
 
 	for k, _ := range r.ConnectedRouters {
+		// The downstream direction of a remote link lives in the other machine
+		var downstream *network.Link
+		if r.RouterLinks[k].ConnectsFrom != nil {
+			downstream = r.RouterLinks[k].ConnectsFrom.NetworkLink
+		}
 		links[k] = api.Link{
-			To:   r.RouterLinks[k].To.ID(),
-			From: r.RouterLinks[k].From.ID(),
-			LinkProps: api.LinkProps{
-				Latency:   int(r.RouterLinks[k].ConnectsTo.NetworkLink.GetProps().Latency),
-				Bandwidth: r.RouterLinks[k].ConnectsTo.NetworkLink.GetProps().Bandwidth,
-				Jitter:    r.RouterLinks[k].ConnectsTo.NetworkLink.GetProps().Jitter,
-				DropRate:  r.RouterLinks[k].ConnectsTo.NetworkLink.GetProps().DropRate,
-				Weight:    r.RouterLinks[k].ConnectsTo.NetworkLink.GetProps().Weight,
-			},
+			To:         r.RouterLinks[k].To.ID(),
+			From:       r.RouterLinks[k].From.ID(),
+			LinkProps:  convertToAPILinkProps(r.RouterLinks[k].ConnectsTo.NetworkLink),
+			Upstream:   convertToAPILinkProps(r.RouterLinks[k].ConnectsTo.NetworkLink),
+			Downstream: convertToAPILinkProps(downstream),
+
+			UpstreamDisrupted:   isDisrupted(r.RouterLinks[k].ConnectsTo.NetworkLink),
+			DownstreamDisrupted: isDisrupted(downstream),
 		}
 	}
 
@@ -134,3 +285,144 @@ func convertToAPIRouter(r *topology.Router) api.Router {
 		Weights:   weights,
 	}
 }
+
+// Directions of a router link are given from the first router of a request
+func routerLinkDirection(link *topology.BiLink, router1Id string, direction string) string {
+	if link.ConnectsTo.From.ID() == router1Id {
+		return direction
+	}
+	return reverseDirection(direction)
+}
+
+// Remote router links are not registered in the topology. Their id is <router>-RemoteBiLink-<router>.
+func remoteBiLinkRouters(id string) (string, string, bool) {
+	routers := strings.SplitN(id, "-RemoteBiLink-", 2)
+	if len(routers) != 2 {
+		return "", "", false
+	}
+	return routers[0], routers[1], true
+}
+
+func findBiLink(topo *topology.Topology, id string) (*topology.BiLink, bool) {
+	if link, ok := topo.GetBiLink(id); ok {
+		return link, true
+	}
+	router1, router2, ok := remoteBiLinkRouters(id)
+	if !ok {
+		return nil, false
+	}
+	r, ok := topo.GetRouter(router1)
+	if !ok {
+		return nil, false
+	}
+	link, ok := r.RouterLinks[router2]
+	if !ok || link.ID() != id {
+		return nil, false
+	}
+	return link, true
+}
+
+// Finds the link connecting two components in the local topology
+func findBiLinkBetween(topo *topology.Topology, from string, to string) (*topology.BiLink, error) {
+	for _, pair := range [][2]string{{from, to}, {to, from}} {
+		if n, ok := topo.GetNode(pair[0]); ok && n.Bridge != nil && n.Bridge.ID() == pair[1] {
+			return n.Link, nil
+		}
+		if b, ok := topo.GetBridge(pair[0]); ok && b.Router != nil && b.Router.ID() == pair[1] {
+			return b.RouterLink, nil
+		}
+	}
+	if r, ok := topo.GetRouter(from); ok {
+		if link, ok := r.RouterLinks[to]; ok {
+			return link, nil
+		}
+	}
+	return nil, errors.New(from + " and " + to + " are not connected")
+}
+
+func componentMachine(component topology.Component) string {
+	switch c := component.(type) {
+	case *topology.Node:
+		return c.MachineId
+	case *topology.Bridge:
+		return c.MachineId
+	case *topology.Router:
+		return c.MachineId
+	default:
+		return ""
+	}
+}
+
+func convertToAPILinkDirection(link *topology.Link) api.LinkDirectionState {
+	if link == nil {
+		return api.LinkDirectionState{}
+	}
+	state := api.LinkDirectionState{
+		From:      link.From.ID(),
+		To:        link.To.ID(),
+		MachineId: componentMachine(link.From),
+	}
+	if link.NetworkLink == nil {
+		return state
+	}
+	shaper := link.NetworkLink.GetShaper()
+	stats := shaper.GetStats()
+	state.Shaper = network.ShaperType(shaper)
+	state.Disrupted = shaper.IsDisrupted()
+	state.LinkProps = convertToAPILinkProps(link.NetworkLink)
+	state.Stats = api.LinkStats{
+		Frames:         stats.Frames,
+		Bytes:          stats.Bytes,
+		LossDrops:      stats.LossDrops,
+		QueueDrops:     stats.QueueDrops,
+		DisruptedDrops: stats.DisruptedDrops,
+		QueueDepth:     stats.QueueDepth,
+	}
+	return state
+}
+
+// Converts the directions of link owned by this machine.
+// The downstream direction of a remote router link is left empty.
+func convertToAPILinkState(link *topology.BiLink) api.LinkState {
+	return api.LinkState{
+		Id:         link.ID(),
+		Upstream:   convertToAPILinkDirection(link.ConnectsTo),
+		Downstream: convertToAPILinkDirection(link.ConnectsFrom),
+	}
+}
+
+// Properties of a link owned by this machine, in the form accepted by the update requests. Upstream is the from -> to direction.
+func linkDirectionsBetween(topo *topology.Topology, from string, to string) (*connectApi.LinkDirection, *connectApi.LinkDirection, error) {
+	up, down, _, err := ownedLinkDirections(topo, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	if up == nil || down == nil {
+		return nil, nil, errors.New("link between " + from + " and " + to + " is not owned by this machine")
+	}
+	return up, down, nil
+}
+
+// Properties of the directions of a link owned by this machine and the weight of the link.
+// Upstream is the from -> to direction, and a direction owned by another machine is nil.
+func ownedLinkDirections(topo *topology.Topology, from string, to string) (*connectApi.LinkDirection, *connectApi.LinkDirection, int, error) {
+	link, err := findBiLinkBetween(topo, from, to)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	upLink, downLink := link.ConnectsTo, link.ConnectsFrom
+	if link.ConnectsTo.From.ID() != from {
+		upLink, downLink = downLink, upLink
+	}
+	var up, down *connectApi.LinkDirection
+	weight := 0
+	if upLink != nil && upLink.NetworkLink != nil {
+		up = toLinkDirection(upLink.NetworkLink.GetProps())
+		weight = upLink.NetworkLink.GetProps().Weight
+	}
+	if downLink != nil && downLink.NetworkLink != nil {
+		down = toLinkDirection(downLink.NetworkLink.GetProps())
+		weight = max(weight, downLink.NetworkLink.GetProps().Weight)
+	}
+	return up, down, weight, nil
+}
@@ -0,0 +1,445 @@
+package chaos
+
+import (
+	"errors"
+	"github.com/David-Antunes/gone/api"
+	"log"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var chaosLog = log.New(os.Stdout, "CHAOS INFO: ", log.Ltime)
+
+// Fault types
+const (
+	Disrupt      = "disrupt"
+	LatencySpike = "latencySpike"
+	LossBurst    = "lossBurst"
+	Pause        = "pause"
+	Partition    = "partition"
+)
+
+// Target kinds
+const (
+	NodeTarget    = "node"
+	BridgeTarget  = "bridge"
+	RoutersTarget = "routers"
+)
+
+// Event actions
+const (
+	Inject = "inject"
+	Heal   = "heal"
+)
+
+// Run states
+const (
+	Running  = "running"
+	Finished = "finished"
+	Stopped  = "stopped"
+)
+
+// Largest expected number of faults of a run, which keeps planning a run quick
+const MaxFaults = 10000
+
+// Settings of a chaos run. Rate is the average number of faults injected per second.
+type Config struct {
+	Seed         int64
+	Rate         float64
+	Duration     time.Duration
+	Faults       []string
+	MinFault     time.Duration
+	MaxFault     time.Duration
+	LatencySpike time.Duration
+	LossBurst    float64
+}
+
+// Link or node a fault is applied to. Links are identified by the two components they connect,
+// starting with the node, bridge or router the kind refers to.
+type Target struct {
+	Kind       string
+	Components []string
+}
+
+func (target Target) key() string {
+	return target.Kind + ":" + strings.Join(target.Components, ":")
+}
+
+// Components faults can be applied to. Links receive disruptions, latency spikes and loss bursts,
+// nodes are paused and partitioned.
+type Targets struct {
+	Links []Target
+	Nodes []string
+}
+
+type Fault struct {
+	Offset   time.Duration
+	Duration time.Duration
+	Type     string
+	Target   Target
+	Groups   [][]string
+}
+
+// Injects a fault and returns the function that heals it
+type Injector func(fault Fault) (func() error, error)
+
+func ValidFault(fault string) bool {
+	switch fault {
+	case Disrupt, LatencySpike, LossBurst, Pause, Partition:
+		return true
+	default:
+		return false
+	}
+}
+
+// Builds the faults of a run. The plan only depends on the config and targets, so the same seed
+// over the same topology always produces the same faults.
+func Plan(config Config, targets Targets) ([]Fault, error) {
+	if config.Rate <= 0 {
+		return nil, errors.New("rate must be greater than 0")
+	}
+	if config.Duration <= 0 {
+		return nil, errors.New("duration must be greater than 0 ms")
+	}
+	if config.Rate*config.Duration.Seconds() > MaxFaults {
+		return nil, errors.New("rate and duration can't plan more than " + strconv.Itoa(MaxFaults) + " faults")
+	}
+	if len(config.Faults) == 0 {
+		return nil, errors.New("no fault types were given")
+	}
+	for _, fault := range config.Faults {
+		if !ValidFault(fault) {
+			return nil, errors.New("invalid fault type: " + fault)
+		}
+	}
+	if config.MinFault <= 0 || config.MaxFault < config.MinFault {
+		return nil, errors.New("fault durations must be greater than 0 ms and the maximum can't be lower than the minimum")
+	}
+
+	links := append([]Target{}, targets.Links...)
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].key() < links[j].key()
+	})
+	nodes := append([]string{}, targets.Nodes...)
+	sort.Strings(nodes)
+
+	rng := rand.New(rand.NewSource(config.Seed))
+	busy := make(map[string]time.Duration)
+	faults := make([]Fault, 0)
+	offset := time.Duration(0)
+
+	for {
+		offset += time.Duration(rng.ExpFloat64() / config.Rate * float64(time.Second))
+		if offset >= config.Duration {
+			break
+		}
+		faultType := config.Faults[rng.Intn(len(config.Faults))]
+		duration := config.MinFault
+		if config.MaxFault > config.MinFault {
+			duration += time.Duration(rng.Int63n(int64(config.MaxFault - config.MinFault)))
+		}
+		// Every fault is healed before the run ends
+		duration = min(duration, config.Duration-offset)
+
+		fault := Fault{
+			Offset:   offset,
+			Duration: duration,
+			Type:     faultType,
+		}
+
+		var candidates []Target
+		switch faultType {
+		case Disrupt, LatencySpike, LossBurst:
+			candidates = links
+		case Pause:
+			for _, node := range nodes {
+				candidates = append(candidates, Target{Kind: NodeTarget, Components: []string{node}})
+			}
+		case Partition:
+			if len(nodes) >= 2 {
+				candidates = []Target{{Kind: Partition, Components: []string{}}}
+			}
+		}
+
+		// Faults never overlap on the same target, so each heal restores the state before its fault
+		free := make([]Target, 0, len(candidates))
+		for _, candidate := range candidates {
+			if busy[faultKey(faultType, candidate)] <= offset {
+				free = append(free, candidate)
+			}
+		}
+		if len(free) == 0 {
+			continue
+		}
+		fault.Target = free[rng.Intn(len(free))]
+		busy[faultKey(faultType, fault.Target)] = offset + duration
+
+		if faultType == Partition {
+			fault.Groups = splitNodes(rng, nodes)
+		}
+		faults = append(faults, fault)
+	}
+	return faults, nil
+}
+
+// Link faults share the state of the link, while pauses and partitions are tracked on their own
+func faultKey(faultType string, target Target) string {
+	switch faultType {
+	case Pause, Partition:
+		return faultType + ":" + target.key()
+	default:
+		return target.key()
+	}
+}
+
+// Splits the nodes in two random non empty groups
+func splitNodes(rng *rand.Rand, nodes []string) [][]string {
+	perm := rng.Perm(len(nodes))
+	cut := 1 + rng.Intn(len(nodes)-1)
+	first := make([]string, 0, cut)
+	second := make([]string, 0, len(nodes)-cut)
+	for i, index := range perm {
+		if i < cut {
+			first = append(first, nodes[index])
+		} else {
+			second = append(second, nodes[index])
+		}
+	}
+	sort.Strings(first)
+	sort.Strings(second)
+	return [][]string{first, second}
+}
+
+type step struct {
+	at     time.Duration
+	action string
+	fault  int
+}
+
+type run struct {
+	id     string
+	config Config
+	start  time.Time
+	status string
+	faults []Fault
+	events []api.ChaosEvent
+	ctx    chan struct{}
+}
+
+// Runs chaos plans and keeps the event log of every run
+type Engine struct {
+	sync.Mutex
+	runs    map[string]*run
+	order   []string
+	counter int
+}
+
+func NewEngine() *Engine {
+	return &Engine{
+		Mutex:   sync.Mutex{},
+		runs:    make(map[string]*run),
+		order:   make([]string, 0),
+		counter: 0,
+	}
+}
+
+func (engine *Engine) Start(config Config, targets Targets, inject Injector) (string, error) {
+	faults, err := Plan(config, targets)
+	if err != nil {
+		return "", err
+	}
+
+	engine.Lock()
+	defer engine.Unlock()
+
+	r := &run{
+		id:     "chaos" + strconv.Itoa(engine.counter),
+		config: config,
+		start:  time.Now(),
+		status: Running,
+		faults: faults,
+		events: make([]api.ChaosEvent, 0, 2*len(faults)),
+		ctx:    make(chan struct{}),
+	}
+	engine.counter++
+	engine.runs[r.id] = r
+	engine.order = append(engine.order, r.id)
+
+	chaosLog.Println(r.id+":", "Starting with seed", config.Seed, "and", len(faults), "faults")
+	go engine.run(r, inject)
+	return r.id, nil
+}
+
+func (engine *Engine) run(r *run, inject Injector) {
+	steps := make([]step, 0, 2*len(r.faults))
+	for i, fault := range r.faults {
+		steps = append(steps, step{at: fault.Offset, action: Inject, fault: i})
+		steps = append(steps, step{at: fault.Offset + fault.Duration, action: Heal, fault: i})
+	}
+	// Heals go first so a target is free before its next fault
+	sort.SliceStable(steps, func(i, j int) bool {
+		if steps[i].at == steps[j].at {
+			return steps[i].action == Heal && steps[j].action == Inject
+		}
+		return steps[i].at < steps[j].at
+	})
+
+	heals := make(map[int]func() error)
+	for _, s := range steps {
+		timer := time.NewTimer(time.Until(r.start.Add(s.at)))
+		select {
+		case <-r.ctx:
+			timer.Stop()
+			engine.healAll(r, heals)
+			return
+		case <-timer.C:
+		}
+		select {
+		case <-r.ctx:
+			engine.healAll(r, heals)
+			return
+		default:
+		}
+
+		if s.action == Inject {
+			heal, err := inject(r.faults[s.fault])
+			if err == nil {
+				heals[s.fault] = heal
+			}
+			engine.log(r, s.fault, Inject, err)
+		} else if heal, ok := heals[s.fault]; ok {
+			delete(heals, s.fault)
+			engine.log(r, s.fault, Heal, heal())
+		}
+	}
+
+	engine.Lock()
+	if r.status == Running {
+		r.status = Finished
+	}
+	engine.Unlock()
+	chaosLog.Println(r.id+":", "Finished")
+}
+
+func (engine *Engine) healAll(r *run, heals map[int]func() error) {
+	indexes := make([]int, 0, len(heals))
+	for i := range heals {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		engine.log(r, i, Heal, heals[i]())
+	}
+	chaosLog.Println(r.id+":", "Stopped")
+}
+
+func (engine *Engine) log(r *run, fault int, action string, err error) {
+	event := api.ChaosEvent{
+		Time:     time.Now(),
+		OffsetMs: toMilliseconds(time.Since(r.start)),
+		Action:   action,
+		Fault:    convertToAPIFault(fault, r.faults[fault]),
+	}
+	if err != nil {
+		event.Error = err.Error()
+		chaosLog.Println(r.id+":", action, r.faults[fault].Type, r.faults[fault].Target.key(), "failed:", err)
+	} else {
+		chaosLog.Println(r.id+":", action, r.faults[fault].Type, r.faults[fault].Target.key())
+	}
+
+	engine.Lock()
+	r.events = append(r.events, event)
+	engine.Unlock()
+}
+
+// Stops a run and heals its active faults
+func (engine *Engine) Stop(id string) error {
+	engine.Lock()
+	defer engine.Unlock()
+
+	r, ok := engine.runs[id]
+	if !ok {
+		return errors.New("invalid chaos id: " + id)
+	}
+	if r.status != Running {
+		return errors.New(id + " is already " + r.status)
+	}
+	r.status = Stopped
+	close(r.ctx)
+	return nil
+}
+
+func (engine *Engine) Get(id string) (api.ChaosRun, bool) {
+	engine.Lock()
+	defer engine.Unlock()
+
+	r, ok := engine.runs[id]
+	if !ok {
+		return api.ChaosRun{}, false
+	}
+	return convertToAPIRun(r), true
+}
+
+func (engine *Engine) List() []api.ChaosRun {
+	engine.Lock()
+	defer engine.Unlock()
+
+	runs := make([]api.ChaosRun, 0, len(engine.order))
+	for _, id := range engine.order {
+		runs = append(runs, convertToAPIRun(engine.runs[id]))
+	}
+	return runs
+}
+
+func (engine *Engine) Events(id string) ([]api.ChaosEvent, bool) {
+	engine.Lock()
+	defer engine.Unlock()
+
+	r, ok := engine.runs[id]
+	if !ok {
+		return nil, false
+	}
+	return append([]api.ChaosEvent{}, r.events...), true
+}
+
+func convertToAPIRun(r *run) api.ChaosRun {
+	faults := make([]api.ChaosFault, 0, len(r.faults))
+	for i, fault := range r.faults {
+		faults = append(faults, convertToAPIFault(i, fault))
+	}
+	return api.ChaosRun{
+		Id:             r.id,
+		Seed:           r.config.Seed,
+		Rate:           r.config.Rate,
+		DurationMs:     toMilliseconds(r.config.Duration),
+		Faults:         r.config.Faults,
+		MinFaultMs:     toMilliseconds(r.config.MinFault),
+		MaxFaultMs:     toMilliseconds(r.config.MaxFault),
+		LatencySpikeMs: toMilliseconds(r.config.LatencySpike),
+		LossBurst:      r.config.LossBurst,
+		Start:          r.start,
+		Status:         r.status,
+		Plan:           faults,
+	}
+}
+
+func convertToAPIFault(index int, fault Fault) api.ChaosFault {
+	return api.ChaosFault{
+		Index:      index,
+		OffsetMs:   toMilliseconds(fault.Offset),
+		DurationMs: toMilliseconds(fault.Duration),
+		Type:       fault.Type,
+		Kind:       fault.Target.Kind,
+		Components: fault.Target.Components,
+		Groups:     fault.Groups,
+	}
+}
+
+func toMilliseconds(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
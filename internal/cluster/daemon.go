@@ -95,3 +95,14 @@ func (cd *ClusterDaemon) RegisterClusterNode(w http.ResponseWriter, r *http.Requ
 
 	clusterLog.Println("Added", req.Hostname, req.IpAddr, req.UdpAddr)
 }
+
+// Describes this machine and the machines it knows
+func (cd *ClusterDaemon) ListMachines(w http.ResponseWriter, r *http.Request) {
+	resp := &ClusterNodeResponse{
+		Hostname: cd.Cl.Primary,
+		IpAddr:   cd.ipAddr,
+		UdpAddr:  cd.udpAddr,
+		Nodes:    cd.Cl.Nodes,
+	}
+	daemon.SendResponse(w, resp)
+}
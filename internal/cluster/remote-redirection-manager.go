@@ -9,6 +9,7 @@ import (
 	"net"
 	"os"
 	"sync"
+	"sync/atomic"
 )
 
 var icmLog = log.New(os.Stdout, "REMOTE INFO: ", log.Ltime)
@@ -24,6 +25,32 @@ type InterCommunicationManager struct {
 	routers       map[string]*network.Router
 	ctx           chan struct{}
 	running       bool
+	counters      *icmCounters
+	machines      map[string]*icmCounters
+}
+
+// Frames exchanged with other machines. Frames and Bytes count the received frames and Machines the frames sent to each machine.
+type ICMStats struct {
+	Frames         uint64
+	Bytes          uint64
+	QueueDrops     uint64
+	UnroutedFrames uint64
+	Machines       map[string]ICMMachineStats
+}
+
+type ICMMachineStats struct {
+	Frames uint64
+	Bytes  uint64
+	Errors uint64
+}
+
+type icmCounters struct {
+	frames     atomic.Uint64
+	bytes      atomic.Uint64
+	queueDrops atomic.Uint64
+	// Frames whose destination router is unknown to this machine
+	unroutedFrames atomic.Uint64
+	errors         atomic.Uint64
 }
 
 func (icm *InterCommunicationManager) GetoutQueue() chan *network.RouterFrame {
@@ -42,6 +69,8 @@ func CreateInterCommunicationManager() *InterCommunicationManager {
 		routers:       make(map[string]*network.Router),
 		ctx:           make(chan struct{}),
 		running:       false,
+		counters:      &icmCounters{},
+		machines:      make(map[string]*icmCounters),
 	}
 }
 func (icm *InterCommunicationManager) SetConnection(conn net.Listener) {
@@ -83,7 +112,8 @@ func (icm *InterCommunicationManager) AddMachine(conn net.Conn, machineId string
 	defer icm.Unlock()
 	if _, ok := icm.connections[machineId]; !ok {
 		icm.connections[machineId] = make(chan *network.RouterFrame, internal.QueueSize)
-		go sendMachine(conn, icm.connections[machineId])
+		icm.machines[machineId] = &icmCounters{}
+		go sendMachine(conn, icm.connections[machineId], icm.machines[machineId])
 	}
 }
 
@@ -119,6 +149,7 @@ func (icm *InterCommunicationManager) receiveFrames() {
 				//frame.Frame.Time = frame.Frame.Time.Add(-icm.delays[frame.From].Value)
 				router.RemoteInjectFrame(frame.Frame)
 			} else {
+				icm.counters.unroutedFrames.Add(1)
 				fmt.Println("No router for ", frame.To)
 			}
 			icm.RUnlock()
@@ -126,14 +157,18 @@ func (icm *InterCommunicationManager) receiveFrames() {
 	}
 }
 
-func sendMachine(conn net.Conn, channel chan *network.RouterFrame) {
+func sendMachine(conn net.Conn, channel chan *network.RouterFrame, counters *icmCounters) {
 	enc := gob.NewEncoder(conn)
 	for {
 		select {
 
 		case frame := <-channel:
 			if err := enc.Encode(frame); err != nil {
+				counters.errors.Add(1)
 				fmt.Println("Error:", err)
+			} else {
+				counters.frames.Add(1)
+				counters.bytes.Add(uint64(frame.Frame.FrameSize))
 			}
 		}
 	}
@@ -150,6 +185,10 @@ func (icm *InterCommunicationManager) receive(conn net.Conn) {
 		}
 		if len(icm.inQueue) < internal.RemoteQueueSize {
 			icm.inQueue <- frame
+			icm.counters.frames.Add(1)
+			icm.counters.bytes.Add(uint64(frame.Frame.FrameSize))
+		} else {
+			icm.counters.queueDrops.Add(1)
 		}
 	}
 }
@@ -164,9 +203,30 @@ func (icm *InterCommunicationManager) send() {
 			if conn, ok := icm.remoteRouters[frame.To]; ok {
 				conn <- frame
 			} else {
+				icm.counters.unroutedFrames.Add(1)
 				fmt.Println("No router for ", frame.To)
 			}
 			icm.RUnlock()
 		}
 	}
 }
+
+func (icm *InterCommunicationManager) GetStats() ICMStats {
+	icm.RLock()
+	defer icm.RUnlock()
+	stats := ICMStats{
+		Frames:         icm.counters.frames.Load(),
+		Bytes:          icm.counters.bytes.Load(),
+		QueueDrops:     icm.counters.queueDrops.Load(),
+		UnroutedFrames: icm.counters.unroutedFrames.Load(),
+		Machines:       make(map[string]ICMMachineStats, len(icm.machines)),
+	}
+	for machineId, counters := range icm.machines {
+		stats.Machines[machineId] = ICMMachineStats{
+			Frames: counters.frames.Load(),
+			Bytes:  counters.bytes.Load(),
+			Errors: counters.errors.Load(),
+		}
+	}
+	return stats
+}
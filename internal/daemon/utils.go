@@ -1,11 +1,18 @@
 package daemon
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/David-Antunes/gone/api"
+	connectApi "github.com/David-Antunes/gone/api/Connect"
 	"github.com/David-Antunes/gone/internal/network"
+	"gopkg.in/yaml.v3"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -67,25 +74,509 @@ func ParseLinkProps(latency float64, bandwidth int, jitter float64, dropRate flo
 	}, nil
 }
 
-func ParseLinkPropsInternal(latency time.Duration, bandwidth int, jitter float64, dropRate float64, weight int) (network.LinkProps, error) {
-	if latency < time.Millisecond*0 {
+// Parses the symmetric link properties and overrides the upstream and downstream directions when given
+func ParseBiLinkProps(latency float64, bandwidth int, jitter float64, dropRate float64, weight int, loss *api.LossModel, impairments api.Impairments, distribution *api.DelayDistribution, queue *api.QueueConfig, trace *api.Trace, upstream *connectApi.LinkDirection, downstream *connectApi.LinkDirection) (network.LinkProps, network.LinkProps, error) {
+	t, err := ParseTrace(trace, true)
+	if err != nil {
+		return network.LinkProps{}, network.LinkProps{}, err
+	}
+	if t != nil && bandwidth == 0 {
+		bandwidth = traceBandwidth(t)
+	}
+	props, err := ParseLinkProps(latency, bandwidth, jitter, dropRate, weight)
+	if err != nil {
+		return network.LinkProps{}, network.LinkProps{}, err
+	}
+	props.Trace = t
+	props.Loss, err = ParseLossModel(loss)
+	if err != nil {
+		return network.LinkProps{}, network.LinkProps{}, err
+	}
+	props, err = ParseImpairments(props, impairments)
+	if err != nil {
+		return network.LinkProps{}, network.LinkProps{}, err
+	}
+	props.Distribution, err = ParseDelayDistribution(distribution)
+	if err != nil {
+		return network.LinkProps{}, network.LinkProps{}, err
+	}
+	props.Queue, err = ParseQueueConfig(queue)
+	if err != nil {
+		return network.LinkProps{}, network.LinkProps{}, err
+	}
+	up, err := ParseLinkDirection(props, upstream)
+	if err != nil {
+		return network.LinkProps{}, network.LinkProps{}, errors.New("upstream " + err.Error())
+	}
+	down, err := ParseLinkDirection(props, downstream)
+	if err != nil {
+		return network.LinkProps{}, network.LinkProps{}, errors.New("downstream " + err.Error())
+	}
+	return up, down, nil
+}
+
+// Converts one-way properties into the properties of a single link. Returns props if direction is nil.
+// Fields the direction doesn't set keep their value in props.
+func ParseLinkDirection(props network.LinkProps, direction *connectApi.LinkDirection) (network.LinkProps, error) {
+	if direction == nil {
+		return props, nil
+	}
+	latency := props.Latency
+	if direction.Latency != nil {
+		latency = time.Duration(*direction.Latency * float64(time.Millisecond))
+	}
+	bandwidth := props.Bandwidth * 8
+	if direction.Bandwidth != nil {
+		bandwidth = *direction.Bandwidth
+	}
+	if direction.Trace != nil {
+		trace, err := ParseTrace(direction.Trace, false)
+		if err != nil {
+			return network.LinkProps{}, err
+		}
+		props.Trace = trace
+		if direction.Bandwidth == nil {
+			bandwidth = traceBandwidth(trace)
+		}
+	}
+	if direction.Jitter != nil {
+		props.Jitter = *direction.Jitter
+	}
+	if direction.DropRate != nil {
+		props.DropRate = *direction.DropRate
+	}
+	if latency < 0 {
 		return network.LinkProps{}, errors.New("latency can't be lower than 0 ms")
-	} else if bandwidth < 10 {
+	} else if bandwidth < 12000 {
 		return network.LinkProps{}, errors.New("bandwidth can't be lower than 1.5 kbps")
-	} else if jitter < 0 {
+	} else if props.Jitter < 0 {
 		return network.LinkProps{}, errors.New("jitter can't be lower than 0")
-	} else if dropRate < 0 || dropRate > 1 {
+	} else if props.DropRate < 0 || props.DropRate > 1 {
 		return network.LinkProps{}, errors.New("drop rate must be between 0 and 1")
-	} else if weight < 0 {
-		return network.LinkProps{}, errors.New("weight can't be lower than 0")
 	}
-	return network.LinkProps{
-		Latency:   latency / 2.0,
-		FLatency:  float64(latency),
-		Bandwidth: bandwidth / 8,
-		Jitter:    jitter / 2.0,
-		DropRate:  dropRate / 2.0,
-		Weight:    weight,
+	if direction.Loss != nil {
+		loss, err := ParseLossModel(direction.Loss)
+		if err != nil {
+			return network.LinkProps{}, err
+		}
+		props.Loss = loss
+	}
+	if direction.Distribution != nil {
+		dist, err := ParseDelayDistribution(direction.Distribution)
+		if err != nil {
+			return network.LinkProps{}, err
+		}
+		props.Distribution = dist
+	}
+	if direction.Queue != nil {
+		queue, err := ParseQueueConfig(direction.Queue)
+		if err != nil {
+			return network.LinkProps{}, err
+		}
+		props.Queue = queue
+	}
+	if direction.Impairments != nil {
+		var err error
+		props, err = ParseImpairments(props, *direction.Impairments)
+		if err != nil {
+			return network.LinkProps{}, err
+		}
+	}
+	props.Latency = latency
+	props.FLatency = float64(latency) / float64(time.Millisecond) * 2.0
+	props.Bandwidth = bandwidth / 8
+	if direction.Seed != 0 {
+		props.Seed = direction.Seed
+	}
+	return props, nil
+}
+
+// Converts a Gilbert-Elliott loss model. The model is applied as is to each direction of the link.
+func ParseLossModel(model *api.LossModel) (network.GilbertElliott, error) {
+	if model == nil {
+		return network.GilbertElliott{}, nil
+	}
+	for _, p := range []float64{model.GoodToBad, model.BadToGood, model.GoodLoss, model.BadLoss} {
+		if p < 0 || p > 1 {
+			return network.GilbertElliott{}, errors.New("loss model probabilities must be between 0 and 1")
+		}
+	}
+	return network.GilbertElliott{
+		GoodToBad: model.GoodToBad,
+		BadToGood: model.BadToGood,
+		GoodLoss:  model.GoodLoss,
+		BadLoss:   model.BadLoss,
+	}, nil
+}
+
+// Applies the reorder, duplicate and corrupt impairments to props. Like the loss model, they are not split between directions.
+func ParseImpairments(props network.LinkProps, impairments api.Impairments) (network.LinkProps, error) {
+	if impairments.Reorder < 0 || impairments.Reorder > 1 {
+		return network.LinkProps{}, errors.New("reorder probability must be between 0 and 1")
+	} else if impairments.ReorderGap < 0 {
+		return network.LinkProps{}, errors.New("reorder gap can't be lower than 0")
+	} else if impairments.Duplicate < 0 || impairments.Duplicate > 1 {
+		return network.LinkProps{}, errors.New("duplicate probability must be between 0 and 1")
+	} else if impairments.Corrupt < 0 || impairments.Corrupt > 1 {
+		return network.LinkProps{}, errors.New("corrupt probability must be between 0 and 1")
+	}
+	props.Reorder = impairments.Reorder
+	props.ReorderGap = impairments.ReorderGap
+	props.Duplicate = impairments.Duplicate
+	props.Corrupt = impairments.Corrupt
+	return props, nil
+}
+
+func ParseDelayDistribution(dist *api.DelayDistribution) (network.DelayDistribution, error) {
+	if dist == nil {
+		return network.DelayDistribution{}, nil
+	}
+	if dist.Correlation < 0 || dist.Correlation > 1 {
+		return network.DelayDistribution{}, errors.New("distribution correlation must be between 0 and 1")
+	}
+	switch dist.Type {
+	case network.NormalDistribution, network.UniformDistribution, network.ParetoDistribution, network.ParetoNormalDistribution:
+		return network.DelayDistribution{
+			Type:        dist.Type,
+			Correlation: dist.Correlation,
+		}, nil
+	case network.TableDistribution:
+		table, err := ParseDistributionTable(dist.Table)
+		if err != nil {
+			return network.DelayDistribution{}, err
+		}
+		return network.DelayDistribution{
+			Type:        dist.Type,
+			Correlation: dist.Correlation,
+			Table:       table,
+		}, nil
+	default:
+		return network.DelayDistribution{}, errors.New("unknown delay distribution " + dist.Type)
+	}
+}
+
+// Parses the contents of a netem .dist file. Lines starting with # are ignored.
+func ParseDistributionTable(contents string) ([]float64, error) {
+	table := make([]float64, 0)
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			value, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, errors.New("invalid distribution table value " + field)
+			}
+			table = append(table, float64(value)/network.DistributionTableScale)
+		}
+	}
+	if len(table) == 0 {
+		return nil, errors.New("distribution table is empty")
+	}
+	return table, nil
+}
+
+// Parses a bandwidth trace. Latency and drop rate are split between both directions of the link when halve is set,
+// like the symmetric link properties.
+func ParseTrace(trace *api.Trace, halve bool) (*network.Trace, error) {
+	if trace == nil {
+		return nil, nil
+	}
+	if trace.OffsetMs < 0 {
+		return nil, errors.New("trace offset can't be lower than 0 ms")
+	}
+	var t *network.Trace
+	var err error
+	switch trace.Format {
+	case network.MahimahiTrace:
+		t, err = ParseMahimahiTrace(trace.Contents)
+	case network.CSVTrace:
+		t, err = ParseCSVTrace(trace.Contents)
+	default:
+		return nil, errors.New("unknown trace format " + trace.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.Loop = trace.Loop
+	t.Offset = time.Duration(trace.OffsetMs * float64(time.Millisecond))
+	if !t.Loop && t.Offset >= t.Duration {
+		return nil, errors.New("trace offset is past the end of the trace")
+	}
+	if halve {
+		for i := range t.Points {
+			t.Points[i].Latency = t.Points[i].Latency / 2
+			t.Points[i].DropRate = t.Points[i].DropRate / 2
+		}
+	}
+	return t, nil
+}
+
+// Parses a mahimahi trace. Delivery opportunities are grouped in windows of network.TraceWindow.
+func ParseMahimahiTrace(contents string) (*network.Trace, error) {
+	windowMs := int(network.TraceWindow / time.Millisecond)
+	opportunities := make([]int, 0)
+	last := 0
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		timestamp, err := strconv.Atoi(line)
+		if err != nil || timestamp < 0 {
+			return nil, errors.New("invalid mahimahi timestamp " + line)
+		} else if timestamp < last {
+			return nil, errors.New("mahimahi timestamps must not decrease")
+		}
+		last = timestamp
+		window := max(timestamp-1, 0) / windowMs
+		for len(opportunities) <= window {
+			opportunities = append(opportunities, 0)
+		}
+		opportunities[window]++
+	}
+	if len(opportunities) == 0 {
+		return nil, errors.New("trace is empty")
+	}
+
+	points := make([]network.TracePoint, 0, len(opportunities))
+	for i, count := range opportunities {
+		points = append(points, network.TracePoint{
+			Time:      time.Duration(i) * network.TraceWindow,
+			Bandwidth: int(float64(count*network.TraceOpportunitySize) / network.TraceWindow.Seconds()),
+		})
+	}
+	return &network.Trace{
+		Format:   network.MahimahiTrace,
+		Points:   points,
+		Duration: time.Duration(len(points)) * network.TraceWindow,
+	}, nil
+}
+
+// Parses a CSV trace of time,bandwidth[,latency[,loss]] rows. A header row is skipped.
+// The last row lasts as long as the interval before it.
+func ParseCSVTrace(contents string) (*network.Trace, error) {
+	points := make([]network.TracePoint, 0)
+	columns := 0
+	for i, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		values := make([]float64, 0, len(fields))
+		for _, field := range fields {
+			value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				break
+			}
+			values = append(values, value)
+		}
+		if len(values) != len(fields) {
+			if len(points) == 0 && columns == 0 {
+				columns = -1
+				continue
+			}
+			return nil, errors.New("invalid trace row at line " + strconv.Itoa(i+1))
+		}
+		if len(values) < 2 || len(values) > 4 {
+			return nil, errors.New("trace rows must have between 2 and 4 columns")
+		} else if len(points) > 0 && len(values) != columns {
+			return nil, errors.New("trace rows must have the same number of columns")
+		}
+		columns = len(values)
+
+		point := network.TracePoint{
+			Time:      time.Duration(values[0] * float64(time.Millisecond)),
+			Bandwidth: int(values[1]) / 8,
+		}
+		if point.Time < 0 {
+			return nil, errors.New("trace time can't be lower than 0 ms")
+		} else if len(points) > 0 && point.Time <= points[len(points)-1].Time {
+			return nil, errors.New("trace time must increase")
+		} else if values[1] < 0 {
+			return nil, errors.New("trace bandwidth can't be lower than 0")
+		}
+		if columns > 2 {
+			if values[2] < 0 {
+				return nil, errors.New("trace latency can't be lower than 0 ms")
+			}
+			point.Latency = time.Duration(values[2] * float64(time.Millisecond))
+		}
+		if columns > 3 {
+			if values[3] < 0 || values[3] > 1 {
+				return nil, errors.New("trace drop rate must be between 0 and 1")
+			}
+			point.DropRate = values[3]
+		}
+		points = append(points, point)
+	}
+	if len(points) == 0 {
+		return nil, errors.New("trace is empty")
+	}
+
+	last := network.TraceWindow
+	if len(points) > 1 {
+		last = points[len(points)-1].Time - points[len(points)-2].Time
+	}
+	return &network.Trace{
+		Format:     network.CSVTrace,
+		Points:     points,
+		Duration:   points[len(points)-1].Time + last,
+		HasLatency: columns > 2,
+		HasLoss:    columns > 3,
+	}, nil
+}
+
+// Initial bandwidth in bits per second of a link driven by a trace
+func traceBandwidth(trace *network.Trace) int {
+	return max(trace.Points[0].Bandwidth*8, 12000)
+}
+
+func ParseQueueConfig(queue *api.QueueConfig) (network.QueueProps, error) {
+	if queue == nil {
+		return network.QueueProps{}, nil
+	}
+	if queue.LimitPackets < 0 || queue.LimitBytes < 0 || queue.LimitMs < 0 {
+		return network.QueueProps{}, errors.New("queue limits can't be lower than 0")
+	} else if queue.MinThreshold < 0 || queue.MaxThreshold < 0 {
+		return network.QueueProps{}, errors.New("RED thresholds can't be lower than 0")
+	} else if queue.MaxProbability < 0 || queue.MaxProbability > 1 {
+		return network.QueueProps{}, errors.New("RED max probability must be between 0 and 1")
+	} else if queue.Target < 0 || queue.Interval < 0 {
+		return network.QueueProps{}, errors.New("CoDel target and interval can't be lower than 0")
+	}
+	discipline := queue.Discipline
+	switch discipline {
+	case "":
+		discipline = network.TailDrop
+	case network.TailDrop, network.RED, network.CoDel, network.FQCoDel:
+	default:
+		return network.QueueProps{}, errors.New("unknown queue discipline " + discipline)
+	}
+	return network.QueueProps{
+		LimitPackets:   queue.LimitPackets,
+		LimitBytes:     queue.LimitBytes,
+		LimitTime:      time.Duration(queue.LimitMs * float64(time.Millisecond)),
+		Discipline:     discipline,
+		ECN:            queue.ECN,
+		MinThreshold:   queue.MinThreshold,
+		MaxThreshold:   queue.MaxThreshold,
+		MaxProbability: queue.MaxProbability,
+		Target:         time.Duration(queue.Target * float64(time.Millisecond)),
+		Interval:       time.Duration(queue.Interval * float64(time.Millisecond)),
+	}, nil
+}
+
+// Converts a duration in milliseconds. A zero duration means the operation doesn't expire.
+func ParseDuration(durationMs float64) (time.Duration, error) {
+	if durationMs < 0 {
+		return 0, errors.New("duration can't be lower than 0 ms")
+	}
+	return time.Duration(durationMs * float64(time.Millisecond)), nil
+}
+
+// Validates the direction of a link operation. An empty direction applies to both directions.
+func ParseDirection(direction string) (string, error) {
+	switch direction {
+	case "", network.Upstream, network.Downstream:
+		return direction, nil
+	default:
+		return "", errors.New("invalid direction: " + direction)
+	}
+}
+
+func ParseFlapConfig(config api.FlapConfig) (network.FlapProps, error) {
+	if config.UpMs <= 0 || config.DownMs <= 0 {
+		return network.FlapProps{}, errors.New("up and down times must be greater than 0 ms")
+	}
+	if config.UpMaxMs < 0 || config.DownMaxMs < 0 {
+		return network.FlapProps{}, errors.New("maximum up and down times can't be lower than 0 ms")
+	}
+	if config.Cycles < 0 {
+		return network.FlapProps{}, errors.New("cycles can't be lower than 0")
+	}
+	return network.FlapProps{
+		Up:      time.Duration(config.UpMs * float64(time.Millisecond)),
+		UpMax:   time.Duration(config.UpMaxMs * float64(time.Millisecond)),
+		Down:    time.Duration(config.DownMs * float64(time.Millisecond)),
+		DownMax: time.Duration(config.DownMaxMs * float64(time.Millisecond)),
+		Cycles:  config.Cycles,
 	}, nil
+}
+
+// Parses the targets of a ramp. Without a direction the latency and drop rate are split between both
+// directions, like the symmetric values of ParseLinkProps.
+func ParseRampConfig(config api.RampConfig, direction string) (network.RampProps, error) {
+	if config.DurationMs <= 0 {
+		return network.RampProps{}, errors.New("ramp duration must be greater than 0 ms")
+	}
+	if config.StepMs < 0 {
+		return network.RampProps{}, errors.New("ramp step can't be lower than 0 ms")
+	}
+	switch config.Curve {
+	case "", network.LinearRamp, network.ExponentialRamp:
+	default:
+		return network.RampProps{}, errors.New("invalid ramp curve: " + config.Curve)
+	}
+	if config.LatencyMs == nil && config.DropRate == nil && config.Bandwidth == nil {
+		return network.RampProps{}, errors.New("ramp needs a latency, drop rate or bandwidth target")
+	}
 
+	props := network.RampProps{
+		Duration: time.Duration(config.DurationMs * float64(time.Millisecond)),
+		Step:     time.Duration(config.StepMs * float64(time.Millisecond)),
+		Curve:    config.Curve,
+	}
+	if config.LatencyMs != nil {
+		if *config.LatencyMs < 0 {
+			return network.RampProps{}, errors.New("latency can't be lower than 0 ms")
+		}
+		latency := time.Duration(*config.LatencyMs * float64(time.Millisecond))
+		if direction == "" {
+			latency /= 2
+		}
+		props.Latency = &latency
+	}
+	if config.DropRate != nil {
+		if *config.DropRate < 0 || *config.DropRate > 1 {
+			return network.RampProps{}, errors.New("drop rate must be between 0 and 1")
+		}
+		dropRate := *config.DropRate
+		if direction == "" {
+			dropRate /= 2
+		}
+		props.DropRate = &dropRate
+	}
+	if config.Bandwidth != nil {
+		if *config.Bandwidth < 12000 {
+			return network.RampProps{}, errors.New("bandwidth can't be lower than 1.5 kbps")
+		}
+		bandwidth := *config.Bandwidth / 8
+		props.Bandwidth = &bandwidth
+	}
+	return props, nil
+}
+
+// Decodes a YAML or JSON document. YAML is converted to JSON first, so documents use the json field names in both formats.
+func ParseDocument(r *http.Request, document any) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	var contents any
+	if err = yaml.Unmarshal(body, &contents); err != nil {
+		return err
+	}
+	if contents == nil {
+		return errors.New("document is empty")
+	}
+	converted, err := json.Marshal(contents)
+	if err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(converted))
+	d.DisallowUnknownFields()
+	return d.Decode(document)
 }
@@ -0,0 +1,22 @@
+package dashboard
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// Path the dashboard is served on
+const Path = "/dashboard/"
+
+//go:embed static
+var static embed.FS
+
+// Serves the dashboard files. The page only talks to the endpoints of the daemon that serves it.
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	return http.StripPrefix(Path, http.FileServer(http.FS(files)))
+}
@@ -0,0 +1,355 @@
+"use strict";
+
+// Refresh period in milliseconds
+const refresh = 2000;
+
+const svgNs = "http://www.w3.org/2000/svg";
+const radius = {node: 7, bridge: 10, router: 13};
+
+let graph = {nodes: [], links: []};
+let kinds = {};
+let positions = {};
+let previous = {};
+let rates = {};
+let selected = null;
+
+// Sends a request to an endpoint of the leader. Like the Operations responses of the API,
+// pause, disrupt and the sniffer, intercept and disruption lists return their error in error, the others in err.
+async function call(endpoint, body) {
+  const response = await fetch("/" + endpoint, {method: "POST", body: JSON.stringify(body || {})});
+  const text = await response.text();
+  let result;
+  try {
+    result = JSON.parse(text);
+  } catch (e) {
+    throw new Error(text || response.statusText);
+  }
+  const error = result.err || result.error;
+  if (error && error.err_code !== 0) {
+    throw new Error(error.err_msg);
+  }
+  return result;
+}
+
+function setMessage(id, message, error) {
+  const e = document.getElementById(id);
+  e.textContent = message;
+  e.className = error ? "error" : "";
+}
+
+function linkKey(link) {
+  return link.source + " - " + link.target;
+}
+
+function formatBandwidth(bps) {
+  const units = ["bps", "Kbps", "Mbps", "Gbps"];
+  let unit = 0;
+  while (bps >= 1000 && unit < units.length - 1) {
+    bps /= 1000;
+    unit++;
+  }
+  return Number(bps.toPrecision(4)) + units[unit];
+}
+
+function linkStates(link) {
+  return ["disrupted", "sniffed", "intercepted"].filter(state => link[state]);
+}
+
+// Throughput and drops per second since the previous refresh
+function updateRates(now) {
+  const current = {};
+  for (const link of graph.links) {
+    const key = linkKey(link);
+    const last = previous[key];
+    current[key] = {time: now, bytes: link.bytes, drops: link.drops};
+    if (last && now > last.time && link.bytes >= last.bytes && link.drops >= last.drops) {
+      const seconds = (now - last.time) / 1000;
+      rates[key] = {
+        throughput: (link.bytes - last.bytes) * 8 / seconds,
+        drops: (link.drops - last.drops) / seconds,
+      };
+    } else {
+      rates[key] = {throughput: 0, drops: 0};
+    }
+  }
+  previous = current;
+}
+
+// Both directions can use the whole bandwidth of the link
+function utilisation(link) {
+  const rate = rates[linkKey(link)];
+  if (!rate || link.bandwidth === 0) {
+    return 0;
+  }
+  return rate.throughput / (2 * link.bandwidth);
+}
+
+// Force-directed layout. Components keep their positions between refreshes.
+function layout(width, height, iterations) {
+  const nodes = graph.nodes;
+  for (const n of nodes) {
+    if (!positions[n.id]) {
+      positions[n.id] = {x: width / 2 + (Math.random() - 0.5) * width / 2, y: height / 2 + (Math.random() - 0.5) * height / 2};
+    }
+  }
+  const k = Math.sqrt(width * height / Math.max(nodes.length, 1)) * 0.6;
+  for (let i = 0; i < iterations; i++) {
+    const forces = {};
+    for (const n of nodes) {
+      forces[n.id] = {x: 0, y: 0};
+    }
+    for (let a = 0; a < nodes.length; a++) {
+      for (let b = a + 1; b < nodes.length; b++) {
+        const p = positions[nodes[a].id], q = positions[nodes[b].id];
+        const dx = p.x - q.x, dy = p.y - q.y;
+        const distance = Math.max(Math.hypot(dx, dy), 1);
+        const force = k * k / distance;
+        forces[nodes[a].id].x += dx / distance * force;
+        forces[nodes[a].id].y += dy / distance * force;
+        forces[nodes[b].id].x -= dx / distance * force;
+        forces[nodes[b].id].y -= dy / distance * force;
+      }
+    }
+    for (const l of graph.links) {
+      const p = positions[l.source], q = positions[l.target];
+      if (!p || !q) {
+        continue;
+      }
+      const dx = p.x - q.x, dy = p.y - q.y;
+      const distance = Math.max(Math.hypot(dx, dy), 1);
+      const force = distance * distance / k;
+      forces[l.source].x -= dx / distance * force;
+      forces[l.source].y -= dy / distance * force;
+      forces[l.target].x += dx / distance * force;
+      forces[l.target].y += dy / distance * force;
+    }
+    const step = 10 * (1 - i / iterations) + 1;
+    for (const n of nodes) {
+      const f = forces[n.id], p = positions[n.id];
+      const length = Math.max(Math.hypot(f.x, f.y), 1);
+      p.x = Math.min(width - 20, Math.max(20, p.x + f.x / length * step));
+      p.y = Math.min(height - 20, Math.max(20, p.y + f.y / length * step));
+    }
+  }
+}
+
+function machineColor(machines, machineId) {
+  const index = machines.indexOf(machineId);
+  return "hsl(" + (index * 137) % 360 + ", 60%, 55%)";
+}
+
+function element(name, attributes, parent) {
+  const e = document.createElementNS(svgNs, name);
+  for (const [key, value] of Object.entries(attributes)) {
+    e.setAttribute(key, value);
+  }
+  parent.appendChild(e);
+  return e;
+}
+
+function drawGraph(changed) {
+  const svg = document.getElementById("graph");
+  const width = svg.clientWidth, height = svg.clientHeight;
+  layout(width, height, changed ? 300 : 10);
+
+  const machines = [...new Set(graph.nodes.map(n => n.machineId))].sort();
+  svg.replaceChildren();
+
+  for (const l of graph.links) {
+    const p = positions[l.source], q = positions[l.target];
+    const classes = ["link", ...linkStates(l)];
+    if (selected === linkKey(l)) {
+      classes.push("selected");
+    }
+    const line = element("line", {
+      x1: p.x, y1: p.y, x2: q.x, y2: q.y,
+      class: classes.join(" "),
+      "stroke-width": 2 + 8 * Math.min(utilisation(l), 1),
+    }, svg);
+    element("title", {}, line).textContent = linkKey(l) + "\n" + l.latency + "ms " + formatBandwidth(l.bandwidth);
+    line.addEventListener("click", () => select(linkKey(l)));
+  }
+
+  for (const n of graph.nodes) {
+    const p = positions[n.id];
+    if (machines.length > 1) {
+      element("circle", {cx: p.x, cy: p.y, r: radius[n.kind] + 3, class: "machine", stroke: machineColor(machines, n.machineId)}, svg);
+    }
+    let shape;
+    if (n.kind === "node") {
+      shape = element("rect", {x: p.x - 7, y: p.y - 7, width: 14, height: 14, class: "component node"}, svg);
+    } else {
+      shape = element("circle", {cx: p.x, cy: p.y, r: radius[n.kind], class: "component " + n.kind}, svg);
+    }
+    element("title", {}, shape).textContent = n.kind + " " + n.id + (n.machineId ? "\n" + n.machineId : "");
+    element("text", {x: p.x + radius[n.kind] + 4, y: p.y + 4}, svg).textContent = n.id;
+  }
+}
+
+function fillTable(id, rows, onClick) {
+  const body = document.querySelector("#" + id + " tbody");
+  body.replaceChildren();
+  for (const row of rows) {
+    const tr = document.createElement("tr");
+    for (const cell of row.cells) {
+      const td = document.createElement("td");
+      td.textContent = cell;
+      tr.appendChild(td);
+    }
+    if (row.className) {
+      tr.className = row.className;
+    }
+    if (onClick) {
+      tr.addEventListener("click", () => onClick(row.key));
+    }
+    body.appendChild(tr);
+  }
+}
+
+function drawLinks() {
+  fillTable("links", graph.links.map(l => {
+    const rate = rates[linkKey(l)] || {throughput: 0, drops: 0};
+    return {
+      key: linkKey(l),
+      className: selected === linkKey(l) ? "selected" : "",
+      cells: [
+        l.source, l.target, Number(l.latency.toPrecision(4)) + "ms", formatBandwidth(l.bandwidth),
+        Number((l.dropRate * 100).toPrecision(4)) + "%", formatBandwidth(rate.throughput),
+        (utilisation(l) * 100).toFixed(1) + "%", rate.drops.toFixed(1), l.drops, linkStates(l).join(", "),
+      ],
+    };
+  }), select);
+}
+
+function drawMachines(cluster) {
+  const components = {};
+  for (const n of graph.nodes) {
+    components[n.machineId] = (components[n.machineId] || 0) + 1;
+  }
+  const rows = [{cells: [(cluster.hostname || "local") + " (leader)", cluster.ipAddr, components[cluster.hostname] || 0]}];
+  for (const id of Object.keys(cluster.nodes || {}).sort()) {
+    rows.push({cells: [id, cluster.nodes[id].ipAddr, components[id] || 0]});
+  }
+  fillTable("machines", rows);
+}
+
+function drawLists(disruptions, sniffers, intercepts) {
+  fillTable("disruptions", (disruptions || []).map(d => ({
+    cells: [d.Id, d.MachineId, d.DurationMs > 0 ? (d.RemainingMs / 1000).toFixed(1) + "s" : "until stopped"],
+  })));
+  fillTable("sniffers", (sniffers || []).map(s => ({cells: [s.Id, s.From + " - " + s.To, s.Path]})));
+  fillTable("intercepts", (intercepts || []).map(i => ({cells: [i.Id, i.From + " - " + i.To, i.Path]})));
+}
+
+async function update() {
+  try {
+    const [graphResponse, cluster, disruptions, sniffers, intercepts] = await Promise.all([
+      call("graph"), call("listMachines"), call("listDisruptions"), call("listSniffers"), call("listIntercepts"),
+    ]);
+    const ids = graph.nodes.map(n => n.id).join() + graph.links.map(linkKey).join();
+    graph = graphResponse.graph;
+    kinds = Object.fromEntries(graph.nodes.map(n => [n.id, n.kind]));
+    updateRates(Date.now());
+    const changed = ids !== graph.nodes.map(n => n.id).join() + graph.links.map(linkKey).join();
+
+    drawGraph(changed);
+    drawLinks();
+    drawMachines(cluster);
+    drawLists(disruptions.disruptions, sniffers.sniffers, intercepts.intercepts);
+    setMessage("status", "Updated " + new Date().toLocaleTimeString());
+  } catch (e) {
+    setMessage("status", e.message, true);
+  }
+}
+
+function selectedLink() {
+  return graph.links.find(l => linkKey(l) === selected);
+}
+
+function select(key) {
+  selected = key;
+  const link = selectedLink();
+  document.getElementById("selected").textContent = key;
+  const form = document.getElementById("update");
+  form.latency.value = link.latency;
+  form.jitter.value = 0;
+  form.dropRate.value = link.dropRate;
+  form.bandwidth.value = link.bandwidth;
+  drawGraph(false);
+  drawLinks();
+}
+
+// Links run from node to bridge, bridge to router or router to router, so the source names the endpoint
+function linkRequest(link, suffix) {
+  switch (kinds[link.source]) {
+    case "node":
+      return {endpoint: "Node" + suffix, body: {node: link.source}};
+    case "bridge":
+      return {endpoint: "Bridge" + suffix, body: {bridge: link.source}};
+    default:
+      return {endpoint: "Router" + suffix, body: {router1: link.source, router2: link.target}};
+  }
+}
+
+// Runs an action and reports its result
+async function run(description, action) {
+  try {
+    await action();
+    setMessage("result", description);
+  } catch (e) {
+    setMessage("result", description + ": " + e.message, true);
+  }
+  update();
+}
+
+function withLink(description, action) {
+  const link = selectedLink();
+  if (!link) {
+    setMessage("result", "No link selected", true);
+    return;
+  }
+  run(description + " " + linkKey(link), () => action(link));
+}
+
+document.getElementById("pause").addEventListener("click", () =>
+  run("Paused all nodes", () => call("pause", {id: "", all: true})));
+
+document.getElementById("unpause").addEventListener("click", () =>
+  run("Unpaused all nodes", () => call("unpause", {id: "", all: true})));
+
+document.getElementById("disrupt").addEventListener("submit", event => {
+  event.preventDefault();
+  const form = event.target;
+  withLink("Disrupted", link => {
+    const request = linkRequest(link, "");
+    const endpoint = request.endpoint === "Router" ? "disruptRouters" : "disrupt" + request.endpoint;
+    return call(endpoint, {...request.body, direction: form.direction.value, durationMs: Number(form.durationMs.value)});
+  });
+});
+
+document.getElementById("stopDisrupt").addEventListener("click", () => {
+  const form = document.getElementById("disrupt");
+  withLink("Stopped the disruption of", link => {
+    const request = linkRequest(link, "");
+    const endpoint = request.endpoint === "Router" ? "stopDisruptRouters" : "stopDisrupt" + request.endpoint;
+    return call(endpoint, {...request.body, direction: form.direction.value});
+  });
+});
+
+document.getElementById("update").addEventListener("submit", event => {
+  event.preventDefault();
+  const form = event.target;
+  withLink("Updated", link => {
+    const request = linkRequest(link, "Link");
+    return call("update" + request.endpoint, {
+      ...request.body,
+      latency: Number(form.latency.value),
+      jitter: Number(form.jitter.value),
+      dropRate: Number(form.dropRate.value),
+      bandwidth: Number(form.bandwidth.value),
+    });
+  });
+});
+
+update();
+setInterval(update, refresh);
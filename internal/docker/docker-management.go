@@ -16,6 +16,7 @@ type dockerNode struct {
 	id        string
 	mac       string
 	ip        string
+	dockerCmd []string
 }
 
 type DockerManager struct {
@@ -38,7 +39,11 @@ func (d *DockerManager) GetMachineId() string {
 	return d.machineId
 }
 
-func (d *DockerManager) RegisterContainer(machineId string, id string, mac string, ip string) error {
+func (d *DockerManager) GetNetwork() string {
+	return d.ns
+}
+
+func (d *DockerManager) RegisterContainer(machineId string, id string, mac string, ip string, dockerCmd []string) error {
 	if d.machineId == "" && machineId != "" {
 		return errors.New("emulation is running locally")
 	} else if _, ok := d.nodes[machineId]; ok {
@@ -49,6 +54,7 @@ func (d *DockerManager) RegisterContainer(machineId string, id string, mac strin
 		id:        id,
 		mac:       mac,
 		ip:        ip,
+		dockerCmd: dockerCmd,
 	}
 	return nil
 }
@@ -328,3 +334,118 @@ func (d *DockerManager) UnpauseAll() {
 		}
 	}
 }
+
+// Stops the container of a node without removing it. Killed containers don't get the chance to shut down.
+func (d *DockerManager) Crash(id string, kill bool) error {
+	if _, ok := d.nodes[id]; !ok {
+		return errors.New("container not found")
+	}
+
+	// Paused containers can't be stopped
+	paused, err := d.inspect(id, "{{.State.Paused}}")
+	if err != nil {
+		dockerLog.Println("Could not fetch container state", err)
+		return err
+	}
+	if paused == "true" {
+		if err = d.Unpause(id); err != nil {
+			return err
+		}
+	}
+
+	var shell *exec.Cmd
+	if kill {
+		shell = exec.Command("docker", "kill", id)
+	} else {
+		shell = exec.Command("docker", "stop", id)
+	}
+	_, err = shell.Output()
+	if err != nil {
+		dockerLog.Println("Could not stop container", err)
+		return err
+	}
+	return nil
+}
+
+// Starts a crashed container. The container keeps the mac and ip address it was registered with,
+// and the proxy is refreshed to capture the traffic of its new interface.
+func (d *DockerManager) Restart(id string) error {
+	container, ok := d.nodes[id]
+	if !ok {
+		return errors.New("container not found")
+	}
+
+	_, err := exec.Command("docker", "start", id).Output()
+	if err != nil {
+		dockerLog.Println("Could not start container", err)
+		return err
+	}
+
+	pid, err := d.inspect(id, "{{.State.Pid}}")
+	if err != nil {
+		dockerLog.Println("Could not fetch namespace id", err)
+		return err
+	}
+
+	mac, err := d.inspect(id, "{{.NetworkSettings.Networks."+d.ns+".MacAddress}}")
+	if err != nil {
+		dockerLog.Println("Could not fetch container Mac Address", err)
+		return err
+	}
+	if mac != container.mac {
+		out, err := exec.Command("nsenter", "--target", pid, "--net", "ip", "link", "set", "dev", "eth0", "address", container.mac).Output()
+		if err != nil {
+			dockerLog.Println(string(out))
+			dockerLog.Println("Could not restore container Mac Address", err)
+			return err
+		}
+	}
+
+	ip, err := d.inspect(id, "{{.NetworkSettings.Networks."+d.ns+".IPAddress}}")
+	if err != nil {
+		dockerLog.Println("Could not fetch container Ip Address", err)
+		return err
+	}
+	if ip != container.ip {
+		prefix, err := d.inspect(id, "{{.NetworkSettings.Networks."+d.ns+".IPPrefixLen}}")
+		if err != nil {
+			dockerLog.Println("Could not fetch container Ip prefix", err)
+			return err
+		}
+		exec.Command("nsenter", "--target", pid, "--net", "ip", "addr", "del", ip+"/"+prefix, "dev", "eth0").Output()
+		out, err := exec.Command("nsenter", "--target", pid, "--net", "ip", "addr", "add", container.ip+"/"+prefix, "dev", "eth0").Output()
+		if err != nil {
+			dockerLog.Println(string(out))
+			dockerLog.Println("Could not restore container Ip Address", err)
+			return err
+		}
+	}
+
+	err = d.proxy.Refresh()
+	if err != nil {
+		dockerLog.Println("Could not refresh proxy", err)
+		return err
+	}
+	return nil
+}
+
+func (d *DockerManager) inspect(id string, format string) (string, error) {
+	out, err := exec.Command("docker", "inspect", id, "--format", format).Output()
+	if err != nil {
+		return "", err
+	}
+	value := strings.Trim(string(out), " ")
+	return strings.Trim(value, "\n"), nil
+}
+
+// Mac and ip address the container was registered with
+func (d *DockerManager) GetAddresses(id string) (string, string, bool) {
+	container, ok := d.nodes[id]
+	return container.mac, container.ip, ok
+}
+
+// Command that created the container
+func (d *DockerManager) GetDockerCmd(id string) ([]string, bool) {
+	container, ok := d.nodes[id]
+	return container.dockerCmd, ok
+}
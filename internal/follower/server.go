@@ -5,6 +5,7 @@ import (
 	"github.com/David-Antunes/gone/internal"
 	"github.com/David-Antunes/gone/internal/application"
 	"github.com/David-Antunes/gone/internal/cluster"
+	"github.com/David-Antunes/gone/internal/metrics"
 	"log"
 	"net"
 	"net/http"
@@ -43,6 +44,7 @@ func createDaemon(app *application.Follower, cd *cluster.ClusterDaemon, ipAddr s
 
 	s := &server{&httpServer, socket, app, cd, false}
 	m.HandleFunc("/ping", ping)
+	m.HandleFunc("/metrics", exportMetrics)
 
 	m.HandleFunc("/registerNode", registerNode)
 	m.HandleFunc("/clearNode", clearNode)
@@ -59,6 +61,9 @@ func createDaemon(app *application.Follower, cd *cluster.ClusterDaemon, ipAddr s
 	m.HandleFunc("/inspectNode", inspectNode)
 	m.HandleFunc("/inspectBridge", inspectBridge)
 	m.HandleFunc("/inspectRouter", inspectRouter)
+	m.HandleFunc("/inspectLink", inspectLink)
+	m.HandleFunc("/inspectLinkRemote", inspectLinkRemote)
+	m.HandleFunc("/linkDirectionsRemote", linkDirectionsRemote)
 
 	m.HandleFunc("/removeNode", removeNode)
 	m.HandleFunc("/removeBridge", removeBridge)
@@ -69,6 +74,11 @@ func createDaemon(app *application.Follower, cd *cluster.ClusterDaemon, ipAddr s
 	m.HandleFunc("/disconnectRouters", disconnectRouters)
 	m.HandleFunc("/localDisconnect", localDisconnect)
 
+	m.HandleFunc("/updateNodeLink", updateNodeLink)
+	m.HandleFunc("/updateBridgeLink", updateBridgeLink)
+	m.HandleFunc("/updateRouterLink", updateRouterLink)
+	m.HandleFunc("/updateRouterLinkRemote", updateRouterLinkRemote)
+
 	m.HandleFunc("/weights", routerWeights)
 	m.HandleFunc("/trade", trade)
 
@@ -93,6 +103,24 @@ func createDaemon(app *application.Follower, cd *cluster.ClusterDaemon, ipAddr s
 	m.HandleFunc("/stopDisruptNode", stopDisruptNode)
 	m.HandleFunc("/stopDisruptBridge", stopDisruptBridge)
 	m.HandleFunc("/stopDisruptRouters", stopDisruptRouters)
+	m.HandleFunc("/listDisruptions", listDisruptions)
+
+	m.HandleFunc("/applyPartition", applyPartition)
+	m.HandleFunc("/heal", heal)
+	m.HandleFunc("/listPartitions", listPartitions)
+
+	m.HandleFunc("/flapNode", flapNode)
+	m.HandleFunc("/flapBridge", flapBridge)
+	m.HandleFunc("/flapRouters", flapRouters)
+	m.HandleFunc("/stopFlap", stopFlap)
+	m.HandleFunc("/listFlaps", listFlaps)
+
+	m.HandleFunc("/rampNode", rampNode)
+	m.HandleFunc("/rampBridge", rampBridge)
+	m.HandleFunc("/rampRouters", rampRouters)
+	m.HandleFunc("/stopRamp", stopRamp)
+	m.HandleFunc("/reverseRamp", reverseRamp)
+	m.HandleFunc("/listRamps", listRamps)
 
 	m.HandleFunc("/stopBridge", stopBridge)
 	m.HandleFunc("/stopRouter", stopRouter)
@@ -101,6 +129,8 @@ func createDaemon(app *application.Follower, cd *cluster.ClusterDaemon, ipAddr s
 
 	m.HandleFunc("/pause", pause)
 	m.HandleFunc("/unpause", unpause)
+	m.HandleFunc("/crashNode", crashNode)
+	m.HandleFunc("/restartNode", restartNode)
 
 	m.HandleFunc("/registerClusterNode", cd.RegisterClusterNode)
 
@@ -122,6 +152,13 @@ func ping(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+func exportMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", metrics.ContentType)
+	if err := engine.app.Metrics().Write(w); err != nil {
+		daemonLog.Println("metrics:", err)
+	}
+}
+
 func (server *server) profile(w http.ResponseWriter, r *http.Request) {
 
 	if !server.profiling {
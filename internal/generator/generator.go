@@ -0,0 +1,395 @@
+package generator
+
+import (
+	"errors"
+	topologyApi "github.com/David-Antunes/gone/api/Topology"
+	"math"
+	"math/rand"
+	"strconv"
+)
+
+// Shapes
+const (
+	Star     = "star"
+	Line     = "line"
+	Ring     = "ring"
+	Mesh     = "mesh"
+	Tree     = "tree"
+	FatTree  = "fatTree"
+	Dumbbell = "dumbbell"
+	Waxman   = "waxman"
+	Barabasi = "barabasi"
+)
+
+// Ways of spreading the routers over the machines
+const (
+	RoundRobin = "roundRobin"
+	Blocks     = "blocks"
+)
+
+// Defaults of the shape parameters
+const (
+	DefaultDegree = 2
+	DefaultAlpha  = 0.4
+	DefaultBeta   = 0.1
+)
+
+// Parameters of a generated topology, as described by topologyApi.GenerateRequest
+type Config struct {
+	Shape    string
+	Prefix   string
+	Routers  int
+	Bridges  int
+	Degree   int
+	Alpha    float64
+	Beta     float64
+	Seed     int64
+	Machines []string
+	Spread   string
+}
+
+// Routers of a shape and the links between them. Bridges are connected to the edge routers.
+type graph struct {
+	routers    int
+	links      [][2]int
+	edge       []int
+	bottleneck int
+}
+
+func (g *graph) connect(r1 int, r2 int) {
+	g.links = append(g.links, [2]int{r1, r2})
+}
+
+func allRouters(n int) []int {
+	routers := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		routers = append(routers, i)
+	}
+	return routers
+}
+
+// Builds a document with the routers, bridges and links of a shape. Router links use link,
+// except the link between the two sides of a dumbbell, which uses bottleneck.
+// Each bridge is placed in the machine of its router.
+func Generate(config Config, link topologyApi.LinkProperties, bottleneck topologyApi.LinkProperties) (topologyApi.Document, error) {
+	if config.Bridges < 0 {
+		return topologyApi.Document{}, errors.New("number of bridges can't be lower than 0")
+	}
+	if len(config.Machines) == 0 {
+		return topologyApi.Document{}, errors.New("no machines to spread the topology over")
+	}
+	if config.Degree == 0 {
+		config.Degree = DefaultDegree
+	}
+
+	g, err := buildGraph(config)
+	if err != nil {
+		return topologyApi.Document{}, err
+	}
+
+	machines, err := spread(g.routers, config.Machines, config.Spread)
+	if err != nil {
+		return topologyApi.Document{}, err
+	}
+
+	doc := topologyApi.Document{
+		Nodes:     make([]topologyApi.NodeSpec, 0),
+		Bridges:   make([]topologyApi.ComponentSpec, 0, config.Bridges),
+		Routers:   make([]topologyApi.ComponentSpec, 0, g.routers),
+		Links:     make([]topologyApi.LinkSpec, 0, len(g.links)+config.Bridges),
+		Propagate: true,
+		Unpause:   false,
+	}
+	routerName := func(i int) string {
+		return config.Prefix + "r" + strconv.Itoa(i)
+	}
+
+	for i := 0; i < g.routers; i++ {
+		doc.Routers = append(doc.Routers, topologyApi.ComponentSpec{
+			Name:      routerName(i),
+			MachineId: machines[i],
+		})
+	}
+	for i, l := range g.links {
+		props := link
+		if g.bottleneck >= 0 && i == g.bottleneck {
+			props = bottleneck
+		}
+		doc.Links = append(doc.Links, topologyApi.LinkSpec{
+			From:           routerName(l[0]),
+			To:             routerName(l[1]),
+			LinkProperties: props,
+		})
+	}
+	for i := 0; i < config.Bridges; i++ {
+		router := g.edge[i%len(g.edge)]
+		name := config.Prefix + "b" + strconv.Itoa(i)
+		doc.Bridges = append(doc.Bridges, topologyApi.ComponentSpec{
+			Name:      name,
+			MachineId: machines[router],
+		})
+		doc.Links = append(doc.Links, topologyApi.LinkSpec{
+			From:           name,
+			To:             routerName(router),
+			LinkProperties: link,
+		})
+	}
+	return doc, nil
+}
+
+func buildGraph(config Config) (*graph, error) {
+	n := config.Routers
+	if config.Shape != FatTree && config.Shape != Dumbbell && n < 1 {
+		return nil, errors.New("number of routers must be at least 1")
+	}
+	g := &graph{
+		routers:    n,
+		links:      make([][2]int, 0),
+		edge:       nil,
+		bottleneck: -1,
+	}
+
+	switch config.Shape {
+	case Star:
+		for i := 1; i < n; i++ {
+			g.connect(0, i)
+		}
+		g.edge = allRouters(n)[min(1, n-1):]
+	case Line:
+		for i := 1; i < n; i++ {
+			g.connect(i-1, i)
+		}
+		g.edge = allRouters(n)
+	case Ring:
+		if n < 3 {
+			return nil, errors.New("a ring needs at least 3 routers")
+		}
+		for i := 1; i < n; i++ {
+			g.connect(i-1, i)
+		}
+		g.connect(n-1, 0)
+		g.edge = allRouters(n)
+	case Mesh:
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				g.connect(i, j)
+			}
+		}
+		g.edge = allRouters(n)
+	case Tree:
+		if config.Degree < 1 {
+			return nil, errors.New("degree of a tree must be at least 1")
+		}
+		g.edge = make([]int, 0)
+		for i := 1; i < n; i++ {
+			g.connect((i-1)/config.Degree, i)
+		}
+		for i := 0; i < n; i++ {
+			// Routers without children
+			if i*config.Degree+1 >= n {
+				g.edge = append(g.edge, i)
+			}
+		}
+	case FatTree:
+		return fatTree(config.Degree)
+	case Dumbbell:
+		return dumbbell(n)
+	case Waxman:
+		return waxman(n, config.Alpha, config.Beta, config.Seed)
+	case Barabasi:
+		return barabasi(n, config.Degree, config.Seed)
+	default:
+		return nil, errors.New("unknown shape: " + config.Shape)
+	}
+	return g, nil
+}
+
+// k-ary fat-tree: (k/2)^2 core routers and k pods of k/2 aggregation and k/2 edge routers.
+// Routers are numbered core first, then the aggregation and edge routers of each pod.
+func fatTree(k int) (*graph, error) {
+	if k < 2 || k%2 != 0 {
+		return nil, errors.New("k of a fat-tree must be an even number of at least 2")
+	}
+	half := k / 2
+	cores := half * half
+	g := &graph{
+		routers:    cores + k*k,
+		links:      make([][2]int, 0),
+		edge:       make([]int, 0, k*half),
+		bottleneck: -1,
+	}
+	for pod := 0; pod < k; pod++ {
+		first := cores + pod*k
+		for a := 0; a < half; a++ {
+			aggregation := first + a
+			// Each aggregation router reaches its own group of core routers
+			for c := 0; c < half; c++ {
+				g.connect(a*half+c, aggregation)
+			}
+			for e := 0; e < half; e++ {
+				g.connect(aggregation, first+half+e)
+			}
+		}
+		for e := 0; e < half; e++ {
+			g.edge = append(g.edge, first+half+e)
+		}
+	}
+	return g, nil
+}
+
+// Two routers joined by the bottleneck link. Other routers are split between both sides.
+func dumbbell(n int) (*graph, error) {
+	n = max(n, 2)
+	g := &graph{
+		routers:    n,
+		links:      make([][2]int, 0),
+		edge:       make([]int, 0),
+		bottleneck: 0,
+	}
+	g.connect(0, 1)
+	for i := 2; i < n; i++ {
+		g.connect(i%2, i)
+		g.edge = append(g.edge, i)
+	}
+	// A side without other routers gets its bridges on the bottleneck router
+	if n < 3 {
+		g.edge = append(g.edge, 0)
+	}
+	if n < 4 {
+		g.edge = append(g.edge, 1)
+	}
+	return g, nil
+}
+
+// Routers placed at random in a unit square, linked with probability alpha * e^(-d / (beta * L)),
+// where d is their distance and L the largest possible distance. Disconnected parts are then
+// joined through their closest routers.
+func waxman(n int, alpha float64, beta float64, seed int64) (*graph, error) {
+	if alpha == 0 {
+		alpha = DefaultAlpha
+	}
+	if beta == 0 {
+		beta = DefaultBeta
+	}
+	if alpha < 0 || alpha > 1 || beta < 0 {
+		return nil, errors.New("alpha must be between 0 and 1 and beta can't be lower than 0")
+	}
+	rng := rand.New(rand.NewSource(seed))
+	x := make([]float64, n)
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x[i], y[i] = rng.Float64(), rng.Float64()
+	}
+	distance := func(i int, j int) float64 {
+		return math.Hypot(x[i]-x[j], y[i]-y[j])
+	}
+
+	g := &graph{
+		routers:    n,
+		links:      make([][2]int, 0),
+		edge:       allRouters(n),
+		bottleneck: -1,
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if rng.Float64() < alpha*math.Exp(-distance(i, j)/(beta*math.Sqrt2)) {
+				g.connect(i, j)
+			}
+		}
+	}
+
+	parts := newUnionFind(n)
+	for _, link := range g.links {
+		parts.union(link[0], link[1])
+	}
+	for i := 1; i < n; i++ {
+		if parts.find(i) == parts.find(0) {
+			continue
+		}
+		closest := -1
+		for j := 0; j < n; j++ {
+			if parts.find(j) == parts.find(0) && (closest < 0 || distance(i, j) < distance(i, closest)) {
+				closest = j
+			}
+		}
+		g.connect(closest, i)
+		parts.union(closest, i)
+	}
+	return g, nil
+}
+
+// Barabási–Albert graph: a full mesh of m+1 routers, then every new router links to m
+// existing routers chosen with a probability proportional to their degree.
+func barabasi(n int, m int, seed int64) (*graph, error) {
+	if m < 1 {
+		return nil, errors.New("links of each new router must be at least 1")
+	}
+	rng := rand.New(rand.NewSource(seed))
+	g := &graph{
+		routers:    n,
+		links:      make([][2]int, 0),
+		edge:       allRouters(n),
+		bottleneck: -1,
+	}
+	// Each router appears once for every link it has
+	ends := make([]int, 0)
+	for i := 0; i < min(m+1, n); i++ {
+		for j := i + 1; j < min(m+1, n); j++ {
+			g.connect(i, j)
+			ends = append(ends, i, j)
+		}
+	}
+	for i := m + 1; i < n; i++ {
+		targets := make(map[int]bool)
+		for len(targets) < m {
+			targets[ends[rng.Intn(len(ends))]] = true
+		}
+		for j := 0; j < i; j++ {
+			if targets[j] {
+				g.connect(j, i)
+				ends = append(ends, j, i)
+			}
+		}
+	}
+	return g, nil
+}
+
+// Machine of each router
+func spread(routers int, machines []string, mode string) ([]string, error) {
+	assigned := make([]string, 0, routers)
+	switch mode {
+	case "", RoundRobin:
+		for i := 0; i < routers; i++ {
+			assigned = append(assigned, machines[i%len(machines)])
+		}
+	case Blocks:
+		size := int(math.Ceil(float64(routers) / float64(len(machines))))
+		for i := 0; i < routers; i++ {
+			assigned = append(assigned, machines[i/size])
+		}
+	default:
+		return nil, errors.New("unknown spread: " + mode)
+	}
+	return assigned, nil
+}
+
+type unionFind []int
+
+func newUnionFind(n int) unionFind {
+	parts := make(unionFind, n)
+	for i := range parts {
+		parts[i] = i
+	}
+	return parts
+}
+
+func (parts unionFind) find(i int) int {
+	for parts[i] != i {
+		i = parts[i]
+	}
+	return i
+}
+
+func (parts unionFind) union(i int, j int) {
+	parts[parts.find(i)] = parts.find(j)
+}
@@ -0,0 +1,160 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+type containerlabLab struct {
+	Name     string               `yaml:"name"`
+	Topology containerlabTopology `yaml:"topology"`
+	Other    map[string]any       `yaml:",inline"`
+}
+
+type containerlabTopology struct {
+	Kinds    map[string]map[string]any `yaml:"kinds"`
+	Defaults map[string]any            `yaml:"defaults"`
+	Nodes    map[string]map[string]any `yaml:"nodes"`
+	Links    []map[string]any          `yaml:"links"`
+	Other    map[string]any            `yaml:",inline"`
+}
+
+// Node fields turned into options of the docker command
+var containerlabNodeFields = map[string]bool{
+	"kind":  true,
+	"image": true,
+	"cmd":   true,
+	"env":   true,
+	"binds": true,
+	"ports": true,
+}
+
+// Containerlab topologies: linux nodes run as GONE nodes, bridge and ovs-bridge nodes become bridges,
+// and any other kind becomes a router. Links keep the default properties, since Containerlab has none.
+func importContainerlab(b *builder, contents string) error {
+	lab := &containerlabLab{}
+	if err := yaml.Unmarshal([]byte(contents), lab); err != nil {
+		return err
+	}
+	if len(lab.Topology.Nodes) == 0 {
+		return errors.New("containerlab topology has no nodes")
+	}
+	for _, field := range sortedKeys(lab.Other) {
+		b.report(field)
+	}
+	for _, field := range sortedKeys(lab.Topology.Other) {
+		b.report("topology " + field)
+	}
+
+	for _, name := range sortedKeys(lab.Topology.Nodes) {
+		// Node fields override the fields of their kind, which override the defaults
+		fields := make(map[string]any)
+		for field, value := range lab.Topology.Defaults {
+			fields[field] = value
+		}
+		kind, _ := lab.Topology.Nodes[name]["kind"].(string)
+		if kind == "" {
+			kind, _ = fields["kind"].(string)
+		}
+		for field, value := range lab.Topology.Kinds[kind] {
+			fields[field] = value
+		}
+		for field, value := range lab.Topology.Nodes[name] {
+			fields[field] = value
+		}
+
+		switch kind {
+		case "linux":
+			image, _ := fields["image"].(string)
+			cmd, _ := fields["cmd"].(string)
+			b.addNode(name, b.dockerCmd(name, image, containerlabOptions(fields), strings.Fields(cmd)))
+			for _, field := range sortedKeys(fields) {
+				if !containerlabNodeFields[field] {
+					b.report("node " + name + ": " + field)
+				}
+			}
+		case "bridge", "ovs-bridge":
+			b.addBridge(name)
+			reportFields(b, name, fields)
+		case "host", "ext-container", "":
+			b.report("node " + name + ": kind " + kind)
+		default:
+			b.addRouter(name)
+			b.report("node " + name + ": kind " + kind + " runs as a router")
+			reportFields(b, name, fields)
+		}
+	}
+
+	for _, link := range lab.Topology.Links {
+		endpoints := containerlabEndpoints(link["endpoints"])
+		if len(endpoints) != 2 {
+			b.report(fmt.Sprint("link ", link["endpoints"], ": endpoints"))
+			continue
+		}
+		description := "link " + endpoints[0] + " - " + endpoints[1]
+		if kind, ok := link["type"].(string); ok && kind != "veth" {
+			b.report(description + ": type " + kind)
+			continue
+		}
+		for _, field := range sortedKeys(link) {
+			if field != "endpoints" && field != "type" {
+				b.report(description + ": " + field)
+			}
+		}
+		b.report("interface names")
+		b.link(endpoints[0], endpoints[1], b.options.Link)
+	}
+	return nil
+}
+
+// Fields of bridges and routers are not used
+func reportFields(b *builder, name string, fields map[string]any) {
+	for _, field := range sortedKeys(fields) {
+		if field != "kind" {
+			b.report("node " + name + ": " + field)
+		}
+	}
+}
+
+func containerlabOptions(fields map[string]any) []string {
+	options := make([]string, 0)
+	if env, ok := fields["env"].(map[string]any); ok {
+		for _, key := range sortedKeys(env) {
+			options = append(options, "-e", key+"="+fmt.Sprint(env[key]))
+		}
+	}
+	if binds, ok := fields["binds"].([]any); ok {
+		for _, bind := range binds {
+			options = append(options, "-v", fmt.Sprint(bind))
+		}
+	}
+	if ports, ok := fields["ports"].([]any); ok {
+		for _, port := range ports {
+			options = append(options, "-p", fmt.Sprint(port))
+		}
+	}
+	return options
+}
+
+// Names of the nodes of a link, written as node:interface or as a map with a node field.
+// Interfaces are not kept, since GONE components have a single link of each kind.
+func containerlabEndpoints(value any) []string {
+	list, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+	endpoints := make([]string, 0, len(list))
+	for _, endpoint := range list {
+		switch e := endpoint.(type) {
+		case string:
+			endpoints = append(endpoints, strings.SplitN(e, ":", 2)[0])
+		case map[string]any:
+			if node, ok := e["node"].(string); ok {
+				endpoints = append(endpoints, node)
+			}
+		}
+	}
+	return endpoints
+}
@@ -0,0 +1,234 @@
+package importer
+
+import (
+	"encoding/xml"
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Speed of light in fiber, in kilometres per millisecond
+const fiberSpeed = 200.0
+
+const earthRadius = 6371.0
+
+type graphMLDocument struct {
+	Keys   []graphMLKey   `xml:"key"`
+	Graphs []graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	Id      string `xml:"id,attr"`
+	For     string `xml:"for,attr"`
+	Name    string `xml:"attr.name,attr"`
+	Default string `xml:"default"`
+}
+
+type graphMLGraph struct {
+	EdgeDefault string           `xml:"edgedefault,attr"`
+	Data        []graphMLData    `xml:"data"`
+	Nodes       []graphMLNode    `xml:"node"`
+	Edges       []graphMLEdge    `xml:"edge"`
+	Hyperedges  []graphMLElement `xml:"hyperedge"`
+}
+
+type graphMLNode struct {
+	Id    string           `xml:"id,attr"`
+	Data  []graphMLData    `xml:"data"`
+	Ports []graphMLElement `xml:"port"`
+	Graph *graphMLGraph    `xml:"graph"`
+}
+
+type graphMLEdge struct {
+	Source   string        `xml:"source,attr"`
+	Target   string        `xml:"target,attr"`
+	Directed string        `xml:"directed,attr"`
+	Data     []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLElement struct{}
+
+// Edge attributes translated into link properties. Latencies are in milliseconds.
+var graphMLLinkAttributes = map[string]bool{
+	"LinkSpeedRaw":   true,
+	"LinkSpeed":      true,
+	"LinkSpeedUnits": true,
+	"LinkLabel":      true,
+	"latency":        true,
+	"delay":          true,
+}
+
+// Node attributes used for the names and latencies of the routers
+var graphMLNodeAttributes = map[string]bool{
+	"label":     true,
+	"Latitude":  true,
+	"Longitude": true,
+}
+
+var invalidName = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// Values of the attributes of an element by name, including the defaults of the keys
+func graphMLValues(keys map[string]graphMLKey, kind string, data []graphMLData) map[string]string {
+	values := make(map[string]string)
+	for _, key := range keys {
+		if (key.For == kind || key.For == "all") && key.Default != "" {
+			values[key.Name] = strings.TrimSpace(key.Default)
+		}
+	}
+	for _, d := range data {
+		name := d.Key
+		if key, ok := keys[d.Key]; ok && key.Name != "" {
+			name = key.Name
+		}
+		values[name] = strings.TrimSpace(d.Value)
+	}
+	return values
+}
+
+// Topology Zoo graphs: every node becomes a router and every edge a router link.
+// Link speeds set the bandwidth, and the latency is taken from a latency or delay attribute,
+// or from the distance between the coordinates of both nodes.
+func importGraphML(b *builder, contents string) error {
+	doc := &graphMLDocument{}
+	if err := xml.Unmarshal([]byte(contents), doc); err != nil {
+		return err
+	}
+	if len(doc.Graphs) == 0 {
+		return errors.New("graphml document has no graph")
+	}
+	if len(doc.Graphs) > 1 {
+		b.report("graphs after the first")
+	}
+
+	keys := make(map[string]graphMLKey)
+	for _, key := range doc.Keys {
+		keys[key.Id] = key
+	}
+	graph := doc.Graphs[0]
+
+	for _, name := range sortedKeys(graphMLValues(keys, "graph", graph.Data)) {
+		b.report("graph attribute " + name)
+	}
+	if len(graph.Hyperedges) > 0 {
+		b.report("hyperedges")
+	}
+
+	names := make(map[string]string)
+	coordinates := make(map[string][2]float64)
+	for _, node := range graph.Nodes {
+		values := graphMLValues(keys, "node", node.Data)
+		for _, name := range sortedKeys(values) {
+			if !graphMLNodeAttributes[name] {
+				b.report("node attribute " + name)
+			}
+		}
+		if len(node.Ports) > 0 {
+			b.report("node " + node.Id + ": ports")
+		}
+		if node.Graph != nil {
+			b.report("node " + node.Id + ": nested graph")
+		}
+
+		name := invalidName.ReplaceAllString(values["label"], "_")
+		if name == "" {
+			name = "r" + node.Id
+		} else if _, ok := b.kinds[name]; ok {
+			name = name + "-" + node.Id
+		}
+		names[node.Id] = name
+		b.addRouter(name)
+		if b.options.Bridges {
+			b.hostBridge(name)
+		}
+
+		latitude, latErr := strconv.ParseFloat(values["Latitude"], 64)
+		longitude, lonErr := strconv.ParseFloat(values["Longitude"], 64)
+		if latErr == nil && lonErr == nil {
+			coordinates[node.Id] = [2]float64{latitude, longitude}
+		}
+	}
+
+	for _, edge := range graph.Edges {
+		if edge.Directed == "true" || (edge.Directed == "" && graph.EdgeDefault == "directed") {
+			b.report("edge " + edge.Source + " - " + edge.Target + ": direction")
+		}
+		values := graphMLValues(keys, "edge", edge.Data)
+		for _, name := range sortedKeys(values) {
+			if !graphMLLinkAttributes[name] {
+				b.report("edge attribute " + name)
+			}
+		}
+
+		props := b.options.Link
+		if bandwidth, ok := graphMLBandwidth(values); ok {
+			props.Bandwidth = bandwidth
+		}
+		if latency, ok := graphMLLatency(values); ok {
+			props.Latency = latency
+		} else if from, ok := coordinates[edge.Source]; ok {
+			if to, ok := coordinates[edge.Target]; ok {
+				// The latency of a link is split between both directions
+				props.Latency = 2 * distance(from, to) / fiberSpeed
+			}
+		}
+
+		from, ok := names[edge.Source]
+		if !ok {
+			from = edge.Source
+		}
+		to, ok := names[edge.Target]
+		if !ok {
+			to = edge.Target
+		}
+		b.link(from, to, props)
+	}
+	return nil
+}
+
+// Bandwidth in bits per second
+func graphMLBandwidth(values map[string]string) (int, bool) {
+	if raw, err := strconv.ParseFloat(values["LinkSpeedRaw"], 64); err == nil && raw > 0 {
+		return int(raw), true
+	}
+	speed, err := strconv.ParseFloat(values["LinkSpeed"], 64)
+	if err != nil || speed <= 0 {
+		return 0, false
+	}
+	switch strings.ToUpper(values["LinkSpeedUnits"]) {
+	case "K":
+		return int(speed * 1e3), true
+	case "M":
+		return int(speed * 1e6), true
+	case "G":
+		return int(speed * 1e9), true
+	case "T":
+		return int(speed * 1e12), true
+	default:
+		return int(speed), true
+	}
+}
+
+func graphMLLatency(values map[string]string) (float64, bool) {
+	for _, name := range []string{"latency", "delay"} {
+		if latency, err := strconv.ParseFloat(strings.TrimSuffix(values[name], "ms"), 64); err == nil && latency >= 0 {
+			return latency, true
+		}
+	}
+	return 0, false
+}
+
+// Great-circle distance in kilometres between two coordinates in degrees
+func distance(from [2]float64, to [2]float64) float64 {
+	lat1, lat2 := from[0]*math.Pi/180, to[0]*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (to[1] - from[1]) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,267 @@
+package importer
+
+import (
+	"errors"
+	topologyApi "github.com/David-Antunes/gone/api/Topology"
+	"sort"
+)
+
+// Formats
+const (
+	GraphML      = "graphml"
+	Containerlab = "containerlab"
+	Mininet      = "mininet"
+)
+
+// Image run by hosts that don't name one
+const DefaultImage = "nicolaka/netshoot"
+
+const (
+	nodeKind   = "node"
+	bridgeKind = "bridge"
+	routerKind = "router"
+)
+
+// Settings shared by the importers. Every component is placed in MachineId and nodes run on Network.
+// Link holds the properties of links without attributes and of the links added to connect components.
+// Bridges connects a bridge to every router of formats that only describe routers.
+type Options struct {
+	MachineId string
+	Network   string
+	Image     string
+	Link      topologyApi.LinkProperties
+	Bridges   bool
+}
+
+// Converts a topology into a document. Returns the constructs that could not be translated.
+func Import(format string, contents string, options Options) (topologyApi.Document, []string, error) {
+	if options.Image == "" {
+		options.Image = DefaultImage
+	}
+	b := newBuilder(options)
+
+	var err error
+	switch format {
+	case GraphML:
+		err = importGraphML(b, contents)
+	case Containerlab:
+		err = importContainerlab(b, contents)
+	case Mininet:
+		err = importMininet(b, contents)
+	default:
+		err = errors.New("unknown format: " + format)
+	}
+	if err != nil {
+		return topologyApi.Document{}, nil, err
+	}
+	return b.doc, b.untranslated, nil
+}
+
+// Builds a document from components and links of any kind. Links GONE can't express directly go through
+// added bridges and routers: nodes reach routers and other nodes through a bridge, and bridges reach
+// routers and other bridges through a router.
+type builder struct {
+	options      Options
+	doc          topologyApi.Document
+	kinds        map[string]string
+	linkedNodes  map[string]bool
+	routerOf     map[string]string
+	bridgeOf     map[string]string
+	untranslated []string
+	reported     map[string]bool
+}
+
+func newBuilder(options Options) *builder {
+	return &builder{
+		options: options,
+		doc: topologyApi.Document{
+			Nodes:     make([]topologyApi.NodeSpec, 0),
+			Bridges:   make([]topologyApi.ComponentSpec, 0),
+			Routers:   make([]topologyApi.ComponentSpec, 0),
+			Links:     make([]topologyApi.LinkSpec, 0),
+			Propagate: true,
+			Unpause:   false,
+		},
+		kinds:        make(map[string]string),
+		linkedNodes:  make(map[string]bool),
+		routerOf:     make(map[string]string),
+		bridgeOf:     make(map[string]string),
+		untranslated: make([]string, 0),
+		reported:     make(map[string]bool),
+	}
+}
+
+// Records a construct that could not be translated, once
+func (b *builder) report(construct string) {
+	if !b.reported[construct] {
+		b.reported[construct] = true
+		b.untranslated = append(b.untranslated, construct)
+	}
+}
+
+func (b *builder) exists(name string) bool {
+	if _, ok := b.kinds[name]; ok {
+		b.report("duplicate component " + name)
+		return true
+	}
+	return false
+}
+
+func (b *builder) addNode(name string, dockerCmd []string) {
+	if b.exists(name) {
+		return
+	}
+	b.kinds[name] = nodeKind
+	b.doc.Nodes = append(b.doc.Nodes, topologyApi.NodeSpec{
+		Name:      name,
+		MachineId: b.options.MachineId,
+		DockerCmd: dockerCmd,
+	})
+}
+
+func (b *builder) addBridge(name string) {
+	if b.exists(name) {
+		return
+	}
+	b.kinds[name] = bridgeKind
+	b.doc.Bridges = append(b.doc.Bridges, topologyApi.ComponentSpec{
+		Name:      name,
+		MachineId: b.options.MachineId,
+	})
+}
+
+func (b *builder) addRouter(name string) {
+	if b.exists(name) {
+		return
+	}
+	b.kinds[name] = routerKind
+	b.doc.Routers = append(b.doc.Routers, topologyApi.ComponentSpec{
+		Name:      name,
+		MachineId: b.options.MachineId,
+	})
+}
+
+// Command running a host on the emulation network
+func (b *builder) dockerCmd(name string, image string, options []string, cmd []string) []string {
+	if image == "" {
+		image = b.options.Image
+	}
+	dockerCmd := []string{"docker", "run", "-dit", "--network", b.options.Network, "--name", name}
+	dockerCmd = append(dockerCmd, options...)
+	dockerCmd = append(dockerCmd, image)
+	return append(dockerCmd, cmd...)
+}
+
+func (b *builder) connect(from string, to string, props topologyApi.LinkProperties) {
+	b.doc.Links = append(b.doc.Links, topologyApi.LinkSpec{
+		From:           from,
+		To:             to,
+		LinkProperties: props,
+	})
+}
+
+// Bridge the hosts of a router connect to, added on first use
+func (b *builder) hostBridge(router string) string {
+	if bridge, ok := b.bridgeOf[router]; ok {
+		return bridge
+	}
+	bridge := router + "-br"
+	b.addBridge(bridge)
+	b.bridgeOf[router] = bridge
+	b.routerOf[bridge] = router
+	b.connect(bridge, router, b.options.Link)
+	return bridge
+}
+
+// Router of a bridge, added on first use
+func (b *builder) bridgeRouter(bridge string) string {
+	if router, ok := b.routerOf[bridge]; ok {
+		return router
+	}
+	router := bridge + "-r"
+	b.addRouter(router)
+	b.routerOf[bridge] = router
+	b.connect(bridge, router, b.options.Link)
+	return router
+}
+
+// Nodes only have one link
+func (b *builder) connectNode(node string, bridge string, props topologyApi.LinkProperties) {
+	if b.linkedNodes[node] {
+		b.report("link " + node + " - " + bridge + ": nodes only keep their first link")
+		return
+	}
+	b.linkedNodes[node] = true
+	b.connect(node, bridge, props)
+}
+
+// Connects two components of any kind with the properties of the original link
+func (b *builder) link(from string, to string, props topologyApi.LinkProperties) {
+	fromKind, ok := b.kinds[from]
+	if !ok {
+		b.report("link " + from + " - " + to + ": unknown component " + from)
+		return
+	}
+	toKind, ok := b.kinds[to]
+	if !ok {
+		b.report("link " + from + " - " + to + ": unknown component " + to)
+		return
+	}
+	if from == to {
+		b.report("link " + from + " - " + to + ": loop")
+		return
+	}
+	// Node, bridge, router order
+	order := map[string]int{nodeKind: 0, bridgeKind: 1, routerKind: 2}
+	if order[toKind] < order[fromKind] {
+		from, to = to, from
+		fromKind, toKind = toKind, fromKind
+	}
+
+	switch {
+	case fromKind == nodeKind && toKind == nodeKind:
+		if b.linkedNodes[from] || b.linkedNodes[to] {
+			b.report("link " + from + " - " + to + ": nodes only keep their first link")
+			return
+		}
+		bridge := from + "-" + to
+		b.addBridge(bridge)
+		b.connectNode(from, bridge, props)
+		b.connectNode(to, bridge, props)
+	case fromKind == nodeKind && toKind == bridgeKind:
+		b.connectNode(from, to, props)
+	case fromKind == nodeKind && toKind == routerKind:
+		b.connectNode(from, b.hostBridge(to), props)
+	case fromKind == bridgeKind && toKind == bridgeKind:
+		b.routerLink(b.bridgeRouter(from), b.bridgeRouter(to), props)
+	case fromKind == bridgeKind && toKind == routerKind:
+		if _, ok := b.routerOf[from]; ok {
+			b.routerLink(b.bridgeRouter(from), to, props)
+			return
+		}
+		b.routerOf[from] = to
+		b.connect(from, to, props)
+	default:
+		b.routerLink(from, to, props)
+	}
+}
+
+// Routers are only connected once
+func (b *builder) routerLink(from string, to string, props topologyApi.LinkProperties) {
+	for _, link := range b.doc.Links {
+		if (link.From == from && link.To == to) || (link.From == to && link.To == from) {
+			b.report("link " + from + " - " + to + ": parallel link")
+			return
+		}
+	}
+	b.connect(from, to, props)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,152 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/David-Antunes/gone/api"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Topology saved by MiniEdit
+type mininetTopology struct {
+	Hosts       []mininetNode `json:"hosts"`
+	Switches    []mininetNode `json:"switches"`
+	Controllers []mininetNode `json:"controllers"`
+	Links       []mininetLink `json:"links"`
+}
+
+type mininetNode struct {
+	Opts map[string]any `json:"opts"`
+}
+
+type mininetLink struct {
+	Src  string         `json:"src"`
+	Dest string         `json:"dest"`
+	Opts map[string]any `json:"opts"`
+}
+
+// Host, switch and link options that are translated
+var mininetOptions = map[string]bool{
+	"hostname":       true,
+	"nodeNum":        true,
+	"switchType":     true,
+	"bw":             true,
+	"delay":          true,
+	"jitter":         true,
+	"loss":           true,
+	"max_queue_size": true,
+}
+
+// Mininet topologies exported by MiniEdit: hosts run as GONE nodes, legacy routers become routers
+// and any other switch becomes a bridge. Link options follow TCLink, which shapes each interface,
+// so delay, jitter and loss apply to each direction.
+func importMininet(b *builder, contents string) error {
+	topo := &mininetTopology{}
+	if err := json.Unmarshal([]byte(contents), topo); err != nil {
+		return err
+	}
+	if len(topo.Hosts) == 0 && len(topo.Switches) == 0 {
+		return errors.New("mininet topology has no hosts or switches")
+	}
+
+	for _, controller := range topo.Controllers {
+		b.report("controller " + mininetName(controller))
+	}
+	for _, host := range topo.Hosts {
+		name := mininetName(host)
+		b.addNode(name, b.dockerCmd(name, "", nil, nil))
+		for _, option := range sortedKeys(host.Opts) {
+			// Every host is scheduled by the host by default
+			if !mininetOptions[option] && !(option == "sched" && host.Opts[option] == "host") {
+				b.report("host " + name + ": " + option)
+			}
+		}
+	}
+	for _, s := range topo.Switches {
+		name := mininetName(s)
+		if s.Opts["switchType"] == "legacyRouter" {
+			b.addRouter(name)
+		} else {
+			b.addBridge(name)
+		}
+		for _, option := range sortedKeys(s.Opts) {
+			if !mininetOptions[option] {
+				b.report("switch " + name + ": " + option)
+			}
+		}
+	}
+
+	for _, link := range topo.Links {
+		description := "link " + link.Src + " - " + link.Dest
+		props := b.options.Link
+		if bw, ok := mininetNumber(link.Opts["bw"]); ok {
+			props.Bandwidth = int(bw * 1e6)
+		}
+		// The symmetric properties of a link are split between both directions
+		if delay, ok := mininetDuration(link.Opts["delay"]); ok {
+			props.Latency = 2 * delay
+		}
+		if jitter, ok := mininetDuration(link.Opts["jitter"]); ok {
+			props.Jitter = 2 * jitter
+		}
+		if loss, ok := mininetNumber(link.Opts["loss"]); ok {
+			props.DropRate = min(2*loss/100, 1)
+		}
+		if size, ok := mininetNumber(link.Opts["max_queue_size"]); ok {
+			queue := props.Queue
+			if queue == nil {
+				queue = &api.QueueConfig{}
+			} else {
+				copied := *queue
+				queue = &copied
+			}
+			queue.LimitPackets = int(size)
+			props.Queue = queue
+		}
+		for _, option := range sortedKeys(link.Opts) {
+			if !mininetOptions[option] {
+				b.report(description + ": " + option)
+			}
+		}
+		b.link(link.Src, link.Dest, props)
+	}
+	return nil
+}
+
+func mininetName(node mininetNode) string {
+	if name, ok := node.Opts["hostname"].(string); ok {
+		return name
+	}
+	return fmt.Sprint(node.Opts["nodeNum"])
+}
+
+func mininetNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// Duration in milliseconds, written like 5ms or as a number of milliseconds
+func mininetDuration(value any) (float64, bool) {
+	if number, ok := mininetNumber(value); ok {
+		return number, true
+	}
+	text, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(text))
+	if err != nil {
+		return 0, false
+	}
+	return float64(duration) / float64(time.Millisecond), true
+}
@@ -0,0 +1,315 @@
+package leader
+
+import (
+	"errors"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+	topologyApi "github.com/David-Antunes/gone/api/Topology"
+	"github.com/David-Antunes/gone/internal/daemon"
+	"github.com/David-Antunes/gone/internal/network"
+	"net/http"
+	"sync"
+)
+
+// Step states
+const (
+	stepDone    = "done"
+	stepFailed  = "failed"
+	stepSkipped = "skipped"
+)
+
+// Component kinds of a document
+const (
+	nodeComponent   = "node"
+	bridgeComponent = "bridge"
+	routerComponent = "router"
+)
+
+// Documents change the whole topology, so only one is applied at a time
+var applyLock sync.Mutex
+
+// Docker names the containers, so the nodes added by documents are kept by their name in the document.
+// Guarded by applyLock.
+var documentNodes = make(map[string]string)
+
+// Operation of a document. run returns the id of the created component, if any.
+type applyStep struct {
+	action string
+	target string
+	run    func() (string, error)
+}
+
+func apply(w http.ResponseWriter, r *http.Request) {
+
+	doc := &topologyApi.Document{}
+
+	if err := daemon.ParseDocument(r, doc); err != nil {
+		daemonLog.Println("apply:", err)
+		daemon.SendError(w, &topologyApi.ApplyResponse{
+			Steps: nil,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	applyLock.Lock()
+	defer applyLock.Unlock()
+
+	steps, err := planDocument(doc, propagatedRouters(doc))
+
+	if err != nil {
+		daemonLog.Println("apply:", err)
+		daemon.SendError(w, &topologyApi.ApplyResponse{
+			Steps: nil,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	results, err := runSteps(steps)
+
+	if err != nil {
+		daemonLog.Println("apply:", err)
+		daemon.SendError(w, &topologyApi.ApplyResponse{
+			Steps: results,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &topologyApi.ApplyResponse{
+		Steps: results,
+		Error: apiErrors.Error{},
+	})
+	daemonLog.Println("apply:", "Applied", len(results), "steps")
+}
+
+// Runs steps in order until one fails. Returns the error of the failed step.
+func runSteps(steps []applyStep) ([]topologyApi.Step, error) {
+	results := make([]topologyApi.Step, 0, len(steps))
+	var failed error
+	for i, step := range steps {
+		result := topologyApi.Step{
+			Index:  i,
+			Action: step.action,
+			Target: step.target,
+			Status: stepSkipped,
+		}
+		if failed == nil {
+			id, err := step.run()
+			if err != nil {
+				result.Status = stepFailed
+				result.Error = err.Error()
+				failed = errors.New(step.action + " " + step.target + ": " + err.Error())
+			} else {
+				result.Status = stepDone
+				result.Result = id
+			}
+		}
+		results = append(results, result)
+	}
+	return results, failed
+}
+
+// Routers propagated once the links of a document are connected
+func propagatedRouters(doc *topologyApi.Document) []string {
+	if !doc.Propagate {
+		return nil
+	}
+	routers := make([]string, 0, len(doc.Routers))
+	for _, router := range doc.Routers {
+		routers = append(routers, router.Name)
+	}
+	return routers
+}
+
+// Builds the steps of a document in dependency order: routers, bridges and nodes first, then their links,
+// and finally propagation of the given routers and unpausing. Every link is validated before any step runs.
+func planDocument(doc *topologyApi.Document, propagate []string) ([]applyStep, error) {
+	kinds := make(map[string]string)
+	for _, router := range doc.Routers {
+		if err := addDocumentComponent(kinds, router.Name, routerComponent); err != nil {
+			return nil, err
+		}
+	}
+	for _, bridge := range doc.Bridges {
+		if err := addDocumentComponent(kinds, bridge.Name, bridgeComponent); err != nil {
+			return nil, err
+		}
+	}
+	for _, node := range doc.Nodes {
+		if err := addDocumentComponent(kinds, node.Name, nodeComponent); err != nil {
+			return nil, err
+		}
+		if len(node.DockerCmd) == 0 {
+			return nil, errors.New(node.Name + " has no docker command")
+		}
+	}
+
+	steps := make([]applyStep, 0)
+
+	for _, router := range doc.Routers {
+		router := router
+		steps = append(steps, applyStep{
+			action: "addRouter",
+			target: router.Name,
+			run: func() (string, error) {
+				_, err := engine.app.AddRouter(router.MachineId, router.Name)
+				return router.Name, err
+			},
+		})
+	}
+	for _, bridge := range doc.Bridges {
+		bridge := bridge
+		steps = append(steps, applyStep{
+			action: "addBridge",
+			target: bridge.Name,
+			run: func() (string, error) {
+				_, err := engine.app.AddBridge(bridge.MachineId, bridge.Name)
+				return bridge.Name, err
+			},
+		})
+	}
+
+	// Links use the id returned when the node is added
+	for _, node := range doc.Nodes {
+		node := node
+		steps = append(steps, applyStep{
+			action: "addNode",
+			target: node.Name,
+			run: func() (string, error) {
+				id, _, _, err := engine.app.AddNode(node.MachineId, node.DockerCmd)
+				if err != nil {
+					return "", err
+				}
+				documentNodes[node.Name] = id
+				return id, nil
+			},
+		})
+	}
+
+	for _, link := range doc.Links {
+		step, err := linkStep(link, kinds, documentNodeId)
+		if err != nil {
+			return nil, errors.New(link.From + " -> " + link.To + ": " + err.Error())
+		}
+		steps = append(steps, step)
+	}
+
+	for _, router := range propagate {
+		router := router
+		steps = append(steps, applyStep{
+			action: "propagate",
+			target: router,
+			run: func() (string, error) {
+				return "", engine.app.Propagate(router)
+			},
+		})
+	}
+	if doc.Unpause {
+		for _, node := range doc.Nodes {
+			node := node
+			steps = append(steps, applyStep{
+				action: "unpause",
+				target: node.Name,
+				run: func() (string, error) {
+					return "", engine.app.Unpause(documentNodeId(node.Name), false)
+				},
+			})
+		}
+	}
+	return steps, nil
+}
+
+// Container id of a node added by a document, or name when no document added a node with that name
+func documentNodeId(name string) string {
+	if id, ok := documentNodes[name]; ok {
+		return id
+	}
+	return name
+}
+
+func addDocumentComponent(kinds map[string]string, name string, kind string) error {
+	if name == "" {
+		return errors.New("every " + kind + " needs a name")
+	}
+	if _, ok := kinds[name]; ok {
+		return errors.New(name + " is declared more than once")
+	}
+	kinds[name] = kind
+	return nil
+}
+
+// Kind of a component declared in the document or already running in the emulation
+func componentKind(kinds map[string]string, name string) (string, bool) {
+	if kind, ok := kinds[name]; ok {
+		return kind, true
+	}
+	if _, ok := engine.app.GetNode(documentNodeId(name)); ok {
+		return nodeComponent, true
+	}
+	if _, ok := engine.app.GetBridge(name); ok {
+		return bridgeComponent, true
+	}
+	if _, ok := engine.app.GetRouter(name); ok {
+		return routerComponent, true
+	}
+	return "", false
+}
+
+func linkStep(link topologyApi.LinkSpec, kinds map[string]string, nodeId func(string) string) (applyStep, error) {
+	from, ok := componentKind(kinds, link.From)
+	if !ok {
+		return applyStep{}, errors.New("unknown component " + link.From)
+	}
+	to, ok := componentKind(kinds, link.To)
+	if !ok {
+		return applyStep{}, errors.New("unknown component " + link.To)
+	}
+	up, down, err := parseLinkSpec(link)
+	if err != nil {
+		return applyStep{}, err
+	}
+
+	target := link.From + " -> " + link.To
+	switch {
+	case from == nodeComponent && to == bridgeComponent:
+		return applyStep{
+			action: "connectNodeToBridge",
+			target: target,
+			run: func() (string, error) {
+				return "", engine.app.ConnectNodeToBridge(nodeId(link.From), link.To, up, down)
+			},
+		}, nil
+	case from == bridgeComponent && to == routerComponent:
+		return applyStep{
+			action: "connectBridgeToRouter",
+			target: target,
+			run: func() (string, error) {
+				return "", engine.app.ConnectBridgeToRouter(link.From, link.To, up, down)
+			},
+		}, nil
+	case from == routerComponent && to == routerComponent:
+		return applyStep{
+			action: "connectRouterToRouter",
+			target: target,
+			run: func() (string, error) {
+				return "", engine.app.ConnectRouterToRouter(link.From, link.To, up, down, false)
+			},
+		}, nil
+	default:
+		return applyStep{}, errors.New("links go from node to bridge, bridge to router or router to router")
+	}
+}
+
+func parseLinkSpec(link topologyApi.LinkSpec) (network.LinkProps, network.LinkProps, error) {
+	return daemon.ParseBiLinkProps(link.Latency, link.Bandwidth, link.Jitter, link.DropRate, link.Weight, link.Loss, link.Impairments, link.Distribution, link.Queue, link.Trace, link.Upstream, link.Downstream)
+}
@@ -0,0 +1,268 @@
+package leader
+
+import (
+	"errors"
+	"github.com/David-Antunes/gone/api"
+	chaosApi "github.com/David-Antunes/gone/api/Chaos"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+	"github.com/David-Antunes/gone/internal/chaos"
+	"github.com/David-Antunes/gone/internal/daemon"
+	"github.com/David-Antunes/gone/internal/network"
+	"net/http"
+	"slices"
+	"time"
+)
+
+func startChaos(w http.ResponseWriter, r *http.Request) {
+
+	req := &chaosApi.StartChaosRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("startChaos:", err)
+		daemon.SendError(w, &chaosApi.StartChaosResponse{
+			Id:  "",
+			Run: api.ChaosRun{},
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	var id string
+	config, err := parseChaosConfig(req)
+	if err == nil {
+		var targets chaos.Targets
+		targets, err = engine.app.ChaosTargets(req.Nodes, req.Bridges, req.Routers)
+		if err == nil {
+			id, err = engine.chaos.Start(config, targets, injectChaosFault(config))
+		}
+	}
+
+	if err != nil {
+		daemonLog.Println("startChaos:", err)
+		daemon.SendError(w, &chaosApi.StartChaosResponse{
+			Id:  "",
+			Run: api.ChaosRun{},
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	run, _ := engine.chaos.Get(id)
+	daemon.SendResponse(w, &chaosApi.StartChaosResponse{
+		Id:    id,
+		Run:   run,
+		Error: apiErrors.Error{},
+	})
+	daemonLog.Println("startChaos:", "Started", id, "with seed", config.Seed)
+}
+
+func stopChaos(w http.ResponseWriter, r *http.Request) {
+
+	req := &chaosApi.StopChaosRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("stopChaos:", err)
+		daemon.SendError(w, &chaosApi.StopChaosResponse{
+			Id: req.Id,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	err := engine.chaos.Stop(req.Id)
+
+	if err != nil {
+		daemonLog.Println("stopChaos:", err)
+		daemon.SendError(w, &chaosApi.StopChaosResponse{
+			Id: req.Id,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &chaosApi.StopChaosResponse{
+		Id:    req.Id,
+		Error: apiErrors.Error{},
+	})
+	daemonLog.Println("stopChaos:", "Stopped", req.Id)
+}
+
+func listChaos(w http.ResponseWriter, r *http.Request) {
+
+	daemon.SendResponse(w, &chaosApi.ListChaosResponse{
+		Runs:  engine.chaos.List(),
+		Error: apiErrors.Error{},
+	})
+}
+
+func inspectChaos(w http.ResponseWriter, r *http.Request) {
+
+	req := &chaosApi.InspectChaosRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("inspectChaos:", err)
+		daemon.SendError(w, &chaosApi.InspectChaosResponse{
+			Run:    api.ChaosRun{},
+			Events: nil,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	run, ok := engine.chaos.Get(req.Id)
+	events, _ := engine.chaos.Events(req.Id)
+
+	if !ok {
+		daemonLog.Println("inspectChaos:", "invalid chaos id", req.Id)
+		daemon.SendError(w, &chaosApi.InspectChaosResponse{
+			Run:    api.ChaosRun{},
+			Events: nil,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  "invalid chaos id: " + req.Id,
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &chaosApi.InspectChaosResponse{
+		Run:    run,
+		Events: events,
+		Error:  apiErrors.Error{},
+	})
+}
+
+func parseChaosConfig(req *chaosApi.StartChaosRequest) (chaos.Config, error) {
+	if req.MinFaultMs < 0 || req.MaxFaultMs < 0 {
+		return chaos.Config{}, errors.New("fault durations can't be lower than 0 ms")
+	}
+	if slices.Contains(req.Faults, chaos.LatencySpike) && req.LatencySpikeMs <= 0 {
+		return chaos.Config{}, errors.New("latency spikes must be greater than 0 ms")
+	}
+	if slices.Contains(req.Faults, chaos.LossBurst) && (req.LossBurst <= 0 || req.LossBurst > 1) {
+		return chaos.Config{}, errors.New("loss bursts must be between 0 and 1")
+	}
+	return chaos.Config{
+		Seed:         req.Seed,
+		Rate:         req.Rate,
+		Duration:     time.Duration(req.DurationMs * float64(time.Millisecond)),
+		Faults:       req.Faults,
+		MinFault:     time.Duration(req.MinFaultMs * float64(time.Millisecond)),
+		MaxFault:     time.Duration(req.MaxFaultMs * float64(time.Millisecond)),
+		LatencySpike: time.Duration(req.LatencySpikeMs * float64(time.Millisecond)),
+		LossBurst:    req.LossBurst,
+	}, nil
+}
+
+// Applies the faults of a chaos run through the same operations as the endpoints
+func injectChaosFault(config chaos.Config) chaos.Injector {
+	return func(fault chaos.Fault) (func() error, error) {
+		switch fault.Type {
+		case chaos.Disrupt:
+			return disruptChaosTarget(fault.Target)
+
+		case chaos.LatencySpike, chaos.LossBurst:
+			return degradeChaosTarget(fault, config)
+
+		case chaos.Pause:
+			id := fault.Target.Components[0]
+			if err := engine.app.Pause(id, false); err != nil {
+				return nil, err
+			}
+			return func() error { return engine.app.Unpause(id, false) }, nil
+
+		case chaos.Partition:
+			id, err := engine.app.Partition(fault.Groups)
+			if err != nil {
+				return nil, err
+			}
+			return func() error { return engine.app.Heal(id, false) }, nil
+
+		default:
+			return nil, errors.New("unknown fault type: " + fault.Type)
+		}
+	}
+}
+
+func disruptChaosTarget(target chaos.Target) (func() error, error) {
+	c := target.Components
+	switch target.Kind {
+	case chaos.NodeTarget:
+		if err := engine.app.DisruptNode(c[0], "", 0); err != nil {
+			return nil, err
+		}
+		return func() error { return engine.app.StopDisruptNode(c[0], "") }, nil
+
+	case chaos.BridgeTarget:
+		if err := engine.app.DisruptBridge(c[0], "", 0); err != nil {
+			return nil, err
+		}
+		return func() error { return engine.app.StopDisruptBridge(c[0], "") }, nil
+
+	default:
+		if err := engine.app.DisruptRouters(c[0], c[1], "", 0); err != nil {
+			return nil, err
+		}
+		return func() error { return engine.app.StopDisruptRouters(c[0], c[1], "") }, nil
+	}
+}
+
+// Latency spikes are split between both directions, as the latency of the connect requests.
+// Loss bursts replace the drop rate of each direction and suspend its loss model, which would ignore the drop rate.
+func degradeChaosTarget(fault chaos.Fault, config chaos.Config) (func() error, error) {
+	c := fault.Target.Components
+	upstream, downstream, err := engine.app.LinkDirectionsBetween(c[0], c[1])
+	if err != nil {
+		return nil, err
+	}
+
+	up, err := daemon.ParseLinkDirection(network.LinkProps{}, upstream)
+	if err != nil {
+		return nil, err
+	}
+	down, err := daemon.ParseLinkDirection(network.LinkProps{}, downstream)
+	if err != nil {
+		return nil, err
+	}
+
+	degrade := func(props network.LinkProps) network.LinkProps {
+		if fault.Type == chaos.LatencySpike {
+			props.Latency += config.LatencySpike / 2
+			props.FLatency = float64(props.Latency) / float64(time.Millisecond) * 2.0
+		} else {
+			props.DropRate = config.LossBurst
+			props.Loss = network.GilbertElliott{}
+		}
+		return props
+	}
+
+	update := func(up network.LinkProps, down network.LinkProps) error {
+		switch fault.Target.Kind {
+		case chaos.NodeTarget:
+			return engine.app.UpdateNodeLink(c[0], up, down)
+		case chaos.BridgeTarget:
+			return engine.app.UpdateBridgeLink(c[0], up, down)
+		default:
+			return engine.app.UpdateRouterLink(c[0], c[1], up, down)
+		}
+	}
+	if err = update(degrade(up), degrade(down)); err != nil {
+		return nil, err
+	}
+	return func() error { return update(up, down) }, nil
+}
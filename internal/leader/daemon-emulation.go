@@ -11,8 +11,10 @@ import (
 	apiErrors "github.com/David-Antunes/gone/api/Errors"
 	inspectApi "github.com/David-Antunes/gone/api/Inspect"
 	removeApi "github.com/David-Antunes/gone/api/Remove"
+	updateApi "github.com/David-Antunes/gone/api/Update"
 	internal "github.com/David-Antunes/gone/internal/api"
 	"github.com/David-Antunes/gone/internal/daemon"
+	"github.com/David-Antunes/gone/internal/network"
 	"log"
 	"net/http"
 	"os"
@@ -267,7 +269,7 @@ func connectNodeToBridge(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	linkProps, err := daemon.ParseLinkProps(req.Latency, req.Bandwidth, req.Jitter, req.DropRate, req.Weight)
+	up, down, err := daemon.ParseBiLinkProps(req.Latency, req.Bandwidth, req.Jitter, req.DropRate, req.Weight, req.Loss, req.Impairments, req.Distribution, req.Queue, req.Trace, req.Upstream, req.Downstream)
 
 	if err != nil {
 		daemonLog.Println("connectNodeToBridge:", err)
@@ -281,7 +283,7 @@ func connectNodeToBridge(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	err = engine.app.ConnectNodeToBridge(req.Node, req.Bridge, linkProps)
+	err = engine.app.ConnectNodeToBridge(req.Node, req.Bridge, up, down)
 
 	if err != nil {
 		daemonLog.Println("connectNodeToBridge:", err)
@@ -301,7 +303,7 @@ func connectNodeToBridge(w http.ResponseWriter, r *http.Request) {
 		Error:  apiErrors.Error{},
 	})
 
-	daemonLog.Println("connectNodeToBridge:", "Connected", req.Node, "to", req.Bridge, "Properties:", linkProps)
+	daemonLog.Println("connectNodeToBridge:", "Connected", req.Node, "to", req.Bridge, "Upstream:", up, "Downstream:", down)
 }
 
 func connectBridgeToRouter(w http.ResponseWriter, r *http.Request) {
@@ -320,7 +322,7 @@ func connectBridgeToRouter(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	linkProps, err := daemon.ParseLinkProps(req.Latency, req.Bandwidth, req.Jitter, req.DropRate, req.Weight)
+	up, down, err := daemon.ParseBiLinkProps(req.Latency, req.Bandwidth, req.Jitter, req.DropRate, req.Weight, req.Loss, req.Impairments, req.Distribution, req.Queue, req.Trace, req.Upstream, req.Downstream)
 	if err != nil {
 		daemonLog.Println("connectBridgeToRouter:", err)
 		daemon.SendError(w, &connectApi.ConnectBridgeToRouterResponse{
@@ -333,7 +335,7 @@ func connectBridgeToRouter(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	err = engine.app.ConnectBridgeToRouter(req.Bridge, req.Router, linkProps)
+	err = engine.app.ConnectBridgeToRouter(req.Bridge, req.Router, up, down)
 
 	if err != nil {
 
@@ -355,7 +357,7 @@ func connectBridgeToRouter(w http.ResponseWriter, r *http.Request) {
 		Error:  apiErrors.Error{},
 	})
 
-	daemonLog.Println("connectBridgeToRouter:", "Connected", req.Bridge, "to", req.Router, "Properties:", linkProps)
+	daemonLog.Println("connectBridgeToRouter:", "Connected", req.Bridge, "to", req.Router, "Upstream:", up, "Downstream:", down)
 }
 
 func connectRouterToRouter(w http.ResponseWriter, r *http.Request) {
@@ -374,7 +376,7 @@ func connectRouterToRouter(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	linkProps, err := daemon.ParseLinkProps(req.Latency, req.Bandwidth, req.Jitter, req.DropRate, req.Weight)
+	up, down, err := daemon.ParseBiLinkProps(req.Latency, req.Bandwidth, req.Jitter, req.DropRate, req.Weight, req.Loss, req.Impairments, req.Distribution, req.Queue, req.Trace, req.Upstream, req.Downstream)
 	if err != nil {
 		daemonLog.Println("connectRouterToRouter:", err)
 		daemon.SendError(w, &connectApi.ConnectRouterToRouterResponse{
@@ -388,7 +390,7 @@ func connectRouterToRouter(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = engine.app.ConnectRouterToRouter(req.From, req.To, linkProps, req.Propagate)
+	err = engine.app.ConnectRouterToRouter(req.From, req.To, up, down, req.Propagate)
 
 	if err != nil {
 
@@ -410,7 +412,7 @@ func connectRouterToRouter(w http.ResponseWriter, r *http.Request) {
 		Error: apiErrors.Error{},
 	})
 
-	daemonLog.Println("connectRouterToRouter:", "Connected", req.From, "to", req.To, "Properties:", linkProps)
+	daemonLog.Println("connectRouterToRouter:", "Connected", req.From, "to", req.To, "Upstream:", up, "Downstream:", down)
 }
 
 func connectRouterToRouterRemote(w http.ResponseWriter, r *http.Request) {
@@ -431,23 +433,11 @@ func connectRouterToRouterRemote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	linkProps, err := daemon.ParseLinkPropsInternal(req.Latency, req.Bandwidth, req.Jitter, req.DropRate, req.Weight)
-	if err != nil {
-		daemonLog.Println("connectRouterToRouterRemote:", err)
-		daemon.SendError(w, &internal.ConnectRouterToRouterResponse{
-			R1:        "",
-			R2:        "",
-			MachineID: "",
-			Error: apiErrors.Error{
-				ErrCode: 1,
-				ErrMsg:  err.Error(),
-			},
-		})
-		return
-	}
-
 	if req.MachineID != engine.app.GetMachineId() {
-		err := engine.app.ApplyConnectRouterToRouterRemote(req.R1, req.R2, req.MachineID, linkProps, req.Propagate)
+		linkProps, err := daemon.ParseLinkDirection(network.LinkProps{Weight: req.Weight}, req.Up)
+		if err == nil {
+			err = engine.app.ApplyConnectRouterToRouterRemote(req.R1, req.R2, req.MachineID, linkProps, req.Propagate)
+		}
 		if err != nil {
 
 			daemonLog.Println("connectRouterToRouterRemote:", err)
@@ -928,3 +918,322 @@ func inspectRouter(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func inspectLink(w http.ResponseWriter, r *http.Request) {
+
+	req := &inspectApi.InspectLinkRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("inspectLink:", err)
+		daemon.SendError(w, &inspectApi.InspectLinkResponse{
+			Name: req.Name,
+			Link: api.LinkState{},
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	link, err := engine.app.InspectLink(req.Name)
+
+	if err != nil {
+		daemonLog.Println("inspectLink:", err)
+		daemon.SendError(w, &inspectApi.InspectLinkResponse{
+			Name: req.Name,
+			Link: api.LinkState{},
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &inspectApi.InspectLinkResponse{
+		Name:  req.Name,
+		Link:  link,
+		Error: apiErrors.Error{},
+	})
+}
+
+func inspectLinkRemote(w http.ResponseWriter, r *http.Request) {
+
+	req := &internal.InspectLinkRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("inspectLinkRemote:", err)
+		daemon.SendError(w, &internal.InspectLinkResponse{
+			Link: api.LinkState{},
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	link, err := engine.app.InspectLinkBetween(req.From, req.To)
+
+	if err != nil {
+		daemonLog.Println("inspectLinkRemote:", err)
+		daemon.SendError(w, &internal.InspectLinkResponse{
+			Link: api.LinkState{},
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &internal.InspectLinkResponse{
+		Link:  link,
+		Error: apiErrors.Error{},
+	})
+}
+
+func linkDirectionsRemote(w http.ResponseWriter, r *http.Request) {
+
+	req := &internal.LinkDirectionsRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("linkDirectionsRemote:", err)
+		daemon.SendError(w, &internal.LinkDirectionsResponse{
+			Upstream:   nil,
+			Downstream: nil,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	var up, down *connectApi.LinkDirection
+	var weight int
+	var err error
+	if req.Owned {
+		up, down, weight, err = engine.app.OwnedLinkDirections(req.From, req.To)
+	} else {
+		up, down, err = engine.app.LinkDirectionsBetween(req.From, req.To)
+	}
+
+	if err != nil {
+		daemonLog.Println("linkDirectionsRemote:", err)
+		daemon.SendError(w, &internal.LinkDirectionsResponse{
+			Upstream:   nil,
+			Downstream: nil,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &internal.LinkDirectionsResponse{
+		Upstream:   up,
+		Downstream: down,
+		Weight:     weight,
+		Error:      apiErrors.Error{},
+	})
+}
+
+func updateNodeLink(w http.ResponseWriter, r *http.Request) {
+
+	req := &updateApi.UpdateNodeLinkRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("updateNodeLink:", err)
+		daemon.SendError(w, &updateApi.UpdateNodeLinkResponse{
+			Node: req.Node,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	up, down, err := daemon.ParseBiLinkProps(req.Latency, req.Bandwidth, req.Jitter, req.DropRate, 0, req.Loss, req.Impairments, req.Distribution, req.Queue, req.Trace, req.Upstream, req.Downstream)
+
+	if err != nil {
+		daemonLog.Println("updateNodeLink:", err)
+		daemon.SendError(w, &updateApi.UpdateNodeLinkResponse{
+			Node: req.Node,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	err = engine.app.UpdateNodeLink(req.Node, up, down)
+
+	if err != nil {
+		daemonLog.Println("updateNodeLink:", err)
+		daemon.SendError(w, &updateApi.UpdateNodeLinkResponse{
+			Node: req.Node,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &updateApi.UpdateNodeLinkResponse{
+		Node:  req.Node,
+		Error: apiErrors.Error{},
+	})
+
+	daemonLog.Println("updateNodeLink:", "Updated", req.Node, "Upstream:", up, "Downstream:", down)
+}
+
+func updateBridgeLink(w http.ResponseWriter, r *http.Request) {
+
+	req := &updateApi.UpdateBridgeLinkRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("updateBridgeLink:", err)
+		daemon.SendError(w, &updateApi.UpdateBridgeLinkResponse{
+			Bridge: req.Bridge,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	up, down, err := daemon.ParseBiLinkProps(req.Latency, req.Bandwidth, req.Jitter, req.DropRate, 0, req.Loss, req.Impairments, req.Distribution, req.Queue, req.Trace, req.Upstream, req.Downstream)
+
+	if err != nil {
+		daemonLog.Println("updateBridgeLink:", err)
+		daemon.SendError(w, &updateApi.UpdateBridgeLinkResponse{
+			Bridge: req.Bridge,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	err = engine.app.UpdateBridgeLink(req.Bridge, up, down)
+
+	if err != nil {
+		daemonLog.Println("updateBridgeLink:", err)
+		daemon.SendError(w, &updateApi.UpdateBridgeLinkResponse{
+			Bridge: req.Bridge,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &updateApi.UpdateBridgeLinkResponse{
+		Bridge: req.Bridge,
+		Error:  apiErrors.Error{},
+	})
+
+	daemonLog.Println("updateBridgeLink:", "Updated", req.Bridge, "Upstream:", up, "Downstream:", down)
+}
+
+func updateRouterLink(w http.ResponseWriter, r *http.Request) {
+
+	req := &updateApi.UpdateRouterLinkRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("updateRouterLink:", err)
+		daemon.SendError(w, &updateApi.UpdateRouterLinkResponse{
+			Router1: req.Router1,
+			Router2: req.Router2,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	up, down, err := daemon.ParseBiLinkProps(req.Latency, req.Bandwidth, req.Jitter, req.DropRate, 0, req.Loss, req.Impairments, req.Distribution, req.Queue, req.Trace, req.Upstream, req.Downstream)
+
+	if err != nil {
+		daemonLog.Println("updateRouterLink:", err)
+		daemon.SendError(w, &updateApi.UpdateRouterLinkResponse{
+			Router1: req.Router1,
+			Router2: req.Router2,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	err = engine.app.UpdateRouterLink(req.Router1, req.Router2, up, down)
+
+	if err != nil {
+		daemonLog.Println("updateRouterLink:", err)
+		daemon.SendError(w, &updateApi.UpdateRouterLinkResponse{
+			Router1: req.Router1,
+			Router2: req.Router2,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &updateApi.UpdateRouterLinkResponse{
+		Router1: req.Router1,
+		Router2: req.Router2,
+		Error:   apiErrors.Error{},
+	})
+
+	daemonLog.Println("updateRouterLink:", "Updated", req.Router1, "to", req.Router2, "Upstream:", up, "Downstream:", down)
+}
+
+func updateRouterLinkRemote(w http.ResponseWriter, r *http.Request) {
+
+	req := &internal.UpdateRouterLinkRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("updateRouterLinkRemote:", err)
+		daemon.SendError(w, &internal.UpdateRouterLinkResponse{
+			R1: req.R1,
+			R2: req.R2,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	linkProps, err := daemon.ParseLinkDirection(network.LinkProps{Weight: req.Weight}, req.Props)
+	if err == nil {
+		err = engine.app.ApplyUpdateRouterLinkRemote(req.R1, req.R2, linkProps)
+	}
+
+	if err != nil {
+		daemonLog.Println("updateRouterLinkRemote:", err)
+		daemon.SendError(w, &internal.UpdateRouterLinkResponse{
+			R1: req.R1,
+			R2: req.R2,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &internal.UpdateRouterLinkResponse{
+		R1:    req.R1,
+		R2:    req.R2,
+		Error: apiErrors.Error{},
+	})
+	daemonLog.Println("updateRouterLinkRemote:", "Updated", req.R1, "to", req.R2)
+}
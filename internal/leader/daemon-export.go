@@ -0,0 +1,30 @@
+package leader
+
+import (
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+	topologyApi "github.com/David-Antunes/gone/api/Topology"
+	"github.com/David-Antunes/gone/internal/daemon"
+	"net/http"
+)
+
+func export(w http.ResponseWriter, r *http.Request) {
+
+	doc, err := engine.app.Export()
+
+	if err != nil {
+		daemonLog.Println("export:", err)
+		daemon.SendError(w, &topologyApi.ExportResponse{
+			Document: topologyApi.Document{},
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &topologyApi.ExportResponse{
+		Document: doc,
+		Error:    apiErrors.Error{},
+	})
+}
@@ -0,0 +1,121 @@
+package leader
+
+import (
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+	topologyApi "github.com/David-Antunes/gone/api/Topology"
+	"github.com/David-Antunes/gone/internal/daemon"
+	"github.com/David-Antunes/gone/internal/generator"
+	"github.com/David-Antunes/gone/internal/network"
+	"net/http"
+)
+
+func generate(w http.ResponseWriter, r *http.Request) {
+
+	req := &topologyApi.GenerateRequest{}
+
+	if err := daemon.ParseDocument(r, req); err != nil {
+		daemonLog.Println("generate:", err)
+		daemon.SendError(w, &topologyApi.GenerateResponse{
+			Document: topologyApi.Document{},
+			Steps:    nil,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	doc, err := generateDocument(req)
+
+	if err != nil {
+		daemonLog.Println("generate:", err)
+		daemon.SendError(w, &topologyApi.GenerateResponse{
+			Document: topologyApi.Document{},
+			Steps:    nil,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	applyLock.Lock()
+	defer applyLock.Unlock()
+
+	steps, err := planDocument(&doc, propagatedRouters(&doc))
+
+	if err != nil {
+		daemonLog.Println("generate:", err)
+		daemon.SendError(w, &topologyApi.GenerateResponse{
+			Document: doc,
+			Steps:    nil,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	if req.DryRun {
+		daemon.SendResponse(w, &topologyApi.GenerateResponse{
+			Document: doc,
+			Steps:    plannedSteps(steps),
+			Error:    apiErrors.Error{},
+		})
+		return
+	}
+
+	results, err := runSteps(steps)
+
+	if err != nil {
+		daemonLog.Println("generate:", err)
+		daemon.SendError(w, &topologyApi.GenerateResponse{
+			Document: doc,
+			Steps:    results,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &topologyApi.GenerateResponse{
+		Document: doc,
+		Steps:    results,
+		Error:    apiErrors.Error{},
+	})
+	daemonLog.Println("generate:", "Generated", req.Shape, "with", len(doc.Routers), "routers and", len(doc.Bridges), "bridges")
+}
+
+// Topologies are placed on the leader when no machines are given
+func generateDocument(req *topologyApi.GenerateRequest) (topologyApi.Document, error) {
+	machines := req.Machines
+	if len(machines) == 0 {
+		machines = []string{engine.app.GetMachineId()}
+	}
+	seed := req.Seed
+	if seed == 0 {
+		seed = network.GetEmulationSeed()
+	}
+	bottleneck := req.Link
+	if req.Bottleneck != nil {
+		bottleneck = *req.Bottleneck
+	}
+
+	return generator.Generate(generator.Config{
+		Shape:    req.Shape,
+		Prefix:   req.Prefix,
+		Routers:  req.Routers,
+		Bridges:  req.Bridges,
+		Degree:   req.Degree,
+		Alpha:    req.Alpha,
+		Beta:     req.Beta,
+		Seed:     seed,
+		Machines: machines,
+		Spread:   req.Spread,
+	}, req.Link, bottleneck)
+}
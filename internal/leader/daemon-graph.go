@@ -0,0 +1,33 @@
+package leader
+
+import (
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+	topologyApi "github.com/David-Antunes/gone/api/Topology"
+	"github.com/David-Antunes/gone/internal/application"
+	"github.com/David-Antunes/gone/internal/daemon"
+	"net/http"
+)
+
+func graph(w http.ResponseWriter, r *http.Request) {
+
+	g, err := engine.app.Graph()
+
+	if err != nil {
+		daemonLog.Println("graph:", err)
+		daemon.SendError(w, &topologyApi.GraphResponse{
+			Graph: topologyApi.Graph{},
+			Dot:   "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &topologyApi.GraphResponse{
+		Graph: g,
+		Dot:   application.GraphDot(g),
+		Error: apiErrors.Error{},
+	})
+}
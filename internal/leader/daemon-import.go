@@ -0,0 +1,108 @@
+package leader
+
+import (
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+	topologyApi "github.com/David-Antunes/gone/api/Topology"
+	"github.com/David-Antunes/gone/internal/daemon"
+	"github.com/David-Antunes/gone/internal/importer"
+	"net/http"
+)
+
+func importTopology(w http.ResponseWriter, r *http.Request) {
+
+	req := &topologyApi.ImportRequest{}
+
+	if err := daemon.ParseDocument(r, req); err != nil {
+		daemonLog.Println("import:", err)
+		daemon.SendError(w, &topologyApi.ImportResponse{
+			Document:     topologyApi.Document{},
+			Untranslated: nil,
+			Steps:        nil,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	machineId := req.MachineId
+	if machineId == "" {
+		machineId = engine.app.GetMachineId()
+	}
+
+	doc, untranslated, err := importer.Import(req.Format, req.Contents, importer.Options{
+		MachineId: machineId,
+		Network:   engine.app.GetNetwork(),
+		Image:     req.Image,
+		Link:      req.Link,
+		Bridges:   req.Bridges,
+	})
+
+	if err != nil {
+		daemonLog.Println("import:", err)
+		daemon.SendError(w, &topologyApi.ImportResponse{
+			Document:     topologyApi.Document{},
+			Untranslated: nil,
+			Steps:        nil,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	applyLock.Lock()
+	defer applyLock.Unlock()
+
+	steps, err := planDocument(&doc, propagatedRouters(&doc))
+
+	if err != nil {
+		daemonLog.Println("import:", err)
+		daemon.SendError(w, &topologyApi.ImportResponse{
+			Document:     doc,
+			Untranslated: untranslated,
+			Steps:        nil,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	if req.DryRun {
+		daemon.SendResponse(w, &topologyApi.ImportResponse{
+			Document:     doc,
+			Untranslated: untranslated,
+			Steps:        plannedSteps(steps),
+			Error:        apiErrors.Error{},
+		})
+		return
+	}
+
+	results, err := runSteps(steps)
+
+	if err != nil {
+		daemonLog.Println("import:", err)
+		daemon.SendError(w, &topologyApi.ImportResponse{
+			Document:     doc,
+			Untranslated: untranslated,
+			Steps:        results,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &topologyApi.ImportResponse{
+		Document:     doc,
+		Untranslated: untranslated,
+		Steps:        results,
+		Error:        apiErrors.Error{},
+	})
+	daemonLog.Println("import:", "Imported", req.Format, "topology with", len(untranslated), "untranslated constructs")
+}
@@ -27,7 +27,7 @@ func registerNode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := engine.app.RegisterNode(req.Id, req.Mac, req.Ip, req.MachineId)
+	err := engine.app.RegisterNode(req.Id, req.Mac, req.Ip, req.MachineId, req.DockerCmd)
 
 	if err != nil {
 		daemonLog.Println("registerNode:", err)
@@ -623,6 +623,81 @@ func unpause(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func crashNode(w http.ResponseWriter, r *http.Request) {
+
+	req := &opApi.CrashNodeRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("crashNode:", err)
+		daemon.SendError(w, &opApi.CrashNodeResponse{
+			Node: req.Node,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	restartAfter, err := daemon.ParseDuration(req.RestartMs)
+	if err == nil {
+		err = engine.app.CrashNode(req.Node, req.Kill, restartAfter)
+	}
+
+	if err != nil {
+		daemonLog.Println("crashNode:", err)
+		daemon.SendError(w, &opApi.CrashNodeResponse{
+			Node: req.Node,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &opApi.CrashNodeResponse{
+		Node:  req.Node,
+		Error: apiErrors.Error{},
+	})
+	daemonLog.Println("crashNode:", "Crashed", req.Node)
+}
+
+func restartNode(w http.ResponseWriter, r *http.Request) {
+
+	req := &opApi.RestartNodeRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("restartNode:", err)
+		daemon.SendError(w, &opApi.RestartNodeResponse{
+			Node: req.Node,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	err := engine.app.RestartNode(req.Node)
+
+	if err != nil {
+		daemonLog.Println("restartNode:", err)
+		daemon.SendError(w, &opApi.RestartNodeResponse{
+			Node: req.Node,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &opApi.RestartNodeResponse{
+		Node:  req.Node,
+		Error: apiErrors.Error{},
+	})
+	daemonLog.Println("restartNode:", "Restarted", req.Node)
+}
+
 func disruptNode(w http.ResponseWriter, r *http.Request) {
 
 	req := &opApi.DisruptNodeRequest{}
@@ -637,7 +712,13 @@ func disruptNode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := engine.app.DisruptNode(req.Node)
+	duration, err := daemon.ParseDuration(req.DurationMs)
+	if err == nil {
+		_, err = daemon.ParseDirection(req.Direction)
+	}
+	if err == nil {
+		err = engine.app.DisruptNode(req.Node, req.Direction, duration)
+	}
 
 	if err != nil {
 		daemonLog.Println("disruptNode:", err)
@@ -671,7 +752,13 @@ func disruptBridge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := engine.app.DisruptBridge(req.Bridge)
+	duration, err := daemon.ParseDuration(req.DurationMs)
+	if err == nil {
+		_, err = daemon.ParseDirection(req.Direction)
+	}
+	if err == nil {
+		err = engine.app.DisruptBridge(req.Bridge, req.Direction, duration)
+	}
 
 	if err != nil {
 		daemonLog.Println("disruptBridge:", err)
@@ -706,7 +793,13 @@ func disruptRouters(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := engine.app.DisruptRouters(req.Router1, req.Router2)
+	duration, err := daemon.ParseDuration(req.DurationMs)
+	if err == nil {
+		_, err = daemon.ParseDirection(req.Direction)
+	}
+	if err == nil {
+		err = engine.app.DisruptRouters(req.Router1, req.Router2, req.Direction, duration)
+	}
 
 	if err != nil {
 		daemonLog.Println("disruptRouters:", err)
@@ -740,7 +833,10 @@ func stopDisruptNode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := engine.app.StopDisruptNode(req.Node)
+	_, err := daemon.ParseDirection(req.Direction)
+	if err == nil {
+		err = engine.app.StopDisruptNode(req.Node, req.Direction)
+	}
 
 	if err != nil {
 		daemonLog.Println("stopDisruptNode:", err)
@@ -774,7 +870,10 @@ func stopDisruptBridge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := engine.app.StopDisruptBridge(req.Bridge)
+	_, err := daemon.ParseDirection(req.Direction)
+	if err == nil {
+		err = engine.app.StopDisruptBridge(req.Bridge, req.Direction)
+	}
 
 	if err != nil {
 		daemonLog.Println("stopDisruptBridge:", err)
@@ -809,7 +908,10 @@ func stopDisruptRouters(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := engine.app.StopDisruptRouters(req.Router1, req.Router2)
+	_, err := daemon.ParseDirection(req.Direction)
+	if err == nil {
+		err = engine.app.StopDisruptRouters(req.Router1, req.Router2, req.Direction)
+	}
 
 	if err != nil {
 		daemonLog.Println("stopDisruptRouters:", err)
@@ -965,3 +1067,481 @@ func stopRouter(w http.ResponseWriter, r *http.Request) {
 		Error:  apiErrors.Error{},
 	})
 }
+
+func listDisruptions(w http.ResponseWriter, r *http.Request) {
+
+	daemon.SendResponse(w, &opApi.ListDisruptionsResponse{
+		Disruptions: engine.app.ListDisruptions(),
+		Error:       apiErrors.Error{},
+	})
+}
+
+func partition(w http.ResponseWriter, r *http.Request) {
+
+	req := &opApi.PartitionRequest{}
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("partition:", err)
+		daemon.SendError(w, &opApi.PartitionResponse{
+			Id:     "",
+			Groups: req.Groups,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	id, err := engine.app.Partition(req.Groups)
+
+	if err != nil {
+		daemonLog.Println("partition:", err)
+		daemon.SendError(w, &opApi.PartitionResponse{
+			Id:     "",
+			Groups: req.Groups,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &opApi.PartitionResponse{
+		Id:     id,
+		Groups: req.Groups,
+		Error:  apiErrors.Error{},
+	})
+}
+
+func heal(w http.ResponseWriter, r *http.Request) {
+
+	req := &opApi.HealRequest{}
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("heal:", err)
+		daemon.SendError(w, &opApi.HealResponse{
+			Id:  req.Id,
+			All: req.All,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	err := engine.app.Heal(req.Id, req.All)
+
+	if err != nil {
+		daemonLog.Println("heal:", err)
+		daemon.SendError(w, &opApi.HealResponse{
+			Id:  req.Id,
+			All: req.All,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &opApi.HealResponse{
+		Id:    req.Id,
+		All:   req.All,
+		Error: apiErrors.Error{},
+	})
+}
+
+func listPartitions(w http.ResponseWriter, r *http.Request) {
+
+	daemon.SendResponse(w, &opApi.ListPartitionsResponse{
+		Partitions: engine.app.ListPartitions(),
+		Error:      apiErrors.Error{},
+	})
+}
+
+func flapNode(w http.ResponseWriter, r *http.Request) {
+
+	req := &opApi.FlapNodeRequest{}
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("flapNode:", err)
+		daemon.SendError(w, &opApi.FlapNodeResponse{
+			Node: req.Node,
+			Id:   "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	var id string
+	props, err := daemon.ParseFlapConfig(req.FlapConfig)
+	if err == nil {
+		_, err = daemon.ParseDirection(req.Direction)
+	}
+	if err == nil {
+		id, err = engine.app.FlapNode(req.Node, req.Direction, props)
+	}
+
+	if err != nil {
+		daemonLog.Println("flapNode:", err)
+		daemon.SendError(w, &opApi.FlapNodeResponse{
+			Node: req.Node,
+			Id:   "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &opApi.FlapNodeResponse{
+		Node:  req.Node,
+		Id:    id,
+		Error: apiErrors.Error{},
+	})
+}
+
+func flapBridge(w http.ResponseWriter, r *http.Request) {
+
+	req := &opApi.FlapBridgeRequest{}
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("flapBridge:", err)
+		daemon.SendError(w, &opApi.FlapBridgeResponse{
+			Bridge: req.Bridge,
+			Id:     "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	var id string
+	props, err := daemon.ParseFlapConfig(req.FlapConfig)
+	if err == nil {
+		_, err = daemon.ParseDirection(req.Direction)
+	}
+	if err == nil {
+		id, err = engine.app.FlapBridge(req.Bridge, req.Direction, props)
+	}
+
+	if err != nil {
+		daemonLog.Println("flapBridge:", err)
+		daemon.SendError(w, &opApi.FlapBridgeResponse{
+			Bridge: req.Bridge,
+			Id:     "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &opApi.FlapBridgeResponse{
+		Bridge: req.Bridge,
+		Id:     id,
+		Error:  apiErrors.Error{},
+	})
+}
+
+func flapRouters(w http.ResponseWriter, r *http.Request) {
+
+	req := &opApi.FlapRoutersRequest{}
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("flapRouters:", err)
+		daemon.SendError(w, &opApi.FlapRoutersResponse{
+			Router1: req.Router1,
+			Router2: req.Router2,
+			Id:      "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	var id string
+	props, err := daemon.ParseFlapConfig(req.FlapConfig)
+	if err == nil {
+		_, err = daemon.ParseDirection(req.Direction)
+	}
+	if err == nil {
+		id, err = engine.app.FlapRouters(req.Router1, req.Router2, req.Direction, props)
+	}
+
+	if err != nil {
+		daemonLog.Println("flapRouters:", err)
+		daemon.SendError(w, &opApi.FlapRoutersResponse{
+			Router1: req.Router1,
+			Router2: req.Router2,
+			Id:      "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &opApi.FlapRoutersResponse{
+		Router1: req.Router1,
+		Router2: req.Router2,
+		Id:      id,
+		Error:   apiErrors.Error{},
+	})
+}
+
+func stopFlap(w http.ResponseWriter, r *http.Request) {
+
+	req := &opApi.StopFlapRequest{}
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("stopFlap:", err)
+		daemon.SendError(w, &opApi.StopFlapResponse{
+			Id: req.Id,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	err := engine.app.StopFlap(req.Id)
+
+	if err != nil {
+		daemonLog.Println("stopFlap:", err)
+		daemon.SendError(w, &opApi.StopFlapResponse{
+			Id: req.Id,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &opApi.StopFlapResponse{
+		Id:    req.Id,
+		Error: apiErrors.Error{},
+	})
+}
+
+func listFlaps(w http.ResponseWriter, r *http.Request) {
+
+	daemon.SendResponse(w, &opApi.ListFlapsResponse{
+		Flaps: engine.app.ListFlaps(),
+		Error: apiErrors.Error{},
+	})
+}
+
+func rampNode(w http.ResponseWriter, r *http.Request) {
+
+	req := &opApi.RampNodeRequest{}
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("rampNode:", err)
+		daemon.SendError(w, &opApi.RampNodeResponse{
+			Node: req.Node,
+			Id:   "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	var id string
+	props, err := daemon.ParseRampConfig(req.RampConfig, req.Direction)
+	if err == nil {
+		_, err = daemon.ParseDirection(req.Direction)
+	}
+	if err == nil {
+		id, err = engine.app.RampNode(req.Node, req.Direction, props)
+	}
+
+	if err != nil {
+		daemonLog.Println("rampNode:", err)
+		daemon.SendError(w, &opApi.RampNodeResponse{
+			Node: req.Node,
+			Id:   "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &opApi.RampNodeResponse{
+		Node:  req.Node,
+		Id:    id,
+		Error: apiErrors.Error{},
+	})
+}
+
+func rampBridge(w http.ResponseWriter, r *http.Request) {
+
+	req := &opApi.RampBridgeRequest{}
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("rampBridge:", err)
+		daemon.SendError(w, &opApi.RampBridgeResponse{
+			Bridge: req.Bridge,
+			Id:     "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	var id string
+	props, err := daemon.ParseRampConfig(req.RampConfig, req.Direction)
+	if err == nil {
+		_, err = daemon.ParseDirection(req.Direction)
+	}
+	if err == nil {
+		id, err = engine.app.RampBridge(req.Bridge, req.Direction, props)
+	}
+
+	if err != nil {
+		daemonLog.Println("rampBridge:", err)
+		daemon.SendError(w, &opApi.RampBridgeResponse{
+			Bridge: req.Bridge,
+			Id:     "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &opApi.RampBridgeResponse{
+		Bridge: req.Bridge,
+		Id:     id,
+		Error:  apiErrors.Error{},
+	})
+}
+
+func rampRouters(w http.ResponseWriter, r *http.Request) {
+
+	req := &opApi.RampRoutersRequest{}
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("rampRouters:", err)
+		daemon.SendError(w, &opApi.RampRoutersResponse{
+			Router1: req.Router1,
+			Router2: req.Router2,
+			Id:      "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	var id string
+	props, err := daemon.ParseRampConfig(req.RampConfig, req.Direction)
+	if err == nil {
+		_, err = daemon.ParseDirection(req.Direction)
+	}
+	if err == nil {
+		id, err = engine.app.RampRouters(req.Router1, req.Router2, req.Direction, props)
+	}
+
+	if err != nil {
+		daemonLog.Println("rampRouters:", err)
+		daemon.SendError(w, &opApi.RampRoutersResponse{
+			Router1: req.Router1,
+			Router2: req.Router2,
+			Id:      "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &opApi.RampRoutersResponse{
+		Router1: req.Router1,
+		Router2: req.Router2,
+		Id:      id,
+		Error:   apiErrors.Error{},
+	})
+}
+
+func stopRamp(w http.ResponseWriter, r *http.Request) {
+
+	req := &opApi.StopRampRequest{}
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("stopRamp:", err)
+		daemon.SendError(w, &opApi.StopRampResponse{
+			Id: req.Id,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	err := engine.app.StopRamp(req.Id, req.Restore)
+
+	if err != nil {
+		daemonLog.Println("stopRamp:", err)
+		daemon.SendError(w, &opApi.StopRampResponse{
+			Id: req.Id,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &opApi.StopRampResponse{
+		Id:    req.Id,
+		Error: apiErrors.Error{},
+	})
+}
+
+func reverseRamp(w http.ResponseWriter, r *http.Request) {
+
+	req := &opApi.ReverseRampRequest{}
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("reverseRamp:", err)
+		daemon.SendError(w, &opApi.ReverseRampResponse{
+			Id: req.Id,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	err := engine.app.ReverseRamp(req.Id)
+
+	if err != nil {
+		daemonLog.Println("reverseRamp:", err)
+		daemon.SendError(w, &opApi.ReverseRampResponse{
+			Id: req.Id,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+	daemon.SendResponse(w, &opApi.ReverseRampResponse{
+		Id:    req.Id,
+		Error: apiErrors.Error{},
+	})
+}
+
+func listRamps(w http.ResponseWriter, r *http.Request) {
+
+	daemon.SendResponse(w, &opApi.ListRampsResponse{
+		Ramps: engine.app.ListRamps(),
+		Error: apiErrors.Error{},
+	})
+}
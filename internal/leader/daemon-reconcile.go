@@ -0,0 +1,426 @@
+package leader
+
+import (
+	"errors"
+	connectApi "github.com/David-Antunes/gone/api/Connect"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+	topologyApi "github.com/David-Antunes/gone/api/Topology"
+	"github.com/David-Antunes/gone/internal/application"
+	"github.com/David-Antunes/gone/internal/daemon"
+	"github.com/David-Antunes/gone/internal/network"
+	"net/http"
+	"reflect"
+	"slices"
+)
+
+const stepPlanned = "planned"
+
+type liveComponent struct {
+	kind      string
+	machineId string
+	dockerCmd []string
+}
+
+func reconcile(w http.ResponseWriter, r *http.Request) {
+
+	req := &topologyApi.ReconcileRequest{}
+
+	if err := daemon.ParseDocument(r, req); err != nil {
+		daemonLog.Println("reconcile:", err)
+		daemon.SendError(w, &topologyApi.ReconcileResponse{
+			Steps:  nil,
+			DryRun: false,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	applyLock.Lock()
+	defer applyLock.Unlock()
+
+	steps, err := planReconcile(&req.Document)
+
+	if err != nil {
+		daemonLog.Println("reconcile:", err)
+		daemon.SendError(w, &topologyApi.ReconcileResponse{
+			Steps:  nil,
+			DryRun: req.DryRun,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	if req.DryRun {
+		daemon.SendResponse(w, &topologyApi.ReconcileResponse{
+			Steps:  plannedSteps(steps),
+			DryRun: true,
+			Error:  apiErrors.Error{},
+		})
+		return
+	}
+
+	results, err := runSteps(steps)
+
+	if err != nil {
+		daemonLog.Println("reconcile:", err)
+		daemon.SendError(w, &topologyApi.ReconcileResponse{
+			Steps:  results,
+			DryRun: false,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &topologyApi.ReconcileResponse{
+		Steps:  results,
+		DryRun: false,
+		Error:  apiErrors.Error{},
+	})
+	daemonLog.Println("reconcile:", "Applied", len(results), "steps")
+}
+
+func plannedSteps(steps []applyStep) []topologyApi.Step {
+	planned := make([]topologyApi.Step, 0, len(steps))
+	for i, step := range steps {
+		planned = append(planned, topologyApi.Step{
+			Index:  i,
+			Action: step.action,
+			Target: step.target,
+			Status: stepPlanned,
+		})
+	}
+	return planned
+}
+
+// Compares the desired document with the running emulation. The plan disconnects the links that are no longer wanted,
+// removes the components that are missing or changed, updates the links that are kept and applies what is left of the document.
+// A node changes when its machine or docker command changes, and a link is reconnected when its weight changes.
+func planReconcile(desired *topologyApi.Document) ([]applyStep, error) {
+	live, err := engine.app.Export()
+	if err != nil {
+		return nil, err
+	}
+	documentNames(&live)
+
+	components := make(map[string]liveComponent)
+	for _, node := range live.Nodes {
+		components[node.Name] = liveComponent{kind: nodeComponent, machineId: node.MachineId, dockerCmd: node.DockerCmd}
+	}
+	for _, bridge := range live.Bridges {
+		components[bridge.Name] = liveComponent{kind: bridgeComponent, machineId: bridge.MachineId}
+	}
+	for _, router := range live.Routers {
+		components[router.Name] = liveComponent{kind: routerComponent, machineId: router.MachineId}
+	}
+
+	kinds := make(map[string]string)
+	kept := make(map[string]bool)
+	additions := &topologyApi.Document{
+		Nodes:   make([]topologyApi.NodeSpec, 0),
+		Bridges: make([]topologyApi.ComponentSpec, 0),
+		Routers: make([]topologyApi.ComponentSpec, 0),
+		Links:   make([]topologyApi.LinkSpec, 0),
+	}
+
+	for _, router := range desired.Routers {
+		if err = addDocumentComponent(kinds, router.Name, routerComponent); err != nil {
+			return nil, err
+		}
+		if c, ok := components[router.Name]; ok && c.kind == routerComponent && c.machineId == router.MachineId {
+			kept[router.Name] = true
+		} else {
+			additions.Routers = append(additions.Routers, router)
+		}
+	}
+	for _, bridge := range desired.Bridges {
+		if err = addDocumentComponent(kinds, bridge.Name, bridgeComponent); err != nil {
+			return nil, err
+		}
+		if c, ok := components[bridge.Name]; ok && c.kind == bridgeComponent && c.machineId == bridge.MachineId {
+			kept[bridge.Name] = true
+		} else {
+			additions.Bridges = append(additions.Bridges, bridge)
+		}
+	}
+	for _, node := range desired.Nodes {
+		if err = addDocumentComponent(kinds, node.Name, nodeComponent); err != nil {
+			return nil, err
+		}
+		// Nodes registered without a command keep running
+		if c, ok := components[node.Name]; ok && c.kind == nodeComponent && c.machineId == node.MachineId &&
+			(len(c.dockerCmd) == 0 || slices.Equal(c.dockerCmd, node.DockerCmd)) {
+			kept[node.Name] = true
+		} else {
+			additions.Nodes = append(additions.Nodes, node)
+		}
+	}
+
+	liveLinks := make(map[[2]string]topologyApi.LinkSpec)
+	for _, link := range live.Links {
+		liveLinks[linkKey(link.From, link.To)] = link
+	}
+
+	steps := make([]applyStep, 0)
+	wanted := make(map[[2]string]bool)
+	changed := len(additions.Routers) > 0 || len(additions.Bridges) > 0 || len(additions.Nodes) > 0
+
+	for _, link := range desired.Links {
+		if _, ok := kinds[link.From]; !ok {
+			return nil, errors.New(link.From + " -> " + link.To + ": unknown component " + link.From)
+		}
+		if _, ok := kinds[link.To]; !ok {
+			return nil, errors.New(link.From + " -> " + link.To + ": unknown component " + link.To)
+		}
+		if _, err = linkStep(link, kinds, func(name string) string { return name }); err != nil {
+			return nil, errors.New(link.From + " -> " + link.To + ": " + err.Error())
+		}
+
+		key := linkKey(link.From, link.To)
+		current, ok := liveLinks[key]
+		if !ok || !kept[link.From] || !kept[link.To] {
+			additions.Links = append(additions.Links, link)
+			changed = true
+			continue
+		}
+
+		step, reconnect, err := updateLinkStep(link, current, kinds)
+		if err != nil {
+			return nil, errors.New(link.From + " -> " + link.To + ": " + err.Error())
+		}
+		if reconnect {
+			additions.Links = append(additions.Links, link)
+			changed = true
+			continue
+		}
+		wanted[key] = true
+		if step != nil {
+			steps = append(steps, *step)
+		}
+	}
+
+	// Links are disconnected before their components are removed, so remote machines release them as well
+	disconnects := make([]applyStep, 0)
+	for _, link := range live.Links {
+		if wanted[linkKey(link.From, link.To)] {
+			continue
+		}
+		disconnects = append(disconnects, disconnectStep(link, components[link.From].kind))
+		changed = true
+	}
+
+	removals := make([]applyStep, 0)
+	for _, kind := range []string{nodeComponent, bridgeComponent, routerComponent} {
+		for _, name := range liveNames(live, kind) {
+			if !kept[name] {
+				removals = append(removals, removeStep(name, kind))
+				changed = true
+			}
+		}
+	}
+
+	var propagate []string
+	if desired.Propagate && changed {
+		propagate = propagatedRouters(desired)
+	}
+	additions.Unpause = desired.Unpause
+
+	applied, err := planDocument(additions, propagate)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := make([]applyStep, 0, len(disconnects)+len(removals)+len(steps)+len(applied))
+	plan = append(plan, disconnects...)
+	plan = append(plan, removals...)
+	plan = append(plan, steps...)
+	plan = append(plan, applied...)
+	return plan, nil
+}
+
+// Exported nodes are named by their container id. Renames the nodes added by documents back to their name in the
+// document and forgets the names of nodes that are no longer running.
+func documentNames(live *topologyApi.Document) {
+	running := make(map[string]bool)
+	for _, node := range live.Nodes {
+		running[node.Name] = true
+	}
+	names := make(map[string]string)
+	for name, id := range documentNodes {
+		if running[id] {
+			names[id] = name
+		} else {
+			delete(documentNodes, name)
+		}
+	}
+
+	rename := func(id string) string {
+		if name, ok := names[id]; ok {
+			return name
+		}
+		return id
+	}
+	for i := range live.Nodes {
+		live.Nodes[i].Name = rename(live.Nodes[i].Name)
+	}
+	for i := range live.Links {
+		live.Links[i].From = rename(live.Links[i].From)
+		live.Links[i].To = rename(live.Links[i].To)
+	}
+}
+
+func linkKey(from string, to string) [2]string {
+	if to < from {
+		return [2]string{to, from}
+	}
+	return [2]string{from, to}
+}
+
+func liveNames(live topologyApi.Document, kind string) []string {
+	names := make([]string, 0)
+	switch kind {
+	case nodeComponent:
+		for _, node := range live.Nodes {
+			names = append(names, node.Name)
+		}
+	case bridgeComponent:
+		for _, bridge := range live.Bridges {
+			names = append(names, bridge.Name)
+		}
+	case routerComponent:
+		for _, router := range live.Routers {
+			names = append(names, router.Name)
+		}
+	}
+	return names
+}
+
+// Compares a desired link with the running one. Returns nil when nothing changed,
+// and reconnect when the link must be disconnected and connected again.
+func updateLinkStep(link topologyApi.LinkSpec, current topologyApi.LinkSpec, kinds map[string]string) (*applyStep, bool, error) {
+	up, down, err := parseLinkSpec(link)
+	if err != nil {
+		return nil, false, err
+	}
+	currentUp, currentDown := current.Upstream, current.Downstream
+	if current.From != link.From {
+		currentUp, currentDown = currentDown, currentUp
+	}
+	if up.Weight != current.Weight {
+		return nil, true, nil
+	}
+	if sameLinkDirection(up, currentUp) && sameLinkDirection(down, currentDown) {
+		return nil, false, nil
+	}
+
+	target := link.From + " -> " + link.To
+	switch kinds[link.From] {
+	case nodeComponent:
+		return &applyStep{
+			action: "updateNodeLink",
+			target: target,
+			run: func() (string, error) {
+				return "", engine.app.UpdateNodeLink(documentNodeId(link.From), up, down)
+			},
+		}, false, nil
+	case bridgeComponent:
+		return &applyStep{
+			action: "updateBridgeLink",
+			target: target,
+			run: func() (string, error) {
+				return "", engine.app.UpdateBridgeLink(link.From, up, down)
+			},
+		}, false, nil
+	default:
+		return &applyStep{
+			action: "updateRouterLink",
+			target: target,
+			run: func() (string, error) {
+				return "", engine.app.UpdateRouterLink(link.From, link.To, up, down)
+			},
+		}, false, nil
+	}
+}
+
+// Both directions are compared as exported, a desired direction without a seed keeps the current one
+func sameLinkDirection(props network.LinkProps, current *connectApi.LinkDirection) bool {
+	if current == nil {
+		return false
+	}
+	direction := application.ExportLinkDirection(props)
+	if direction.Seed == 0 {
+		direction.Seed = current.Seed
+	}
+	return reflect.DeepEqual(direction, current)
+}
+
+func disconnectStep(link topologyApi.LinkSpec, kind string) applyStep {
+	target := link.From + " -> " + link.To
+	switch kind {
+	case nodeComponent:
+		return applyStep{
+			action: "disconnectNode",
+			target: target,
+			run: func() (string, error) {
+				return "", engine.app.DisconnectNode(documentNodeId(link.From))
+			},
+		}
+	case bridgeComponent:
+		return applyStep{
+			action: "disconnectBridge",
+			target: target,
+			run: func() (string, error) {
+				return "", engine.app.DisconnectBridge(link.From)
+			},
+		}
+	default:
+		return applyStep{
+			action: "disconnectRouters",
+			target: target,
+			run: func() (string, error) {
+				return "", engine.app.DisconnectRouters(link.From, link.To)
+			},
+		}
+	}
+}
+
+func removeStep(name string, kind string) applyStep {
+	switch kind {
+	case nodeComponent:
+		return applyStep{
+			action: "removeNode",
+			target: name,
+			run: func() (string, error) {
+				if err := engine.app.RemoveNode(documentNodeId(name)); err != nil {
+					return "", err
+				}
+				delete(documentNodes, name)
+				return "", nil
+			},
+		}
+	case bridgeComponent:
+		return applyStep{
+			action: "removeBridge",
+			target: name,
+			run: func() (string, error) {
+				return "", engine.app.RemoveBridge(name)
+			},
+		}
+	default:
+		return applyStep{
+			action: "removeRouter",
+			target: name,
+			run: func() (string, error) {
+				return "", engine.app.RemoveRouter(name)
+			},
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package leader
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/David-Antunes/gone/api"
+	disconnectApi "github.com/David-Antunes/gone/api/Disconnect"
+	apiErrors "github.com/David-Antunes/gone/api/Errors"
+	opApi "github.com/David-Antunes/gone/api/Operations"
+	scheduleApi "github.com/David-Antunes/gone/api/Schedule"
+	updateApi "github.com/David-Antunes/gone/api/Update"
+	"github.com/David-Antunes/gone/internal/daemon"
+	"github.com/David-Antunes/gone/internal/scheduler"
+	"net/http"
+	"time"
+)
+
+func scheduleTimeline(w http.ResponseWriter, r *http.Request) {
+
+	req := &scheduleApi.ScheduleTimelineRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("scheduleTimeline:", err)
+		daemon.SendError(w, &scheduleApi.ScheduleTimelineResponse{
+			Id: "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	start, actions, err := parseTimeline(req)
+
+	if err != nil {
+		daemonLog.Println("scheduleTimeline:", err)
+		daemon.SendError(w, &scheduleApi.ScheduleTimelineResponse{
+			Id: "",
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	id := engine.scheduler.Schedule(start, actions)
+
+	daemon.SendResponse(w, &scheduleApi.ScheduleTimelineResponse{
+		Id:    id,
+		Error: apiErrors.Error{},
+	})
+	daemonLog.Println("scheduleTimeline:", "Scheduled", id, "with", len(actions), "actions")
+}
+
+func listTimelines(w http.ResponseWriter, r *http.Request) {
+
+	daemon.SendResponse(w, &scheduleApi.ListTimelinesResponse{
+		Timelines: engine.scheduler.List(),
+		Error:     apiErrors.Error{},
+	})
+}
+
+func inspectTimeline(w http.ResponseWriter, r *http.Request) {
+
+	req := &scheduleApi.InspectTimelineRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("inspectTimeline:", err)
+		daemon.SendError(w, &scheduleApi.InspectTimelineResponse{
+			Timeline: api.Timeline{},
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	timeline, ok := engine.scheduler.Get(req.Id)
+
+	if !ok {
+		daemonLog.Println("inspectTimeline:", "invalid timeline id", req.Id)
+		daemon.SendError(w, &scheduleApi.InspectTimelineResponse{
+			Timeline: api.Timeline{},
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  "invalid timeline id: " + req.Id,
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &scheduleApi.InspectTimelineResponse{
+		Timeline: timeline,
+		Error:    apiErrors.Error{},
+	})
+}
+
+func cancelTimeline(w http.ResponseWriter, r *http.Request) {
+
+	req := &scheduleApi.CancelTimelineRequest{}
+
+	if err := daemon.ParseRequest(r, req); err != nil {
+		daemonLog.Println("cancelTimeline:", err)
+		daemon.SendError(w, &scheduleApi.CancelTimelineResponse{
+			Id: req.Id,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	err := engine.scheduler.Cancel(req.Id)
+
+	if err != nil {
+		daemonLog.Println("cancelTimeline:", err)
+		daemon.SendError(w, &scheduleApi.CancelTimelineResponse{
+			Id: req.Id,
+			Error: apiErrors.Error{
+				ErrCode: 1,
+				ErrMsg:  err.Error(),
+			},
+		})
+		return
+	}
+
+	daemon.SendResponse(w, &scheduleApi.CancelTimelineResponse{
+		Id:    req.Id,
+		Error: apiErrors.Error{},
+	})
+	daemonLog.Println("cancelTimeline:", "Cancelled", req.Id)
+}
+
+// Validates every action before scheduling, so a timeline is either fully scheduled or rejected
+func parseTimeline(req *scheduleApi.ScheduleTimelineRequest) (time.Time, []scheduler.Action, error) {
+	start := time.Now()
+	if req.Start != "" {
+		var err error
+		start, err = time.Parse(time.RFC3339Nano, req.Start)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+	} else if req.DelayMs < 0 {
+		return time.Time{}, nil, errors.New("timeline delay can't be lower than 0 ms")
+	} else {
+		start = start.Add(time.Duration(req.DelayMs * float64(time.Millisecond)))
+	}
+
+	if len(req.Actions) == 0 {
+		return time.Time{}, nil, errors.New("timeline has no actions")
+	}
+
+	actions := make([]scheduler.Action, 0, len(req.Actions))
+	for _, action := range req.Actions {
+		a, err := parseTimelineAction(action)
+		if err != nil {
+			return time.Time{}, nil, errors.New(action.Action + ": " + err.Error())
+		}
+		actions = append(actions, a)
+	}
+	return start, actions, nil
+}
+
+// Converts the request of an action into the operation executed by the endpoint with the same name
+func parseTimelineAction(action api.TimelineAction) (scheduler.Action, error) {
+	if action.OffsetMs < 0 {
+		return scheduler.Action{}, errors.New("offset can't be lower than 0 ms")
+	}
+
+	var run func() error
+	switch action.Action {
+	case "updateNodeLink":
+		req := &updateApi.UpdateNodeLinkRequest{}
+		if err := json.Unmarshal(action.Request, req); err != nil {
+			return scheduler.Action{}, err
+		}
+		up, down, err := daemon.ParseBiLinkProps(req.Latency, req.Bandwidth, req.Jitter, req.DropRate, 0, req.Loss, req.Impairments, req.Distribution, req.Queue, req.Trace, req.Upstream, req.Downstream)
+		if err != nil {
+			return scheduler.Action{}, err
+		}
+		run = func() error { return engine.app.UpdateNodeLink(req.Node, up, down) }
+
+	case "updateBridgeLink":
+		req := &updateApi.UpdateBridgeLinkRequest{}
+		if err := json.Unmarshal(action.Request, req); err != nil {
+			return scheduler.Action{}, err
+		}
+		up, down, err := daemon.ParseBiLinkProps(req.Latency, req.Bandwidth, req.Jitter, req.DropRate, 0, req.Loss, req.Impairments, req.Distribution, req.Queue, req.Trace, req.Upstream, req.Downstream)
+		if err != nil {
+			return scheduler.Action{}, err
+		}
+		run = func() error { return engine.app.UpdateBridgeLink(req.Bridge, up, down) }
+
+	case "updateRouterLink":
+		req := &updateApi.UpdateRouterLinkRequest{}
+		if err := json.Unmarshal(action.Request, req); err != nil {
+			return scheduler.Action{}, err
+		}
+		up, down, err := daemon.ParseBiLinkProps(req.Latency, req.Bandwidth, req.Jitter, req.DropRate, 0, req.Loss, req.Impairments, req.Distribution, req.Queue, req.Trace, req.Upstream, req.Downstream)
+		if err != nil {
+			return scheduler.Action{}, err
+		}
+		run = func() error { return engine.app.UpdateRouterLink(req.Router1, req.Router2, up, down) }
+
+	case "disruptNode", "stopDisruptNode":
+		req := &opApi.DisruptNodeRequest{}
+		if err := json.Unmarshal(action.Request, req); err != nil {
+			return scheduler.Action{}, err
+		}
+		duration, err := daemon.ParseDuration(req.DurationMs)
+		if err != nil {
+			return scheduler.Action{}, err
+		}
+		if _, err = daemon.ParseDirection(req.Direction); err != nil {
+			return scheduler.Action{}, err
+		}
+		if action.Action == "disruptNode" {
+			run = func() error { return engine.app.DisruptNode(req.Node, req.Direction, duration) }
+		} else {
+			run = func() error { return engine.app.StopDisruptNode(req.Node, req.Direction) }
+		}
+
+	case "disruptBridge", "stopDisruptBridge":
+		req := &opApi.DisruptBridgeRequest{}
+		if err := json.Unmarshal(action.Request, req); err != nil {
+			return scheduler.Action{}, err
+		}
+		duration, err := daemon.ParseDuration(req.DurationMs)
+		if err != nil {
+			return scheduler.Action{}, err
+		}
+		if _, err = daemon.ParseDirection(req.Direction); err != nil {
+			return scheduler.Action{}, err
+		}
+		if action.Action == "disruptBridge" {
+			run = func() error { return engine.app.DisruptBridge(req.Bridge, req.Direction, duration) }
+		} else {
+			run = func() error { return engine.app.StopDisruptBridge(req.Bridge, req.Direction) }
+		}
+
+	case "disruptRouters", "stopDisruptRouters":
+		req := &opApi.DisruptRoutersRequest{}
+		if err := json.Unmarshal(action.Request, req); err != nil {
+			return scheduler.Action{}, err
+		}
+		duration, err := daemon.ParseDuration(req.DurationMs)
+		if err != nil {
+			return scheduler.Action{}, err
+		}
+		if _, err = daemon.ParseDirection(req.Direction); err != nil {
+			return scheduler.Action{}, err
+		}
+		if action.Action == "disruptRouters" {
+			run = func() error { return engine.app.DisruptRouters(req.Router1, req.Router2, req.Direction, duration) }
+		} else {
+			run = func() error { return engine.app.StopDisruptRouters(req.Router1, req.Router2, req.Direction) }
+		}
+
+	case "pause":
+		req := &opApi.PauseRequest{}
+		if err := json.Unmarshal(action.Request, req); err != nil {
+			return scheduler.Action{}, err
+		}
+		run = func() error { return engine.app.Pause(req.Id, req.All) }
+
+	case "unpause":
+		req := &opApi.UnpauseRequest{}
+		if err := json.Unmarshal(action.Request, req); err != nil {
+			return scheduler.Action{}, err
+		}
+		run = func() error { return engine.app.Unpause(req.Id, req.All) }
+
+	case "partition":
+		req := &opApi.PartitionRequest{}
+		if err := json.Unmarshal(action.Request, req); err != nil {
+			return scheduler.Action{}, err
+		}
+		run = func() error {
+			_, err := engine.app.Partition(req.Groups)
+			return err
+		}
+
+	case "heal":
+		req := &opApi.HealRequest{}
+		if err := json.Unmarshal(action.Request, req); err != nil {
+			return scheduler.Action{}, err
+		}
+		run = func() error { return engine.app.Heal(req.Id, req.All) }
+
+	case "disconnectNode":
+		req := &disconnectApi.DisconnectNodeRequest{}
+		if err := json.Unmarshal(action.Request, req); err != nil {
+			return scheduler.Action{}, err
+		}
+		run = func() error { return engine.app.DisconnectNode(req.Name) }
+
+	case "disconnectBridge":
+		req := &disconnectApi.DisconnectBridgeRequest{}
+		if err := json.Unmarshal(action.Request, req); err != nil {
+			return scheduler.Action{}, err
+		}
+		run = func() error { return engine.app.DisconnectBridge(req.Name) }
+
+	case "disconnectRouters":
+		req := &disconnectApi.DisconnectRoutersRequest{}
+		if err := json.Unmarshal(action.Request, req); err != nil {
+			return scheduler.Action{}, err
+		}
+		run = func() error { return engine.app.DisconnectRouters(req.First, req.Second) }
+
+	default:
+		return scheduler.Action{}, errors.New("unknown timeline action")
+	}
+
+	return scheduler.Action{
+		Offset: time.Duration(action.OffsetMs * float64(time.Millisecond)),
+		Name:   action.Action,
+		Run:    run,
+	}, nil
+}
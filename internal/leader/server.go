@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"github.com/David-Antunes/gone/internal"
 	"github.com/David-Antunes/gone/internal/application"
+	"github.com/David-Antunes/gone/internal/chaos"
 	"github.com/David-Antunes/gone/internal/cluster"
+	"github.com/David-Antunes/gone/internal/dashboard"
+	"github.com/David-Antunes/gone/internal/metrics"
+	"github.com/David-Antunes/gone/internal/scheduler"
 	"log"
 	"net"
 	"net/http"
@@ -17,6 +21,8 @@ type server struct {
 	socket     net.Listener
 	app        *application.Leader
 	cd         *cluster.ClusterDaemon
+	scheduler  *scheduler.Scheduler
+	chaos      *chaos.Engine
 	profiling  bool
 }
 
@@ -46,10 +52,13 @@ func createDaemon(app *application.Leader, cd *cluster.ClusterDaemon, ipAddr str
 		socket:     socket,
 		app:        app,
 		cd:         cd,
+		scheduler:  scheduler.NewScheduler(),
+		chaos:      chaos.NewEngine(),
 		profiling:  false,
 	}
 
 	m.HandleFunc("/ping", ping)
+	m.HandleFunc("/metrics", exportMetrics)
 
 	m.HandleFunc("/registerNode", registerNode)
 	m.HandleFunc("/clearNode", clearNode)
@@ -65,6 +74,9 @@ func createDaemon(app *application.Leader, cd *cluster.ClusterDaemon, ipAddr str
 	m.HandleFunc("/inspectNode", inspectNode)
 	m.HandleFunc("/inspectBridge", inspectBridge)
 	m.HandleFunc("/inspectRouter", inspectRouter)
+	m.HandleFunc("/inspectLink", inspectLink)
+	m.HandleFunc("/inspectLinkRemote", inspectLinkRemote)
+	m.HandleFunc("/linkDirectionsRemote", linkDirectionsRemote)
 
 	m.HandleFunc("/removeNode", removeNode)
 	m.HandleFunc("/removeBridge", removeBridge)
@@ -75,6 +87,11 @@ func createDaemon(app *application.Leader, cd *cluster.ClusterDaemon, ipAddr str
 	m.HandleFunc("/disconnectRouters", disconnectRouters)
 	m.HandleFunc("/localDisconnect", localDisconnect)
 
+	m.HandleFunc("/updateNodeLink", updateNodeLink)
+	m.HandleFunc("/updateBridgeLink", updateBridgeLink)
+	m.HandleFunc("/updateRouterLink", updateRouterLink)
+	m.HandleFunc("/updateRouterLinkRemote", updateRouterLinkRemote)
+
 	m.HandleFunc("/weights", routerWeights)
 	m.HandleFunc("/trade", trade)
 
@@ -99,6 +116,24 @@ func createDaemon(app *application.Leader, cd *cluster.ClusterDaemon, ipAddr str
 	m.HandleFunc("/stopDisruptNode", stopDisruptNode)
 	m.HandleFunc("/stopDisruptBridge", stopDisruptBridge)
 	m.HandleFunc("/stopDisruptRouters", stopDisruptRouters)
+	m.HandleFunc("/listDisruptions", listDisruptions)
+
+	m.HandleFunc("/partition", partition)
+	m.HandleFunc("/heal", heal)
+	m.HandleFunc("/listPartitions", listPartitions)
+
+	m.HandleFunc("/flapNode", flapNode)
+	m.HandleFunc("/flapBridge", flapBridge)
+	m.HandleFunc("/flapRouters", flapRouters)
+	m.HandleFunc("/stopFlap", stopFlap)
+	m.HandleFunc("/listFlaps", listFlaps)
+
+	m.HandleFunc("/rampNode", rampNode)
+	m.HandleFunc("/rampBridge", rampBridge)
+	m.HandleFunc("/rampRouters", rampRouters)
+	m.HandleFunc("/stopRamp", stopRamp)
+	m.HandleFunc("/reverseRamp", reverseRamp)
+	m.HandleFunc("/listRamps", listRamps)
 
 	m.HandleFunc("/stopBridge", stopBridge)
 	m.HandleFunc("/stopRouter", stopRouter)
@@ -107,8 +142,29 @@ func createDaemon(app *application.Leader, cd *cluster.ClusterDaemon, ipAddr str
 
 	m.HandleFunc("/pause", pause)
 	m.HandleFunc("/unpause", unpause)
+	m.HandleFunc("/crashNode", crashNode)
+	m.HandleFunc("/restartNode", restartNode)
+
+	m.HandleFunc("/scheduleTimeline", scheduleTimeline)
+	m.HandleFunc("/listTimelines", listTimelines)
+	m.HandleFunc("/inspectTimeline", inspectTimeline)
+	m.HandleFunc("/cancelTimeline", cancelTimeline)
+
+	m.HandleFunc("/startChaos", startChaos)
+	m.HandleFunc("/stopChaos", stopChaos)
+	m.HandleFunc("/listChaos", listChaos)
+	m.HandleFunc("/inspectChaos", inspectChaos)
+
+	m.HandleFunc("/apply", apply)
+	m.HandleFunc("/export", export)
+	m.HandleFunc("/reconcile", reconcile)
+	m.HandleFunc("/generate", generate)
+	m.HandleFunc("/import", importTopology)
+	m.HandleFunc("/graph", graph)
+	m.Handle(dashboard.Path, dashboard.Handler())
 
 	m.HandleFunc("/registerMachine", cd.RegisterMachine)
+	m.HandleFunc("/listMachines", cd.ListMachines)
 	m.HandleFunc("/profile", s.profile)
 	m.HandleFunc("/stopProfile", s.stopProfile)
 	m.HandleFunc("/localQuery", localQuery)
@@ -127,6 +183,13 @@ func ping(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+func exportMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", metrics.ContentType)
+	if err := engine.app.Metrics().Write(w); err != nil {
+		daemonLog.Println("metrics:", err)
+	}
+}
+
 func (server *server) profile(w http.ResponseWriter, r *http.Request) {
 
 	if !server.profiling {
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+const (
+	counterType = "counter"
+	gaugeType   = "gauge"
+)
+
+type sample struct {
+	labels string
+	value  float64
+}
+
+type family struct {
+	name       string
+	help       string
+	metricType string
+	samples    []sample
+}
+
+// Registry collects samples and writes them in the Prometheus text exposition format
+type Registry struct {
+	families map[string]*family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+	}
+}
+
+// Labels are given as name, value pairs
+func (reg *Registry) Counter(name string, help string, value uint64, labels ...string) {
+	reg.add(name, help, counterType, float64(value), labels)
+}
+
+func (reg *Registry) Gauge(name string, help string, value float64, labels ...string) {
+	reg.add(name, help, gaugeType, value, labels)
+}
+
+func (reg *Registry) add(name string, help string, metricType string, value float64, labels []string) {
+	f, ok := reg.families[name]
+	if !ok {
+		f = &family{
+			name:       name,
+			help:       help,
+			metricType: metricType,
+			samples:    make([]sample, 0),
+		}
+		reg.families[name] = f
+	}
+	f.samples = append(f.samples, sample{
+		labels: formatLabels(labels),
+		value:  value,
+	})
+}
+
+func (reg *Registry) Write(w io.Writer) error {
+	names := make([]string, 0, len(reg.families))
+	for name := range reg.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f := reg.families[name]
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapeHelp(f.help), f.name, f.metricType); err != nil {
+			return err
+		}
+		for _, s := range f.samples {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", f.name, s.labels, strconv.FormatFloat(s.value, 'g', -1, 64)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func formatLabels(labels []string) string {
+	if len(labels) < 2 {
+		return ""
+	}
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, labels[i]+"=\""+escapeLabel(labels[i+1])+"\"")
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelReplacer = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n")
+var helpReplacer = strings.NewReplacer("\\", "\\\\", "\n", "\\n")
+
+func escapeLabel(value string) string {
+	return labelReplacer.Replace(value)
+}
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
+
+func Bool(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
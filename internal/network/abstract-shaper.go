@@ -1,13 +1,22 @@
 package network
 
-import "time"
+import (
+	"github.com/David-Antunes/gone/internal"
+	"golang.org/x/time/rate"
+	"time"
+)
 
 type Shaper interface {
 	Start()
 	SetDelay(delay *Delay)
 	GetDelay() *Delay
+	GetProps() LinkProps
+	SetProps(props LinkProps)
+	AdjustProps(change func(props *LinkProps))
 	Disrupt() bool
 	StopDisrupt() bool
+	IsDisrupted() bool
+	GetStats() LinkStats
 	Stop()
 	Close()
 	Pause()
@@ -28,3 +37,20 @@ func (d *DynamicDelay) GetReceiveLatency() time.Duration {
 func (d *DynamicDelay) GetTransmitLatency() time.Duration {
 	return d.TransmitDelay.Value
 }
+
+// Rate at which the shaper limiter hands out PacketSize tokens for a given bandwidth in bytes per second
+func bandwidthLimit(bandwidth int) rate.Limit {
+	aux := internal.PacketSize / float64(bandwidth)
+	return rate.Every(time.Duration(float64(time.Second) * aux))
+}
+
+// Applies change to the properties of a shaper, following the new bandwidth unless a trace drives the limiter.
+// The trace keeps its position, unlike when the properties are replaced.
+func adjustProps(shared *sharedProps, limiter *rate.Limiter, change func(props *LinkProps)) {
+	shared.update(func(props *LinkProps) {
+		change(props)
+		if !props.Trace.Enabled() {
+			limiter.SetLimit(bandwidthLimit(props.Bandwidth))
+		}
+	})
+}
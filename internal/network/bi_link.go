@@ -1,5 +1,11 @@
 package network
 
+// Directions of a BiLink
+const (
+	Upstream   = "upstream"
+	Downstream = "downstream"
+)
+
 type BiLink struct {
 	Left  *Link
 	Right *Link
@@ -34,15 +40,47 @@ func (link *BiLink) Stop() {
 	link.Left.Stop()
 	link.Right.Stop()
 }
-func (link *BiLink) Disrupt() bool {
-	link.Left.Disrupt()
-	return link.Right.Disrupt()
+func (link *BiLink) Disrupt(owner string) bool {
+	left := link.Left.Disrupt(owner)
+	right := link.Right.Disrupt(owner)
+	return left || right
+}
+
+func (link *BiLink) StopDisrupt(owner string) bool {
+	left := link.Left.StopDisrupt(owner)
+	right := link.Right.StopDisrupt(owner)
+	return left || right
+}
+
+// Disrupts a single direction of the link, or both when direction is empty.
+// Upstream is the Left link and Downstream the Right link.
+func (link *BiLink) DisruptDirection(direction string, owner string) bool {
+	switch direction {
+	case Upstream:
+		return link.Left.Disrupt(owner)
+	case Downstream:
+		return link.Right.Disrupt(owner)
+	default:
+		return link.Disrupt(owner)
+	}
+}
+
+func (link *BiLink) StopDisruptDirection(direction string, owner string) bool {
+	switch direction {
+	case Upstream:
+		return link.Left.StopDisrupt(owner)
+	case Downstream:
+		return link.Right.StopDisrupt(owner)
+	default:
+		return link.StopDisrupt(owner)
+	}
 }
 
-func (link *BiLink) StopDisrupt() bool {
-	link.Left.StopDisrupt()
-	return link.Right.StopDisrupt()
+func (link *BiLink) UpdateProps(left LinkProps, right LinkProps) {
+	link.Left.UpdateProps(left)
+	link.Right.UpdateProps(right)
 }
+
 func (link *BiLink) Close() {
 	link.Left.Close()
 	link.Right.Close()
@@ -57,3 +95,15 @@ func (link *BiLink) Unpause() {
 	link.Left.Unpause()
 	link.Right.Unpause()
 }
+
+// Links of a direction of link, both links when direction is empty
+func (link *BiLink) Directions(direction string) []*Link {
+	switch direction {
+	case Upstream:
+		return []*Link{link.Left}
+	case Downstream:
+		return []*Link{link.Right}
+	default:
+		return []*Link{link.Left, link.Right}
+	}
+}
@@ -18,9 +18,11 @@ type Bridge struct {
 	queue           chan *xdp.Frame
 	ctx             chan struct{}
 	disrupted       disruptLogic
+	counters        *componentCounters
+	partitions      *Partitions
 }
 
-func CreateBridge() *Bridge {
+func CreateBridge(partitions *Partitions) *Bridge {
 	return &Bridge{
 		RWMutex:         sync.RWMutex{},
 		channels:        make(map[string]chan *xdp.Frame),
@@ -34,6 +36,8 @@ func CreateBridge() *Bridge {
 			disrupted: false,
 			ctx:       make(chan struct{}, 1),
 		},
+		counters:   &componentCounters{},
+		partitions: partitions,
 	}
 }
 
@@ -41,6 +45,14 @@ func (bridge *Bridge) Gateway() chan *xdp.Frame {
 	return bridge.gateway
 }
 
+func (bridge *Bridge) GetStats() ComponentStats {
+	return bridge.counters.stats(len(bridge.queue))
+}
+
+func (bridge *Bridge) IsDisrupted() bool {
+	return bridge.disrupted.disrupted
+}
+
 func (bridge *Bridge) Incoming() chan *xdp.Frame {
 	return bridge.incomingChannel
 }
@@ -138,6 +150,7 @@ func (bridge *Bridge) null() {
 		case <-bridge.disrupted.ctx:
 			return
 		case <-bridge.incomingChannel:
+			bridge.counters.disruptedDrops.Add(1)
 			continue
 		}
 	}
@@ -163,20 +176,33 @@ func (bridge *Bridge) send() {
 		case frame := <-bridge.queue:
 			if bytes.Equal([]byte(frame.MacDestination), internal.BroadcastAddr) {
 				bridge.RLock()
-				for _, channel := range bridge.channels {
-					if len(channel) < internal.QueueSize {
+				for mac, channel := range bridge.channels {
+					if bridge.partitions.Blocks(frame.GetMacOrigin(), mac) {
+						bridge.counters.partitionDrops.Add(1)
+					} else if len(channel) < internal.QueueSize {
 						channel <- frame
+					} else {
+						bridge.counters.queueDrops.Add(1)
 					}
 				}
 				bridge.RUnlock()
+				bridge.counters.forward(frame)
+				continue
+			}
+			if bridge.partitions.Blocks(frame.GetMacOrigin(), frame.GetMacDestination()) {
+				bridge.counters.partitionDrops.Add(1)
 				continue
 			}
 			bridge.RLock()
 			if channel, ok := bridge.channels[frame.GetMacDestination()]; ok && len(channel) < internal.QueueSize {
 				channel <- frame
+				bridge.counters.forward(frame)
 			} else {
 				if len(bridge.gateway) < internal.QueueSize {
 					bridge.gateway <- frame
+					bridge.counters.forward(frame)
+				} else {
+					bridge.counters.queueDrops.Add(1)
 				}
 			}
 			bridge.RUnlock()
@@ -0,0 +1,44 @@
+package network
+
+import (
+	"github.com/David-Antunes/gone-proxy/xdp"
+	"sync/atomic"
+)
+
+// Counters of a bridge or router
+type ComponentStats struct {
+	Frames         uint64
+	Bytes          uint64
+	QueueDrops     uint64
+	DisruptedDrops uint64
+	PartitionDrops uint64
+	UnknownFrames  uint64
+	QueueDepth     int
+}
+
+type componentCounters struct {
+	frames         atomic.Uint64
+	bytes          atomic.Uint64
+	queueDrops     atomic.Uint64
+	disruptedDrops atomic.Uint64
+	partitionDrops atomic.Uint64
+	// Frames whose destination is unknown and are handed over to the routing logic
+	unknownFrames atomic.Uint64
+}
+
+func (counters *componentCounters) forward(frame *xdp.Frame) {
+	counters.frames.Add(1)
+	counters.bytes.Add(uint64(frame.FrameSize))
+}
+
+func (counters *componentCounters) stats(queueDepth int) ComponentStats {
+	return ComponentStats{
+		Frames:         counters.frames.Load(),
+		Bytes:          counters.bytes.Load(),
+		QueueDrops:     counters.queueDrops.Load(),
+		DisruptedDrops: counters.disruptedDrops.Load(),
+		PartitionDrops: counters.partitionDrops.Load(),
+		UnknownFrames:  counters.unknownFrames.Load(),
+		QueueDepth:     queueDepth,
+	}
+}
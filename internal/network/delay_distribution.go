@@ -0,0 +1,84 @@
+package network
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	NormalDistribution       = "normal"
+	UniformDistribution      = "uniform"
+	ParetoDistribution       = "pareto"
+	ParetoNormalDistribution = "paretonormal"
+	TableDistribution        = "table"
+)
+
+// Same scale used by the netem .dist files
+const DistributionTableScale = 8192
+
+// Shape of the pareto distribution, as used by netem
+const paretoAlpha = 3.0
+
+// Delay distribution of a link. Samples are centered on the link latency and scaled by its jitter.
+// An empty Type is a normal distribution.
+type DelayDistribution struct {
+	Type string
+	// Correlation between consecutive samples, between 0 and 1
+	Correlation float64
+	// Normalized samples of an empirical distribution, only used by TableDistribution
+	Table []float64
+}
+
+// Returns a sample with mean 0 and standard deviation 1
+func (dist *DelayDistribution) sample(rng *rand.Rand) float64 {
+	switch dist.Type {
+	case UniformDistribution:
+		// Uniform distribution between -sqrt(3) and sqrt(3) has a standard deviation of 1
+		return (rng.Float64()*2 - 1) * math.Sqrt(3)
+	case ParetoDistribution:
+		return pareto(rng)
+	case ParetoNormalDistribution:
+		return 0.25*pareto(rng) + 0.75*rng.NormFloat64()
+	case TableDistribution:
+		if len(dist.Table) == 0 {
+			return 0
+		}
+		return dist.Table[rng.Intn(len(dist.Table))]
+	default:
+		return rng.NormFloat64()
+	}
+}
+
+// Pareto sample shifted and scaled to have mean 0 and standard deviation 1
+func pareto(rng *rand.Rand) float64 {
+	mean := paretoAlpha / (paretoAlpha - 1)
+	std := math.Sqrt(paretoAlpha/(paretoAlpha-2)) / (paretoAlpha - 1)
+	x := 1 / math.Pow(1-rng.Float64(), 1/paretoAlpha)
+	return (x - mean) / std
+}
+
+// Delay distribution state of a single link, used to correlate consecutive samples
+type jitterState struct {
+	last    float64
+	sampled bool
+}
+
+func (state *jitterState) poll(props *LinkProps, rng *rand.Rand) time.Duration {
+	if props.Jitter == 0 {
+		return props.Latency
+	}
+	dist := &props.Distribution
+	x := dist.sample(rng)
+	if state.sampled {
+		x = dist.Correlation*state.last + (1-dist.Correlation)*x
+	}
+	state.last = x
+	state.sampled = true
+
+	delay := props.Latency + time.Duration(x*props.Jitter*float64(time.Millisecond))
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
@@ -0,0 +1,158 @@
+package network
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Flap states
+const (
+	FlapUp       = "up"
+	FlapDown     = "down"
+	FlapFinished = "finished"
+	FlapStopped  = "stopped"
+)
+
+// Timings of a flapping link. When UpMax or DownMax are greater than Up or Down,
+// each period is drawn uniformly between both values. Cycles set to 0 flap until stopped.
+type FlapProps struct {
+	Up      time.Duration
+	UpMax   time.Duration
+	Down    time.Duration
+	DownMax time.Duration
+	Cycles  int
+	// Seed of the random periods, 0 derives it from the emulation seed
+	Seed int64
+}
+
+// Sets the seed of props, if it has none, to the seed of the flap with the given id
+func (props FlapProps) Seeded(id string) FlapProps {
+	if props.Seed == 0 {
+		props.Seed = LinkSeed(id)
+	}
+	return props
+}
+
+func (props FlapProps) upTime(rng *rand.Rand) time.Duration {
+	return randomPeriod(props.Up, props.UpMax, rng)
+}
+
+func (props FlapProps) downTime(rng *rand.Rand) time.Duration {
+	return randomPeriod(props.Down, props.DownMax, rng)
+}
+
+func randomPeriod(min time.Duration, max time.Duration, rng *rand.Rand) time.Duration {
+	if max <= min {
+		return min
+	}
+	return min + time.Duration(rng.Int63n(int64(max-min)))
+}
+
+// Repeatedly disrupts and restores a direction of a link. Each cycle takes the link down and then brings it back up,
+// so the link is always up once the flap finishes or is stopped. The flap owns its disruptions by its id.
+type Flap struct {
+	sync.Mutex
+	id        string
+	link      *BiLink
+	direction string
+	props     FlapProps
+	state     string
+	completed int
+	rng       *rand.Rand
+	ctx       chan struct{}
+}
+
+func StartFlap(id string, link *BiLink, direction string, props FlapProps) *Flap {
+	flap := &Flap{
+		Mutex:     sync.Mutex{},
+		id:        id,
+		link:      link,
+		direction: direction,
+		props:     props,
+		state:     FlapUp,
+		completed: 0,
+		rng:       rand.New(rand.NewSource(props.Seed)),
+		ctx:       make(chan struct{}),
+	}
+	go flap.run()
+	return flap
+}
+
+func (flap *Flap) run() {
+	for flap.props.Cycles == 0 || flap.completed < flap.props.Cycles {
+		if !flap.transition(FlapDown) || !flap.wait(flap.props.downTime(flap.rng)) {
+			return
+		}
+		if !flap.transition(FlapUp) || !flap.wait(flap.props.upTime(flap.rng)) {
+			return
+		}
+	}
+	flap.transition(FlapFinished)
+}
+
+// Moves the link to a new state unless the flap was stopped in the meantime
+func (flap *Flap) transition(state string) bool {
+	flap.Lock()
+	defer flap.Unlock()
+	if !flap.runningLocked() {
+		return false
+	}
+	switch state {
+	case FlapDown:
+		flap.link.DisruptDirection(flap.direction, flap.id)
+	case FlapUp:
+		flap.link.StopDisruptDirection(flap.direction, flap.id)
+		flap.completed++
+	}
+	flap.state = state
+	return true
+}
+
+// Returns false when the flap is stopped while waiting
+func (flap *Flap) wait(period time.Duration) bool {
+	timer := time.NewTimer(period)
+	select {
+	case <-flap.ctx:
+		timer.Stop()
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Stops the flap and brings the link back up
+func (flap *Flap) Stop() bool {
+	flap.Lock()
+	defer flap.Unlock()
+	if !flap.runningLocked() {
+		return false
+	}
+	close(flap.ctx)
+	if flap.state == FlapDown {
+		flap.link.StopDisruptDirection(flap.direction, flap.id)
+	}
+	flap.state = FlapStopped
+	return true
+}
+
+func (flap *Flap) Running() bool {
+	flap.Lock()
+	defer flap.Unlock()
+	return flap.runningLocked()
+}
+
+func (flap *Flap) runningLocked() bool {
+	return flap.state == FlapUp || flap.state == FlapDown
+}
+
+// Current state and number of completed cycles
+func (flap *Flap) Status() (string, int) {
+	flap.Lock()
+	defer flap.Unlock()
+	return flap.state, flap.completed
+}
+
+func (flap *Flap) GetProps() FlapProps {
+	return flap.props
+}
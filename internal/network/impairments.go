@@ -0,0 +1,65 @@
+package network
+
+import (
+	"github.com/David-Antunes/gone-proxy/xdp"
+	"math/rand"
+)
+
+// Size of the Ethernet header, which is never corrupted
+const ethernetHeaderSize = 14
+
+func (props *LinkProps) PollDuplicate(rng *rand.Rand) bool {
+	return props.Duplicate > 0 && rng.Float64() < props.Duplicate
+}
+
+func (props *LinkProps) PollCorrupt(rng *rand.Rand) bool {
+	return props.Corrupt > 0 && rng.Float64() < props.Corrupt
+}
+
+// Reorder state of a single link. Like netem, only one in every ReorderGap frames can be reordered.
+type reorderState struct {
+	count int
+}
+
+func (state *reorderState) poll(props *LinkProps, rng *rand.Rand) bool {
+	if props.Reorder == 0 {
+		return false
+	}
+	if state.count < props.ReorderGap-1 || rng.Float64() >= props.Reorder {
+		state.count++
+		return false
+	}
+	state.count = 0
+	return true
+}
+
+// Corrupts and duplicates frame according to props. Returns the frame to queue and its duplicate,
+// which is nil unless the frame was duplicated.
+func impair(frame *xdp.Frame, props *LinkProps, rng *rand.Rand) (*xdp.Frame, *xdp.Frame) {
+	if props.PollCorrupt(rng) {
+		frame = corruptFrame(frame, rng)
+	}
+	if props.PollDuplicate(rng) {
+		return frame, cloneFrame(frame)
+	}
+	return frame, nil
+}
+
+func cloneFrame(frame *xdp.Frame) *xdp.Frame {
+	payload := make([]byte, len(frame.FramePointer))
+	copy(payload, frame.FramePointer)
+	return xdp.NewFrame(payload, frame.FrameSize, frame.Time, frame.MacOrigin, frame.MacDestination)
+}
+
+// Returns a copy of frame with a random bit of the payload flipped.
+// The original frame is left untouched since it may be shared with other links.
+func corruptFrame(frame *xdp.Frame, rng *rand.Rand) *xdp.Frame {
+	size := min(frame.FrameSize, len(frame.FramePointer))
+	if size <= ethernetHeaderSize {
+		return frame
+	}
+	corrupted := cloneFrame(frame)
+	bit := rng.Intn((size - ethernetHeaderSize) * 8)
+	corrupted.FramePointer[ethernetHeaderSize+bit/8] ^= 1 << (bit % 8)
+	return corrupted
+}
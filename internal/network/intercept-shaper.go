@@ -12,19 +12,55 @@ import (
 type InterceptShaper struct {
 	running   bool
 	queue     chan *xdp.Frame
+	front     chan *xdp.Frame
 	incoming  chan *xdp.Frame
 	outgoing  chan *xdp.Frame
-	props     LinkProps
+	props     *sharedProps
+	loss      lossState
+	counters  *linkCounters
+	jitter    jitterState
+	reorder   reorderState
+	buffer    *linkBuffer
 	delay     *Delay
 	limiter   *rate.Limiter
+	replay    *traceReplay
 	tokenSize int
-	ctx       chan struct{}
+	routines  routines
 	rt        *redirect_traffic.InterceptComponent
-	disrupted disruptLogic
+	disrupted bool
 }
 
 func (shaper *InterceptShaper) GetProps() LinkProps {
-	return shaper.props
+	return *shaper.props.load()
+}
+
+func (shaper *InterceptShaper) SetProps(props LinkProps) {
+	shaper.props.store(props)
+	shaper.limiter.SetLimit(bandwidthLimit(props.Bandwidth))
+	shaper.replay.set(props.Trace, shaper.applyTrace)
+}
+
+func (shaper *InterceptShaper) AdjustProps(change func(props *LinkProps)) {
+	adjustProps(shaper.props, shaper.limiter, change)
+}
+
+func (shaper *InterceptShaper) applyTrace(point TracePoint) {
+	applyTracePoint(shaper.props, shaper.limiter, point)
+}
+
+func (shaper *InterceptShaper) GetQueueStats() QueueStats {
+	return shaper.buffer.Stats()
+}
+
+func (shaper *InterceptShaper) GetStats() LinkStats {
+	queue := shaper.buffer.Stats()
+	stats := shaper.counters.stats(max(queue.Packets, len(shaper.queue)))
+	stats.QueueDrops += queue.TailDrops + queue.AQMDrops
+	return stats
+}
+
+func (shaper *InterceptShaper) IsDisrupted() bool {
+	return shaper.disrupted
 }
 
 func (shaper *InterceptShaper) GetIncoming() chan *xdp.Frame {
@@ -51,63 +87,61 @@ func NewInterceptShaper(incoming chan *xdp.Frame, outgoing chan *xdp.Frame, prop
 	newTime := float64(time.Second) * aux
 
 	return &InterceptShaper{
+		counters:  &linkCounters{},
 		running:   false,
 		queue:     make(chan *xdp.Frame, internal.QueueSize),
+		front:     make(chan *xdp.Frame, internal.QueueSize),
+		buffer:    newLinkBuffer(),
 		incoming:  incoming,
 		outgoing:  outgoing,
-		props:     props,
+		props:     newSharedProps(props),
 		delay:     &Delay{0},
 		limiter:   rate.NewLimiter(rate.Every(time.Duration(newTime)), 1),
+		replay:    newTraceReplay(props.Trace),
 		tokenSize: internal.PacketSize,
-		ctx:       make(chan struct{}, 2),
 		rt:        rt,
-		disrupted: disruptLogic{
-			disrupted: false,
-			ctx:       make(chan struct{}, 1),
-		},
+		disrupted: false,
 	}
 }
 
 func (shaper *InterceptShaper) Stop() {
 	if shaper.running {
 		shaper.running = false
-		shaper.ctx <- struct{}{}
-		shaper.ctx <- struct{}{}
+		shaper.routines.halt()
 	}
 }
 
 func (shaper *InterceptShaper) Start() {
+	shaper.replay.start(shaper.applyTrace)
 	if !shaper.running {
 		shaper.running = true
-		go shaper.receive()
-		go shaper.send()
+		shaper.routines.run(shaper.receive, shaper.send)
 	}
 }
 
 func (shaper *InterceptShaper) Disrupt() bool {
-	if !shaper.disrupted.disrupted {
-		shaper.disrupted.disrupted = true
+	if !shaper.disrupted {
+		shaper.disrupted = true
 		shaper.Stop()
-		go shaper.null()
-
-		// Clear queue for requests
-		go func() {
-			go shaper.send()
-			time.Sleep(time.Second)
-			shaper.ctx <- struct{}{}
-		}()
+		shaper.routines.run(shaper.null, shaper.drain)
 		return true
 	} else {
 		return false
 	}
 }
 
-func (shaper *InterceptShaper) null() {
+// Clear queue for requests. Stopping the disruption stops the drain before the routines start again.
+func (shaper *InterceptShaper) drain(stop chan struct{}) {
+	shaper.send(stopAfter(stop, time.Second))
+}
+
+func (shaper *InterceptShaper) null(stop chan struct{}) {
 	for {
 		select {
-		case <-shaper.disrupted.ctx:
+		case <-stop:
 			return
 		case <-shaper.incoming:
+			shaper.counters.disruptedDrops.Add(1)
 			continue
 		}
 	}
@@ -115,107 +149,141 @@ func (shaper *InterceptShaper) null() {
 
 func (shaper *InterceptShaper) StopDisrupt() bool {
 
-	if shaper.disrupted.disrupted {
-		shaper.disrupted.disrupted = false
-		shaper.disrupted.ctx <- struct{}{}
+	if shaper.disrupted {
+		shaper.disrupted = false
+		shaper.routines.halt()
 		shaper.Start()
 		return true
 	}
 	return false
 }
 
-func (shaper *InterceptShaper) receive() {
+func (shaper *InterceptShaper) receive(stop chan struct{}) {
 
 	for {
 		select {
-		case <-shaper.ctx:
+		case <-stop:
 			return
 
 		case frame := <-shaper.incoming:
-			shaper.rt.Socket.GetOutgoing() <- frame
+			sendFrame(shaper.rt.Socket.GetOutgoing(), frame, stop)
 
 		case frame := <-shaper.rt.Socket.GetIncoming():
-
-			frame.Time = frame.Time.Add(shaper.props.Latency)
-			frame.Time = frame.Time.Add(shaper.props.PollJitter())
+			props := shaper.props.load()
+			old := frame.Time
+			frame.Time = frame.Time.Add(shaper.jitter.poll(props, props.rng.receive))
 			frame.Time = frame.Time.Add(-shaper.delay.Value)
-			if shaper.props.PollDropRate() {
+			if shaper.loss.poll(props, props.rng.receive) {
+				shaper.counters.lossDrops.Add(1)
+				continue
+			}
+			frame, duplicate := impair(frame, props, props.rng.receive)
+			if duplicate != nil {
+				shaper.enqueue(duplicate, props, stop)
+			}
+			if shaper.reorder.poll(props, props.rng.receive) {
+				frame.Time = old.Add(-shaper.delay.Value)
+				sendFrame(shaper.front, frame, stop)
 				continue
 			}
-			//if len(shaper.queue) < internal.QueueSize {
-			shaper.queue <- frame
-			//}
+			shaper.enqueue(frame, props, stop)
 		}
 	}
 }
 
-func (shaper *InterceptShaper) send() {
+func (shaper *InterceptShaper) send(stop chan struct{}) {
 
 	for {
 		select {
-		case <-shaper.ctx:
+		case <-stop:
 			return
 
+		case frame := <-shaper.front:
+			shaper.transmit(frame)
+
 		case frame := <-shaper.queue:
-			var r *rate.Reservation
-			if shaper.tokenSize < frame.FrameSize {
-				r = shaper.limiter.Reserve()
-				if !r.OK() {
-					fmt.Println("Something went wrong")
-				}
-				shaper.tokenSize = shaper.tokenSize - frame.FrameSize + internal.PacketSize
-
-				frame.Time = frame.Time.Add(r.Delay())
-			} else {
-				shaper.tokenSize = shaper.tokenSize - frame.FrameSize
+			// Reordered frames overtake every frame still waiting in the queue
+			for len(shaper.front) > 0 {
+				shaper.transmit(<-shaper.front)
 			}
+			shaper.buffer.pop(frame)
+			shaper.transmit(frame)
+		}
+	}
+}
 
-			//go func() {
-			time.Sleep(time.Until(frame.Time))
-			if len(shaper.outgoing) < internal.ComponentQueueSize {
-				shaper.outgoing <- frame
-			}
-			//}()
+// Queues frame unless a limit of the link buffer was reached
+func (shaper *InterceptShaper) enqueue(frame *xdp.Frame, props *LinkProps, stop chan struct{}) {
+	if shaper.buffer.push(frame, props) && !sendFrame(shaper.queue, frame, stop) {
+		shaper.buffer.pop(frame)
+	}
+}
 
+func (shaper *InterceptShaper) transmit(frame *xdp.Frame) {
+	var r *rate.Reservation
+	if shaper.tokenSize < frame.FrameSize {
+		r = shaper.limiter.Reserve()
+		if !r.OK() {
+			fmt.Println("Something went wrong")
 		}
+		shaper.tokenSize = shaper.tokenSize - frame.FrameSize + internal.PacketSize
+
+		frame.Time = frame.Time.Add(r.Delay())
+	} else {
+		shaper.tokenSize = shaper.tokenSize - frame.FrameSize
 	}
+
+	//go func() {
+	time.Sleep(time.Until(frame.Time))
+	if len(shaper.outgoing) < internal.ComponentQueueSize {
+		shaper.outgoing <- frame
+		shaper.counters.forward(frame)
+	} else {
+		shaper.counters.queueDrops.Add(1)
+	}
+	//}()
 }
 
 func (shaper *InterceptShaper) ConvertToNetworkShaper() *NetworkShaper {
 	if shaper.StopDisrupt() {
 		shaper.Stop()
 	}
-	return &NetworkShaper{
+	converted := &NetworkShaper{
 		running:   shaper.running,
 		queue:     shaper.queue,
+		front:     shaper.front,
+		buffer:    shaper.buffer,
 		incoming:  shaper.incoming,
 		outgoing:  shaper.outgoing,
 		props:     shaper.props,
+		loss:      shaper.loss,
+		counters:  shaper.counters,
+		jitter:    shaper.jitter,
+		reorder:   shaper.reorder,
 		limiter:   shaper.limiter,
+		replay:    shaper.replay,
 		delay:     shaper.delay,
 		tokenSize: shaper.tokenSize,
-		ctx:       shaper.ctx,
 	}
+	converted.replay.retarget(converted.applyTrace)
+	return converted
 }
+
+// Stopping a disruption restarts the routines, so it has to happen before they are stopped
 func (shaper *InterceptShaper) Close() {
-	shaper.Stop()
 	shaper.StopDisrupt()
+	shaper.Stop()
+	shaper.replay.stop()
 }
 
 func (shaper *InterceptShaper) Pause() {
-	if shaper.running {
-		shaper.ctx <- struct{}{}
-		shaper.ctx <- struct{}{}
-	} else if shaper.disrupted.disrupted {
-		shaper.disrupted.ctx <- struct{}{}
-	}
+	shaper.routines.halt()
 }
 
 func (shaper *InterceptShaper) Unpause() {
 	if shaper.running {
-		go shaper.receive()
-		go shaper.send()
-	} else if shaper.disrupted.disrupted {
-		go shaper.null()
+		shaper.routines.run(shaper.receive, shaper.send)
+	} else if shaper.disrupted {
+		shaper.routines.run(shaper.null)
 	}
 }
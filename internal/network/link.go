@@ -2,13 +2,21 @@ package network
 
 import (
 	"github.com/David-Antunes/gone-proxy/xdp"
+	"sync"
 )
 
+// Owner of the disruptions of the operations API
+const ManualDisruption = "manual"
+
+// The lifecycle of the shaper goes through the link, so it is never started, paused or disrupted concurrently.
+// A link stays disrupted until every owner of a disruption stops it.
 type Link struct {
+	sync.Mutex
 	originChan      chan *xdp.Frame
 	destinationChan chan *xdp.Frame
 	props           LinkProps
 	shaper          Shaper
+	disruptions     map[string]bool
 }
 
 func CreateLink(originChan chan *xdp.Frame, destinationChan chan *xdp.Frame, props LinkProps) *Link {
@@ -16,7 +24,9 @@ func CreateLink(originChan chan *xdp.Frame, destinationChan chan *xdp.Frame, pro
 		originChan:      originChan,
 		destinationChan: destinationChan,
 		props:           props,
-		shaper:          CreateNetworkShaper(originChan, destinationChan, props)}
+		shaper:          CreateNetworkShaper(originChan, destinationChan, props),
+		disruptions:     make(map[string]bool),
+	}
 }
 
 func CreateNullLink(originChan chan *xdp.Frame) *Link {
@@ -25,24 +35,38 @@ func CreateNullLink(originChan chan *xdp.Frame) *Link {
 		destinationChan: nil,
 		props:           LinkProps{},
 		shaper:          CreateNullShaper(originChan),
+		disruptions:     make(map[string]bool),
 	}
 }
 
+// A disrupted link starts once its last disruption stops
 func (link *Link) Start() {
-	link.shaper.Start()
+	link.Lock()
+	defer link.Unlock()
+	if len(link.disruptions) == 0 {
+		link.shaper.Start()
+	}
 }
 func (link *Link) Stop() {
+	link.Lock()
+	defer link.Unlock()
 	link.shaper.Stop()
 }
 
 func (link *Link) Pause() {
+	link.Lock()
+	defer link.Unlock()
 	link.shaper.Pause()
 }
 func (link *Link) Unpause() {
+	link.Lock()
+	defer link.Unlock()
 	link.shaper.Unpause()
 }
 
 func (link *Link) Close() {
+	link.Lock()
+	defer link.Unlock()
 	link.shaper.Close()
 }
 
@@ -55,10 +79,29 @@ func (link *Link) GetDestinationChan() chan *xdp.Frame {
 }
 
 func (link *Link) GetProps() LinkProps {
+	link.Lock()
+	defer link.Unlock()
 	return link.props
 }
 
+// Queue counters of the link. Null shapers don't keep them.
+func (link *Link) GetQueueStats() QueueStats {
+	switch shaper := link.GetShaper().(type) {
+	case *NetworkShaper:
+		return shaper.GetQueueStats()
+	case *SniffShaper:
+		return shaper.GetQueueStats()
+	case *InterceptShaper:
+		return shaper.GetQueueStats()
+	case *RemoteShaper:
+		return shaper.GetQueueStats()
+	}
+	return QueueStats{}
+}
+
 func (link *Link) GetShaper() Shaper {
+	link.Lock()
+	defer link.Unlock()
 	return link.shaper
 }
 
@@ -73,19 +116,74 @@ func (link *Link) SetDestinationChan(destinationChan chan *xdp.Frame) *Link {
 }
 
 func (link *Link) SetProps(props LinkProps) *Link {
+	link.Lock()
+	defer link.Unlock()
 	link.props = props
 	return link
 }
 
+// Applies new properties to the link and to the shaper currently running on it
+// Links keep their seed unless props sets a new one.
+func (link *Link) UpdateProps(props LinkProps) {
+	link.Lock()
+	defer link.Unlock()
+	if props.Seed == 0 {
+		props.Seed = link.props.Seed
+	}
+	link.props = props
+	link.shaper.SetProps(props)
+}
+
+// Changes some of the properties of the link and of the shaper currently running on it, keeping the others
+func (link *Link) AdjustProps(change func(props *LinkProps)) {
+	link.Lock()
+	defer link.Unlock()
+	change(&link.props)
+	link.shaper.AdjustProps(change)
+}
+
+// Converting a shaper stops its disruption, so the disruptions of the link are applied to the new shaper.
+// They are dropped when it can't be disrupted.
 func (link *Link) SetShaper(shaper Shaper) *Link {
+	link.Lock()
+	defer link.Unlock()
 	link.shaper = shaper
+	if len(link.disruptions) > 0 && !shaper.Disrupt() {
+		clear(link.disruptions)
+	}
 	return link
 }
 
-func (link *Link) Disrupt() bool {
-	return link.shaper.Disrupt()
+// Disrupts the link on behalf of owner. Returns false if owner already disrupts it or the shaper can't be disrupted.
+func (link *Link) Disrupt(owner string) bool {
+	link.Lock()
+	defer link.Unlock()
+	if link.disruptions[owner] {
+		return false
+	}
+	if len(link.disruptions) == 0 && !link.shaper.Disrupt() {
+		return false
+	}
+	link.disruptions[owner] = true
+	return true
+}
+
+// Stops the disruption of owner. The link only comes back up once no other owner disrupts it.
+func (link *Link) StopDisrupt(owner string) bool {
+	link.Lock()
+	defer link.Unlock()
+	if !link.disruptions[owner] {
+		return false
+	}
+	delete(link.disruptions, owner)
+	if len(link.disruptions) == 0 {
+		link.shaper.StopDisrupt()
+	}
+	return true
 }
 
-func (link *Link) StopDisrupt() bool {
-	return link.shaper.StopDisrupt()
+func (link *Link) IsDisrupted() bool {
+	link.Lock()
+	defer link.Unlock()
+	return len(link.disruptions) > 0
 }
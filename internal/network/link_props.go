@@ -1,31 +1,122 @@
 package network
 
 import (
+	"hash/fnv"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// Seed of the emulation. Links without a seed of their own derive it from this seed and their id.
+var emulationSeed atomic.Int64
+
+func SetEmulationSeed(seed int64) {
+	emulationSeed.Store(seed)
+}
+
+func GetEmulationSeed() int64 {
+	return emulationSeed.Load()
+}
+
+// Seed of the link with the given id, so the same emulation seed always produces the same links
+func LinkSeed(id string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return GetEmulationSeed() + int64(h.Sum64())
+}
+
 type LinkProps struct {
-	Latency   time.Duration
-	FLatency  float64
-	Bandwidth int
-	Jitter    float64
-	DropRate  float64
-	Weight    int
+	Latency      time.Duration
+	FLatency     float64
+	Bandwidth    int
+	Jitter       float64
+	DropRate     float64
+	Weight       int
+	Loss         GilbertElliott
+	Distribution DelayDistribution
+	Queue        QueueProps
+	// Probability of a frame skipping the link latency and overtaking the queued frames
+	Reorder    float64
+	ReorderGap int
+	Duplicate  float64
+	Corrupt    float64
+	// Replaces the fixed bandwidth when set
+	Trace *Trace
+	// Seed of the random number generator of the link, 0 derives it from the emulation seed
+	Seed int64
+	rng  *linkRand
 }
 
-func (props *LinkProps) PollJitter() time.Duration {
-	if props.Jitter == 0 {
-		return props.Latency
-	} else {
-		random := rand.NormFloat64()
-		results := (random * float64(props.Latency)) + float64(time.Duration(props.Jitter)*time.Millisecond)
-		duration := time.Duration(results)
+// Random number generators of a link. A rand.Rand can't be shared between goroutines,
+// so the receive and send routines of a shaper each draw from their own generator.
+type linkRand struct {
+	receive *rand.Rand
+	send    *rand.Rand
+}
 
-		//fmt.Println(random, results, duration)
-		return duration
+func newLinkRand(seed int64) *linkRand {
+	return &linkRand{
+		receive: rand.New(rand.NewSource(seed)),
+		// Complementing the seed keeps both sequences apart
+		send: rand.New(rand.NewSource(^seed)),
 	}
 }
-func (props *LinkProps) PollDropRate() bool {
-	return rand.Float64() < props.DropRate
+
+// Sets the seed of props, if it has none, to the seed of the link with the given id
+func (props LinkProps) Seeded(id string) LinkProps {
+	if props.Seed == 0 {
+		props.Seed = LinkSeed(id)
+		props.rng = nil
+	}
+	return props
+}
+
+// Keeps the generators of old when props use the same seed, so updating a link doesn't restart its random sequences.
+// A new seed gets new generators.
+func (props *LinkProps) inheritRand(old LinkProps) {
+	if props.Seed == old.Seed {
+		props.rng = old.rng
+	} else {
+		props.rng = newLinkRand(props.Seed)
+	}
+}
+
+// Link properties shared by a shaper, its routines and the shapers it is converted to.
+// Routines read an immutable snapshot, and every change stores a new copy, so props are never written while read.
+type sharedProps struct {
+	sync.Mutex
+	current atomic.Pointer[LinkProps]
+}
+
+func newSharedProps(props LinkProps) *sharedProps {
+	shared := &sharedProps{}
+	props.rng = newLinkRand(props.Seed)
+	shared.current.Store(&props)
+	return shared
+}
+
+// Current snapshot of the properties. It must not be modified.
+func (shared *sharedProps) load() *LinkProps {
+	return shared.current.Load()
+}
+
+func (shared *sharedProps) store(props LinkProps) {
+	shared.Lock()
+	defer shared.Unlock()
+	props.inheritRand(*shared.load())
+	shared.current.Store(&props)
+}
+
+// Applies change to a copy of the current properties and stores it
+func (shared *sharedProps) update(change func(props *LinkProps)) {
+	shared.Lock()
+	defer shared.Unlock()
+	props := *shared.load()
+	change(&props)
+	shared.current.Store(&props)
+}
+
+func (props *LinkProps) PollDropRate(rng *rand.Rand) bool {
+	return rng.Float64() < props.DropRate
 }
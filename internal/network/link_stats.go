@@ -0,0 +1,64 @@
+package network
+
+import (
+	"github.com/David-Antunes/gone-proxy/xdp"
+	"sync/atomic"
+)
+
+const (
+	NetworkShaperType   = "network"
+	SniffShaperType     = "sniff"
+	InterceptShaperType = "intercept"
+	RemoteShaperType    = "remote"
+	NullShaperType      = "null"
+)
+
+type LinkStats struct {
+	Frames         uint64
+	Bytes          uint64
+	LossDrops      uint64
+	QueueDrops     uint64
+	DisruptedDrops uint64
+	QueueDepth     int
+}
+
+// Counters of a link. They are kept when the link shaper is converted to sniff or intercept a link.
+type linkCounters struct {
+	frames     atomic.Uint64
+	bytes      atomic.Uint64
+	lossDrops  atomic.Uint64
+	queueDrops atomic.Uint64
+	// Frames discarded while the link is disrupted
+	disruptedDrops atomic.Uint64
+}
+
+func (counters *linkCounters) forward(frame *xdp.Frame) {
+	counters.frames.Add(1)
+	counters.bytes.Add(uint64(frame.FrameSize))
+}
+
+func (counters *linkCounters) stats(queueDepth int) LinkStats {
+	return LinkStats{
+		Frames:         counters.frames.Load(),
+		Bytes:          counters.bytes.Load(),
+		LossDrops:      counters.lossDrops.Load(),
+		QueueDrops:     counters.queueDrops.Load(),
+		DisruptedDrops: counters.disruptedDrops.Load(),
+		QueueDepth:     queueDepth,
+	}
+}
+
+func ShaperType(shaper Shaper) string {
+	switch shaper.(type) {
+	case *NetworkShaper:
+		return NetworkShaperType
+	case *SniffShaper:
+		return SniffShaperType
+	case *InterceptShaper:
+		return InterceptShaperType
+	case *RemoteShaper:
+		return RemoteShaperType
+	default:
+		return NullShaperType
+	}
+}
@@ -0,0 +1,38 @@
+package network
+
+import "math/rand"
+
+// Two-state (good/bad) Gilbert-Elliott loss model.
+// The model is disabled while both transition probabilities are 0.
+type GilbertElliott struct {
+	GoodToBad float64
+	BadToGood float64
+	GoodLoss  float64
+	BadLoss   float64
+}
+
+func (model *GilbertElliott) Enabled() bool {
+	return model.GoodToBad != 0 || model.BadToGood != 0
+}
+
+// State of the loss model of a single link
+type lossState struct {
+	bad bool
+}
+
+// Moves the channel to its next state and polls the loss probability of that state.
+// Falls back to the independent drop rate of props when the Gilbert-Elliott model is disabled.
+func (state *lossState) poll(props *LinkProps, rng *rand.Rand) bool {
+	if !props.Loss.Enabled() {
+		return props.PollDropRate(rng)
+	}
+	if state.bad {
+		state.bad = rng.Float64() >= props.Loss.BadToGood
+	} else {
+		state.bad = rng.Float64() < props.Loss.GoodToBad
+	}
+	if state.bad {
+		return rng.Float64() < props.Loss.BadLoss
+	}
+	return rng.Float64() < props.Loss.GoodLoss
+}
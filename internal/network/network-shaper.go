@@ -13,18 +13,72 @@ import (
 type NetworkShaper struct {
 	running   bool
 	queue     chan *xdp.Frame
+	front     chan *xdp.Frame
 	incoming  chan *xdp.Frame
 	outgoing  chan *xdp.Frame
 	delay     *Delay
-	props     LinkProps
+	props     *sharedProps
+	loss      lossState
+	counters  *linkCounters
+	jitter    jitterState
+	reorder   reorderState
+	buffer    *linkBuffer
 	limiter   *rate.Limiter
+	replay    *traceReplay
 	tokenSize int
-	ctx       chan struct{}
-	disrupted disruptLogic
+	routines  routines
+	disrupted bool
 }
 
 func (shaper *NetworkShaper) GetProps() LinkProps {
-	return shaper.props
+	return *shaper.props.load()
+}
+
+// Replaces the link properties of a running shaper without dropping queued frames.
+// The routines are only restarted when switching between receiveNoLatency and receiveLatency.
+func (shaper *NetworkShaper) SetProps(props LinkProps) {
+	latency := shaper.hasLatency()
+	shaper.props.store(props)
+	shaper.limiter.SetLimit(bandwidthLimit(props.Bandwidth))
+	shaper.replay.set(props.Trace, shaper.applyTrace)
+	if shaper.running && latency != shaper.hasLatency() {
+		shaper.routines.restart(shaper.receive(), shaper.send)
+	}
+}
+
+// Changes some of the link properties without replacing them.
+// The routines are only restarted when switching between receiveNoLatency and receiveLatency.
+func (shaper *NetworkShaper) AdjustProps(change func(props *LinkProps)) {
+	latency := shaper.hasLatency()
+	adjustProps(shaper.props, shaper.limiter, change)
+	if shaper.running && latency != shaper.hasLatency() {
+		shaper.routines.restart(shaper.receive(), shaper.send)
+	}
+}
+
+func (shaper *NetworkShaper) applyTrace(point TracePoint) {
+	applyTracePoint(shaper.props, shaper.limiter, point)
+}
+
+func (shaper *NetworkShaper) GetQueueStats() QueueStats {
+	return shaper.buffer.Stats()
+}
+
+func (shaper *NetworkShaper) GetStats() LinkStats {
+	queue := shaper.buffer.Stats()
+	stats := shaper.counters.stats(max(queue.Packets, len(shaper.queue)))
+	stats.QueueDrops += queue.TailDrops + queue.AQMDrops
+	return stats
+}
+
+func (shaper *NetworkShaper) IsDisrupted() bool {
+	return shaper.disrupted
+}
+
+func (shaper *NetworkShaper) hasLatency() bool {
+	props := shaper.props.load()
+	return !(props.Latency == 0 && props.Jitter == 0.0 && props.DropRate == 0.0 && !props.Loss.Enabled() &&
+		props.Reorder == 0.0 && props.Duplicate == 0.0 && props.Corrupt == 0.0 && !props.Trace.varies())
 }
 
 func (shaper *NetworkShaper) GetIncoming() chan *xdp.Frame {
@@ -47,65 +101,67 @@ func CreateNetworkShaper(incoming chan *xdp.Frame, outgoing chan *xdp.Frame, pro
 	aux := internal.PacketSize / float64(props.Bandwidth)
 	newTime := float64(time.Second) * aux
 	return &NetworkShaper{
+		counters:  &linkCounters{},
 		running:   false,
 		queue:     make(chan *xdp.Frame, internal.QueueSize),
+		front:     make(chan *xdp.Frame, internal.QueueSize),
 		incoming:  incoming,
 		outgoing:  outgoing,
 		delay:     &Delay{0},
-		props:     props,
+		props:     newSharedProps(props),
+		buffer:    newLinkBuffer(),
 		limiter:   rate.NewLimiter(rate.Every(time.Duration(newTime)), 1),
+		replay:    newTraceReplay(props.Trace),
 		tokenSize: internal.PacketSize,
-		ctx:       make(chan struct{}, 2),
-		disrupted: disruptLogic{
-			disrupted: false,
-			ctx:       make(chan struct{}, 1),
-		},
+		disrupted: false,
 	}
 }
 func (shaper *NetworkShaper) Stop() {
 	if shaper.running {
 		shaper.running = false
-		shaper.ctx <- struct{}{}
-		shaper.ctx <- struct{}{}
+		shaper.routines.halt()
 	}
 }
 
 func (shaper *NetworkShaper) Start() {
+	shaper.replay.start(shaper.applyTrace)
 	if !shaper.running {
 		shaper.running = true
-		go shaper.send()
-		if shaper.props.Latency == 0 && shaper.props.Jitter == 0.0 && shaper.props.DropRate == 0.0 {
-			go shaper.receiveNoLatency()
-		} else {
-			go shaper.receiveLatency()
-		}
+		shaper.routines.run(shaper.receive(), shaper.send)
 	}
 }
 
+// Receive routine for the current link properties
+func (shaper *NetworkShaper) receive() func(stop chan struct{}) {
+	if shaper.hasLatency() {
+		return shaper.receiveLatency
+	}
+	return shaper.receiveNoLatency
+}
+
 func (shaper *NetworkShaper) Disrupt() bool {
-	if !shaper.disrupted.disrupted {
-		shaper.disrupted.disrupted = true
+	if !shaper.disrupted {
+		shaper.disrupted = true
 		shaper.Stop()
-		go shaper.null()
-
-		// Clear queue for requests
-		go func() {
-			go shaper.send()
-			time.Sleep(time.Second)
-			shaper.ctx <- struct{}{}
-		}()
+		shaper.routines.run(shaper.null, shaper.drain)
 		return true
 	} else {
 		return false
 	}
 }
 
-func (shaper *NetworkShaper) null() {
+// Clear queue for requests. Stopping the disruption stops the drain before the routines start again.
+func (shaper *NetworkShaper) drain(stop chan struct{}) {
+	shaper.send(stopAfter(stop, time.Second))
+}
+
+func (shaper *NetworkShaper) null(stop chan struct{}) {
 	for {
 		select {
-		case <-shaper.disrupted.ctx:
+		case <-stop:
 			return
 		case <-shaper.incoming:
+			shaper.counters.disruptedDrops.Add(1)
 			continue
 		}
 	}
@@ -113,85 +169,154 @@ func (shaper *NetworkShaper) null() {
 
 func (shaper *NetworkShaper) StopDisrupt() bool {
 
-	if shaper.disrupted.disrupted {
-		shaper.disrupted.disrupted = false
-		shaper.disrupted.ctx <- struct{}{}
+	if shaper.disrupted {
+		shaper.disrupted = false
+		shaper.routines.halt()
 		shaper.Start()
 		return true
 	}
 	return false
 }
 
-func (shaper *NetworkShaper) receiveLatency() {
+func (shaper *NetworkShaper) receiveLatency(stop chan struct{}) {
 
 	for {
 
 		select {
-		case <-shaper.ctx:
+		case <-stop:
 			return
 
 		case frame := <-shaper.incoming:
+			props := shaper.props.load()
 			//fmt.Println("before:", frame.Time)
 			//frame.Time = frame.Time.Add(shaper.props.Latency)
 			old := frame.Time
-			jitter := shaper.props.PollJitter()
+			jitter := shaper.jitter.poll(props, props.rng.receive)
 			frame.Time = frame.Time.Add(jitter)
 			fmt.Println(old, frame.Time, jitter)
 
 			frame.Time = frame.Time.Add(-shaper.delay.Value)
 			//fmt.Println("after:", frame.Time, shaper.props.Latency)
-			if shaper.props.PollDropRate() {
+			if shaper.loss.poll(props, props.rng.receive) {
+				shaper.counters.lossDrops.Add(1)
+				continue
+			}
+			frame, duplicate := impair(frame, props, props.rng.receive)
+			if duplicate != nil {
+				shaper.enqueue(duplicate, props, stop)
+			}
+			if shaper.reorder.poll(props, props.rng.receive) {
+				frame.Time = old.Add(-shaper.delay.Value)
+				sendFrame(shaper.front, frame, stop)
 				continue
 			}
-			//if len(shaper.queue) < internal.QueueSize {
-			shaper.queue <- frame
-			//}
+			shaper.enqueue(frame, props, stop)
 		}
 	}
 }
-func (shaper *NetworkShaper) receiveNoLatency() {
+func (shaper *NetworkShaper) receiveNoLatency(stop chan struct{}) {
 
 	for {
 
 		select {
-		case <-shaper.ctx:
+		case <-stop:
 			return
 
 		case frame := <-shaper.incoming:
 			frame.Time = frame.Time.Add(-shaper.delay.Value)
-			//if len(shaper.queue) < internal.QueueSize {
-			shaper.queue <- frame
-			//}
+			shaper.enqueue(frame, shaper.props.load(), stop)
 		}
 	}
 }
 
-func (shaper *NetworkShaper) send() {
+// Queues frame unless a limit of the link buffer was reached
+func (shaper *NetworkShaper) enqueue(frame *xdp.Frame, props *LinkProps, stop chan struct{}) {
+	if shaper.buffer.push(frame, props) && !sendFrame(shaper.queue, frame, stop) {
+		shaper.buffer.pop(frame)
+	}
+}
+
+func (shaper *NetworkShaper) send(stop chan struct{}) {
 
 	for {
+		if shaper.buffer.queued > 0 && !stopped(stop) {
+			shaper.sendFlows(stop)
+		}
 		select {
-		case <-shaper.ctx:
+		case <-stop:
 			return
 
+		case frame := <-shaper.front:
+			shaper.transmit(frame)
+
 		case frame := <-shaper.queue:
-			var r *rate.Reservation
-			if shaper.tokenSize < frame.FrameSize {
-				r = shaper.limiter.Reserve()
-				if !r.OK() {
-					fmt.Println("Something went wrong")
-				}
-				shaper.tokenSize = shaper.tokenSize - frame.FrameSize + internal.PacketSize
-
-				frame.Time = frame.Time.Add(r.Delay())
-			} else {
-				shaper.tokenSize = shaper.tokenSize - frame.FrameSize
+			// Reordered frames overtake every frame still waiting in the queue
+			for len(shaper.front) > 0 {
+				shaper.transmit(<-shaper.front)
+			}
+			props := shaper.props.load()
+			if props.Queue.Discipline == FQCoDel || shaper.buffer.queued > 0 {
+				shaper.buffer.enqueueFlow(frame)
+				shaper.sendFlows(stop)
+				continue
 			}
-			//go func() {
-			time.Sleep(time.Until(frame.Time))
-			if len(shaper.outgoing) < internal.ComponentQueueSize {
-				shaper.outgoing <- frame
+			if frame = shaper.buffer.manage(frame, &props.Queue, props.rng.send, time.Now()); frame != nil {
+				shaper.buffer.pop(frame)
+				shaper.transmit(frame)
 			}
-			//}()
+		}
+	}
+}
+
+// Moves the queued frames into the FQ-CoDel flows and transmits them until every flow is empty.
+// Returns early when the shaper is being stopped so the send routine can exit.
+func (shaper *NetworkShaper) sendFlows(stop chan struct{}) {
+	for !stopped(stop) {
+		for len(shaper.queue) > 0 {
+			shaper.buffer.enqueueFlow(<-shaper.queue)
+		}
+		frame := shaper.buffer.dequeueFlow(&shaper.props.load().Queue, time.Now())
+		if frame == nil {
+			return
+		}
+		shaper.buffer.pop(frame)
+		shaper.transmit(frame)
+	}
+}
+
+func (shaper *NetworkShaper) transmit(frame *xdp.Frame) {
+	var r *rate.Reservation
+	if shaper.tokenSize < frame.FrameSize {
+		r = shaper.limiter.Reserve()
+		if !r.OK() {
+			fmt.Println("Something went wrong")
+		}
+		shaper.tokenSize = shaper.tokenSize - frame.FrameSize + internal.PacketSize
+
+		frame.Time = frame.Time.Add(r.Delay())
+	} else {
+		shaper.tokenSize = shaper.tokenSize - frame.FrameSize
+	}
+	//go func() {
+	time.Sleep(time.Until(frame.Time))
+	if len(shaper.outgoing) < internal.ComponentQueueSize {
+		shaper.outgoing <- frame
+		shaper.counters.forward(frame)
+	} else {
+		shaper.buffer.tailDrop()
+	}
+	//}()
+}
+
+// Moves the frames left in the FQ-CoDel flows to the front of the link, so a converted shaper,
+// which doesn't use the flows, still transmits them first
+func (shaper *NetworkShaper) flushFlows() {
+	for _, frame := range shaper.buffer.flushFlows() {
+		shaper.buffer.pop(frame)
+		select {
+		case shaper.front <- frame:
+		default:
+			shaper.buffer.tailDrop()
 		}
 	}
 }
@@ -200,63 +325,73 @@ func (shaper *NetworkShaper) ConvertToSniffShaper(rt *redirect_traffic.SniffComp
 	if shaper.StopDisrupt() {
 		shaper.Stop()
 	}
-	return &SniffShaper{
+	shaper.flushFlows()
+	converted := &SniffShaper{
 		running:   shaper.running,
 		queue:     shaper.queue,
+		front:     shaper.front,
+		buffer:    shaper.buffer,
 		incoming:  shaper.incoming,
 		outgoing:  shaper.outgoing,
 		props:     shaper.props,
+		loss:      shaper.loss,
+		counters:  shaper.counters,
+		jitter:    shaper.jitter,
+		reorder:   shaper.reorder,
 		delay:     shaper.delay,
 		limiter:   shaper.limiter,
+		replay:    shaper.replay,
 		tokenSize: shaper.tokenSize,
 		rt:        rt,
-		ctx:       shaper.ctx,
 		disrupted: shaper.disrupted,
 	}
+	converted.replay.retarget(converted.applyTrace)
+	return converted
 }
 
 func (shaper *NetworkShaper) ConvertToInterceptShaper(rt *redirect_traffic.InterceptComponent) *InterceptShaper {
 	if shaper.StopDisrupt() {
 		shaper.Stop()
 	}
-	return &InterceptShaper{
+	shaper.flushFlows()
+	converted := &InterceptShaper{
 		running:   shaper.running,
 		queue:     shaper.queue,
+		front:     shaper.front,
+		buffer:    shaper.buffer,
 		incoming:  shaper.incoming,
 		outgoing:  shaper.outgoing,
 		props:     shaper.props,
+		loss:      shaper.loss,
+		counters:  shaper.counters,
+		jitter:    shaper.jitter,
+		reorder:   shaper.reorder,
 		delay:     shaper.delay,
 		limiter:   shaper.limiter,
+		replay:    shaper.replay,
 		tokenSize: shaper.tokenSize,
 		rt:        rt,
-		ctx:       shaper.ctx,
 		disrupted: shaper.disrupted,
 	}
+	converted.replay.retarget(converted.applyTrace)
+	return converted
 }
 
+// Stopping a disruption restarts the routines, so it has to happen before they are stopped
 func (shaper *NetworkShaper) Close() {
-	shaper.Stop()
 	shaper.StopDisrupt()
+	shaper.Stop()
+	shaper.replay.stop()
 }
 
 func (shaper *NetworkShaper) Pause() {
-	if shaper.running {
-		shaper.ctx <- struct{}{}
-		shaper.ctx <- struct{}{}
-	} else if shaper.disrupted.disrupted {
-		shaper.disrupted.ctx <- struct{}{}
-	}
+	shaper.routines.halt()
 }
 
 func (shaper *NetworkShaper) Unpause() {
 	if shaper.running {
-		if shaper.props.Latency == 0 && shaper.props.Jitter == 0.0 && shaper.props.DropRate == 0.0 {
-			go shaper.receiveNoLatency()
-		} else {
-			go shaper.receiveLatency()
-		}
-		go shaper.send()
-	} else if shaper.disrupted.disrupted {
-		go shaper.null()
+		shaper.routines.run(shaper.receive(), shaper.send)
+	} else if shaper.disrupted {
+		shaper.routines.run(shaper.null)
 	}
 }
@@ -13,6 +13,20 @@ func (shaper *NullShaper) GetProps() LinkProps {
 	return LinkProps{}
 }
 
+func (shaper *NullShaper) SetProps(props LinkProps) {
+}
+
+func (shaper *NullShaper) AdjustProps(change func(props *LinkProps)) {
+}
+
+func (shaper *NullShaper) GetStats() LinkStats {
+	return LinkStats{}
+}
+
+func (shaper *NullShaper) IsDisrupted() bool {
+	return false
+}
+
 func (shaper *NullShaper) GetIncoming() chan *xdp.Frame {
 	return shaper.incoming
 }
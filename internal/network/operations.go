@@ -10,25 +10,27 @@ type disruptLogic struct {
 	ctx       chan struct{}
 }
 
-func ConnectNodeToBridge(node *Node, bridge *Bridge, props LinkProps) *BiLink {
+// Connects node to bridge. up is applied to the node -> bridge direction and down to the bridge -> node direction.
+func ConnectNodeToBridge(node *Node, bridge *Bridge, up LinkProps, down LinkProps) *BiLink {
 
 	bridgeOutgoingChannel := make(chan *xdp.Frame, internal.QueueSize)
-	toLink := CreateLink(node.incoming, bridge.incomingChannel, props)
-	fromLink := CreateLink(bridgeOutgoingChannel, node.outgoing, props)
+	toLink := CreateLink(node.incoming, bridge.incomingChannel, up)
+	fromLink := CreateLink(bridgeOutgoingChannel, node.outgoing, down)
 	link := CreateBILink(toLink, fromLink)
 	node.SetLink(link)
 	bridge.AddNode([]byte(node.macAddr), bridgeOutgoingChannel)
 	return link
 }
 
-func ConnectBridgeToRouter(bridge *Bridge, router *Router, props LinkProps) *BiLink {
+// Connects bridge to router. up is applied to the bridge -> router direction and down to the router -> bridge direction.
+func ConnectBridgeToRouter(bridge *Bridge, router *Router, up LinkProps, down LinkProps) *BiLink {
 
 	gateway := make(chan *xdp.Frame, internal.QueueSize)
 	bridgeChannel := make(chan *xdp.Frame, internal.QueueSize)
 	bridge.SetGateway(gateway)
 
-	toLink := CreateLink(bridge.gateway, router.incomingChannel, props)
-	fromLink := CreateLink(bridgeChannel, bridge.incomingChannel, props)
+	toLink := CreateLink(bridge.gateway, router.incomingChannel, up)
+	fromLink := CreateLink(bridgeChannel, bridge.incomingChannel, down)
 	link := CreateBILink(toLink, fromLink)
 
 	bridge.SetLink(link)
@@ -36,12 +38,13 @@ func ConnectBridgeToRouter(bridge *Bridge, router *Router, props LinkProps) *BiL
 	return link
 }
 
-func ConnectRouterToRouter(router1 *Router, router2 *Router, props LinkProps) *BiLink {
+// Connects router1 to router2. up is applied to the router1 -> router2 direction and down to the router2 -> router1 direction.
+func ConnectRouterToRouter(router1 *Router, router2 *Router, up LinkProps, down LinkProps) *BiLink {
 	router1_to_router2_channel := make(chan *xdp.Frame, internal.QueueSize)
 	router2_to_router1_channel := make(chan *xdp.Frame, internal.QueueSize)
 
-	to_link := CreateLink(router1_to_router2_channel, router2.incomingChannel, props)
-	from_link := CreateLink(router2_to_router1_channel, router1.incomingChannel, props)
+	to_link := CreateLink(router1_to_router2_channel, router2.incomingChannel, up)
+	from_link := CreateLink(router2_to_router1_channel, router1.incomingChannel, down)
 	BI_link := CreateBILink(to_link, from_link)
 
 	return BI_link
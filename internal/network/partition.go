@@ -0,0 +1,86 @@
+package network
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Group of each MAC address of a partition
+type partitionGroups map[string]int
+
+// Blocks the traffic between groups of MAC addresses while keeping the traffic inside each group.
+// The same Partitions are shared by all bridges and routers of a machine and are checked for every frame,
+// so the active partitions are kept in an immutable snapshot that is replaced on every change.
+type Partitions struct {
+	sync.Mutex
+	partitions map[string]partitionGroups
+	snapshot   atomic.Pointer[[]partitionGroups]
+}
+
+func NewPartitions() *Partitions {
+	return &Partitions{
+		Mutex:      sync.Mutex{},
+		partitions: make(map[string]partitionGroups),
+	}
+}
+
+// Adds or replaces a partition. Each group is a list of MAC addresses.
+func (p *Partitions) Add(id string, groups [][]string) {
+	p.Lock()
+	defer p.Unlock()
+
+	macs := make(partitionGroups)
+	for i, group := range groups {
+		for _, mac := range group {
+			macs[mac] = i
+		}
+	}
+	p.partitions[id] = macs
+	p.update()
+}
+
+func (p *Partitions) Remove(id string) bool {
+	p.Lock()
+	defer p.Unlock()
+
+	if _, ok := p.partitions[id]; !ok {
+		return false
+	}
+	delete(p.partitions, id)
+	p.update()
+	return true
+}
+
+func (p *Partitions) Clear() {
+	p.Lock()
+	defer p.Unlock()
+	p.partitions = make(map[string]partitionGroups)
+	p.update()
+}
+
+func (p *Partitions) update() {
+	snapshot := make([]partitionGroups, 0, len(p.partitions))
+	for _, groups := range p.partitions {
+		snapshot = append(snapshot, groups)
+	}
+	p.snapshot.Store(&snapshot)
+}
+
+// Whether a frame from src to dst crosses a partition. Addresses outside a partition, like broadcasts, are never blocked.
+func (p *Partitions) Blocks(src string, dst string) bool {
+	if p == nil {
+		return false
+	}
+	snapshot := p.snapshot.Load()
+	if snapshot == nil {
+		return false
+	}
+	for _, groups := range *snapshot {
+		srcGroup, srcOk := groups[src]
+		dstGroup, dstOk := groups[dst]
+		if srcOk && dstOk && srcGroup != dstGroup {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,436 @@
+package network
+
+import (
+	"encoding/binary"
+	"github.com/David-Antunes/gone-proxy/xdp"
+	"github.com/David-Antunes/gone/internal"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	TailDrop = "taildrop"
+	RED      = "red"
+	CoDel    = "codel"
+	FQCoDel  = "fq_codel"
+)
+
+const (
+	defaultCodelTarget    = 5 * time.Millisecond
+	defaultCodelInterval  = 100 * time.Millisecond
+	defaultREDProbability = 0.1
+	redWeight             = 0.002
+	fqCodelFlows          = 1024
+)
+
+// Buffer limits and queue discipline of a link. Limits set to 0 are not enforced.
+type QueueProps struct {
+	LimitPackets int
+	LimitBytes   int
+	LimitTime    time.Duration
+	Discipline   string
+	// Mark ECN capable frames with Congestion Experienced instead of dropping them
+	ECN bool
+	// RED thresholds in packets
+	MinThreshold   int
+	MaxThreshold   int
+	MaxProbability float64
+	// CoDel parameters, also used by each FQ-CoDel flow
+	Target   time.Duration
+	Interval time.Duration
+}
+
+func (props *QueueProps) packetLimit() int {
+	if props.LimitPackets <= 0 || props.LimitPackets > internal.QueueSize {
+		return internal.QueueSize
+	}
+	return props.LimitPackets
+}
+
+func (props *QueueProps) codelTarget() time.Duration {
+	if props.Target <= 0 {
+		return defaultCodelTarget
+	}
+	return props.Target
+}
+
+func (props *QueueProps) codelInterval() time.Duration {
+	if props.Interval <= 0 {
+		return defaultCodelInterval
+	}
+	return props.Interval
+}
+
+type QueueStats struct {
+	Packets   int
+	Bytes     int
+	TailDrops uint64
+	AQMDrops  uint64
+	ECNMarks  uint64
+}
+
+// Backlog, counters and queue discipline state of a link, carried across shaper conversions.
+// The backlog is updated by the receive routine and the discipline state is only used by the send routine
+// of a NetworkShaper.
+type linkBuffer struct {
+	sync.Mutex
+	stats  QueueStats
+	red    float64
+	codel  codelState
+	flows  map[uint64]*fqFlow
+	new    []*fqFlow
+	old    []*fqFlow
+	queued int
+}
+
+func newLinkBuffer() *linkBuffer {
+	return &linkBuffer{
+		flows: make(map[uint64]*fqFlow),
+	}
+}
+
+func (buffer *linkBuffer) Stats() QueueStats {
+	buffer.Lock()
+	defer buffer.Unlock()
+	return buffer.stats
+}
+
+// Reserves space for frame in the buffer. Returns false and counts a tail drop if a limit was reached.
+func (buffer *linkBuffer) push(frame *xdp.Frame, props *LinkProps) bool {
+	buffer.Lock()
+	defer buffer.Unlock()
+	queue := &props.Queue
+	if buffer.stats.Packets >= queue.packetLimit() ||
+		(queue.LimitBytes > 0 && buffer.stats.Bytes+frame.FrameSize > queue.LimitBytes) ||
+		(queue.LimitTime > 0 && props.Bandwidth > 0 &&
+			time.Duration(float64(buffer.stats.Bytes)/float64(props.Bandwidth)*float64(time.Second)) > queue.LimitTime) {
+		buffer.stats.TailDrops++
+		return false
+	}
+	buffer.stats.Packets++
+	buffer.stats.Bytes += frame.FrameSize
+	return true
+}
+
+// Releases the space of a frame leaving the buffer.
+// Frames queued before a shaper conversion may never have been reserved, so the backlog can't go below 0.
+func (buffer *linkBuffer) pop(frame *xdp.Frame) {
+	buffer.Lock()
+	defer buffer.Unlock()
+	buffer.stats.Packets = max(buffer.stats.Packets-1, 0)
+	buffer.stats.Bytes = max(buffer.stats.Bytes-frame.FrameSize, 0)
+}
+
+func (buffer *linkBuffer) tailDrop() {
+	buffer.Lock()
+	buffer.stats.TailDrops++
+	buffer.Unlock()
+}
+
+// Drops or marks frame according to the queue discipline. Returns nil if frame was dropped.
+func (buffer *linkBuffer) manage(frame *xdp.Frame, props *QueueProps, rng *rand.Rand, now time.Time) *xdp.Frame {
+	var congested bool
+	switch props.Discipline {
+	case RED:
+		congested = buffer.pollRED(props, rng)
+	case CoDel:
+		congested = buffer.codel.poll(frame, buffer.backlog(), props, now)
+	default:
+		return frame
+	}
+	if !congested {
+		return frame
+	}
+	return buffer.congestion(frame, props)
+}
+
+// Marks frame if ECN is enabled and the frame supports it, otherwise drops it
+func (buffer *linkBuffer) congestion(frame *xdp.Frame, props *QueueProps) *xdp.Frame {
+	if props.ECN {
+		if marked := markCongestion(frame); marked != nil {
+			buffer.Lock()
+			buffer.stats.ECNMarks++
+			buffer.Unlock()
+			return marked
+		}
+	}
+	buffer.Lock()
+	buffer.stats.AQMDrops++
+	buffer.Unlock()
+	buffer.pop(frame)
+	return nil
+}
+
+func (buffer *linkBuffer) backlog() int {
+	buffer.Lock()
+	defer buffer.Unlock()
+	return buffer.stats.Bytes
+}
+
+func (buffer *linkBuffer) pollRED(props *QueueProps, rng *rand.Rand) bool {
+	buffer.Lock()
+	packets := buffer.stats.Packets
+	buffer.Unlock()
+
+	minThreshold := props.MinThreshold
+	maxThreshold := props.MaxThreshold
+	if minThreshold <= 0 {
+		minThreshold = props.packetLimit() / 4
+	}
+	if maxThreshold <= minThreshold {
+		maxThreshold = 3 * minThreshold
+	}
+	maxProbability := props.MaxProbability
+	if maxProbability <= 0 {
+		maxProbability = defaultREDProbability
+	}
+
+	buffer.red = (1-redWeight)*buffer.red + redWeight*float64(packets)
+	if buffer.red < float64(minThreshold) {
+		return false
+	} else if buffer.red >= float64(maxThreshold) {
+		return true
+	}
+	p := maxProbability * (buffer.red - float64(minThreshold)) / float64(maxThreshold-minThreshold)
+	return rng.Float64() < p
+}
+
+// Time a frame waited beyond its scheduled departure, which is the queueing delay caused by the link bandwidth
+func sojourn(frame *xdp.Frame, now time.Time) time.Duration {
+	return max(now.Sub(frame.Time), 0)
+}
+
+// CoDel state as described in RFC 8289
+type codelState struct {
+	firstAboveTime time.Time
+	dropNext       time.Time
+	count          int
+	lastCount      int
+	dropping       bool
+}
+
+func (state *codelState) controlLaw(t time.Time, interval time.Duration) time.Time {
+	return t.Add(time.Duration(float64(interval) / math.Sqrt(float64(state.count))))
+}
+
+func (state *codelState) okToDrop(frame *xdp.Frame, backlog int, props *QueueProps, now time.Time) bool {
+	if sojourn(frame, now) < props.codelTarget() || backlog <= internal.PacketSize {
+		state.firstAboveTime = time.Time{}
+		return false
+	}
+	if state.firstAboveTime.IsZero() {
+		state.firstAboveTime = now.Add(props.codelInterval())
+		return false
+	}
+	return !now.Before(state.firstAboveTime)
+}
+
+// Returns true if frame should be dropped
+func (state *codelState) poll(frame *xdp.Frame, backlog int, props *QueueProps, now time.Time) bool {
+	interval := props.codelInterval()
+	okToDrop := state.okToDrop(frame, backlog, props, now)
+
+	if state.dropping {
+		if !okToDrop {
+			state.dropping = false
+			return false
+		}
+		if !now.Before(state.dropNext) {
+			state.count++
+			state.dropNext = state.controlLaw(state.dropNext, interval)
+			return true
+		}
+		return false
+	}
+
+	if okToDrop {
+		state.dropping = true
+		delta := state.count - state.lastCount
+		if delta > 1 && now.Sub(state.dropNext) < 16*interval {
+			state.count = delta
+		} else {
+			state.count = 1
+		}
+		state.lastCount = state.count
+		state.dropNext = state.controlLaw(now, interval)
+		return true
+	}
+	return false
+}
+
+type fqFlow struct {
+	frames  []*xdp.Frame
+	deficit int
+	codel   codelState
+	active  bool
+}
+
+// Moves frame into its FQ-CoDel flow
+func (buffer *linkBuffer) enqueueFlow(frame *xdp.Frame) {
+	id := flowHash(frame) % fqCodelFlows
+	flow, ok := buffer.flows[id]
+	if !ok {
+		flow = &fqFlow{}
+		buffer.flows[id] = flow
+	}
+	flow.frames = append(flow.frames, frame)
+	buffer.queued++
+	if !flow.active {
+		flow.active = true
+		flow.deficit = internal.PacketSize
+		buffer.new = append(buffer.new, flow)
+	}
+}
+
+// Picks the next frame using deficit round robin between flows, applying CoDel to each flow.
+// Returns nil once every flow is empty.
+func (buffer *linkBuffer) dequeueFlow(props *QueueProps, now time.Time) *xdp.Frame {
+	for len(buffer.new) > 0 || len(buffer.old) > 0 {
+		isNew := len(buffer.new) > 0
+		var flow *fqFlow
+		if isNew {
+			flow = buffer.new[0]
+		} else {
+			flow = buffer.old[0]
+		}
+
+		if flow.deficit <= 0 {
+			flow.deficit += internal.PacketSize
+			buffer.removeFlow(isNew)
+			buffer.old = append(buffer.old, flow)
+			continue
+		}
+
+		var frame *xdp.Frame
+		for len(flow.frames) > 0 && frame == nil {
+			frame = flow.frames[0]
+			flow.frames = flow.frames[1:]
+			buffer.queued--
+			if flow.codel.poll(frame, buffer.backlog(), props, now) {
+				frame = buffer.congestion(frame, props)
+			}
+		}
+
+		if frame == nil {
+			buffer.removeFlow(isNew)
+			if isNew {
+				buffer.old = append(buffer.old, flow)
+			} else {
+				flow.active = false
+			}
+			continue
+		}
+		flow.deficit -= frame.FrameSize
+		return frame
+	}
+	return nil
+}
+
+// Removes every frame from the FQ-CoDel flows, oldest flows first
+func (buffer *linkBuffer) flushFlows() []*xdp.Frame {
+	frames := make([]*xdp.Frame, 0, buffer.queued)
+	for _, flow := range append(buffer.new, buffer.old...) {
+		frames = append(frames, flow.frames...)
+		flow.frames = nil
+		flow.active = false
+	}
+	buffer.new = nil
+	buffer.old = nil
+	buffer.queued = 0
+	return frames
+}
+
+func (buffer *linkBuffer) removeFlow(isNew bool) {
+	if isNew {
+		buffer.new = buffer.new[1:]
+	} else {
+		buffer.old = buffer.old[1:]
+	}
+}
+
+// Identifies the flow of a frame by its IP addresses, protocol and ports, or by its MAC addresses for non IP frames
+func flowHash(frame *xdp.Frame) uint64 {
+	h := fnv.New64a()
+	data := frame.FramePointer[:min(frame.FrameSize, len(frame.FramePointer))]
+	if len(data) < ethernetHeaderSize {
+		h.Write([]byte(frame.MacOrigin + frame.MacDestination))
+		return h.Sum64()
+	}
+	switch binary.BigEndian.Uint16(data[12:14]) {
+	case 0x0800:
+		ip := data[ethernetHeaderSize:]
+		if len(ip) >= 20 {
+			headerSize := int(ip[0]&0x0f) * 4
+			h.Write(ip[9:10])
+			h.Write(ip[12:20])
+			if len(ip) >= headerSize+4 {
+				h.Write(ip[headerSize : headerSize+4])
+			}
+			return h.Sum64()
+		}
+	case 0x86dd:
+		ip := data[ethernetHeaderSize:]
+		if len(ip) >= 40 {
+			h.Write(ip[6:7])
+			h.Write(ip[8:40])
+			if len(ip) >= 44 {
+				h.Write(ip[40:44])
+			}
+			return h.Sum64()
+		}
+	}
+	h.Write(data[:12])
+	return h.Sum64()
+}
+
+// Returns a copy of frame with the ECN field set to Congestion Experienced, or nil if frame is not ECN capable
+func markCongestion(frame *xdp.Frame) *xdp.Frame {
+	data := frame.FramePointer[:min(frame.FrameSize, len(frame.FramePointer))]
+	if len(data) < ethernetHeaderSize {
+		return nil
+	}
+	switch binary.BigEndian.Uint16(data[12:14]) {
+	case 0x0800:
+		if len(data) < ethernetHeaderSize+20 {
+			return nil
+		}
+		if data[ethernetHeaderSize+1]&0x03 == 0 {
+			return nil
+		}
+		marked := cloneFrame(frame)
+		ip := marked.FramePointer[ethernetHeaderSize:]
+		ip[1] |= 0x03
+		headerSize := int(ip[0]&0x0f) * 4
+		if headerSize < 20 || headerSize > len(ip) {
+			return marked
+		}
+		ip[10], ip[11] = 0, 0
+		binary.BigEndian.PutUint16(ip[10:12], ipv4Checksum(ip[:headerSize]))
+		return marked
+	case 0x86dd:
+		if len(data) < ethernetHeaderSize+40 {
+			return nil
+		}
+		if data[ethernetHeaderSize+1]&0x30 == 0 {
+			return nil
+		}
+		marked := cloneFrame(frame)
+		marked.FramePointer[ethernetHeaderSize+1] |= 0x30
+		return marked
+	}
+	return nil
+}
+
+func ipv4Checksum(header []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(header); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(header[i : i+2]))
+	}
+	for sum > 0xffff {
+		sum = (sum >> 16) + (sum & 0xffff)
+	}
+	return ^uint16(sum)
+}
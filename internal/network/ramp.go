@@ -0,0 +1,261 @@
+package network
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Ramp curves
+const (
+	LinearRamp      = "linear"
+	ExponentialRamp = "exponential"
+)
+
+// Ramp states
+const (
+	RampRunning   = "running"
+	RampReversing = "reversing"
+	RampFinished  = "finished"
+	RampReversed  = "reversed"
+	RampStopped   = "stopped"
+)
+
+// Steepness of the exponential curve. Most of the change happens in the last part of the ramp.
+const rampExponent = 5.0
+
+// Default time between two updates of the link
+const DefaultRampStep = 100 * time.Millisecond
+
+// Targets of a ramp. Only the properties with a target change, the others keep their current value.
+type RampProps struct {
+	Duration  time.Duration
+	Step      time.Duration
+	Curve     string
+	Latency   *time.Duration
+	DropRate  *float64
+	Bandwidth *int
+}
+
+type rampValues struct {
+	latency   time.Duration
+	dropRate  float64
+	bandwidth int
+}
+
+func currentRampValues(props LinkProps) rampValues {
+	return rampValues{
+		latency:   props.Latency,
+		dropRate:  props.DropRate,
+		bandwidth: props.Bandwidth,
+	}
+}
+
+func (props RampProps) target(from rampValues) rampValues {
+	to := from
+	if props.Latency != nil {
+		to.latency = *props.Latency
+	}
+	if props.DropRate != nil {
+		to.dropRate = *props.DropRate
+	}
+	if props.Bandwidth != nil {
+		to.bandwidth = *props.Bandwidth
+	}
+	return to
+}
+
+// Fraction of the change applied after progress, between 0 and 1, of the ramp
+func (props RampProps) fraction(progress float64) float64 {
+	if props.Curve == ExponentialRamp {
+		return (math.Exp(rampExponent*progress) - 1) / (math.Exp(rampExponent) - 1)
+	}
+	return progress
+}
+
+func interpolate(from rampValues, to rampValues, f float64) rampValues {
+	return rampValues{
+		latency:   from.latency + time.Duration(f*float64(to.latency-from.latency)),
+		dropRate:  from.dropRate + f*(to.dropRate-from.dropRate),
+		bandwidth: from.bandwidth + int(math.Round(f*float64(to.bandwidth-from.bandwidth))),
+	}
+}
+
+// Segment of a ramp, moving the links from one set of values to another
+type rampSegment struct {
+	from     []rampValues
+	to       []rampValues
+	start    time.Time
+	duration time.Duration
+}
+
+func (segment *rampSegment) progress(now time.Time) float64 {
+	if segment.duration <= 0 {
+		return 1
+	}
+	return min(float64(now.Sub(segment.start))/float64(segment.duration), 1)
+}
+
+// Gradually moves the latency, drop rate and bandwidth of links to their targets, updating the running shapers every step.
+type Ramp struct {
+	sync.Mutex
+	links    []*Link
+	props    RampProps
+	original []rampValues
+	segment  rampSegment
+	state    string
+	ctx      chan struct{}
+}
+
+func StartRamp(links []*Link, props RampProps) *Ramp {
+	if props.Step <= 0 {
+		props.Step = DefaultRampStep
+	}
+	original := make([]rampValues, 0, len(links))
+	targets := make([]rampValues, 0, len(links))
+	for _, link := range links {
+		values := currentRampValues(link.GetProps())
+		original = append(original, values)
+		targets = append(targets, props.target(values))
+	}
+	ramp := &Ramp{
+		Mutex:    sync.Mutex{},
+		links:    links,
+		props:    props,
+		original: original,
+		segment: rampSegment{
+			from:     original,
+			to:       targets,
+			start:    time.Now(),
+			duration: props.Duration,
+		},
+		state: RampRunning,
+		ctx:   make(chan struct{}),
+	}
+	go ramp.run()
+	return ramp
+}
+
+func (ramp *Ramp) run() {
+	ticker := time.NewTicker(ramp.props.Step)
+	defer ticker.Stop()
+	for {
+		if !ramp.step(time.Now()) {
+			return
+		}
+		select {
+		case <-ramp.ctx:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Applies the values of the current segment. Returns false once the ramp is over.
+func (ramp *Ramp) step(now time.Time) bool {
+	ramp.Lock()
+	defer ramp.Unlock()
+	if !ramp.runningLocked() {
+		return false
+	}
+	progress := ramp.segment.progress(now)
+	ramp.applyLocked(ramp.props.fraction(progress))
+	if progress < 1 {
+		return true
+	}
+	if ramp.state == RampReversing {
+		ramp.state = RampReversed
+	} else {
+		ramp.state = RampFinished
+	}
+	return false
+}
+
+func (ramp *Ramp) applyLocked(f float64) {
+	for i, link := range ramp.links {
+		ramp.setLocked(link, interpolate(ramp.segment.from[i], ramp.segment.to[i], f))
+	}
+}
+
+// Only the properties the ramp targets are written, so other updates of the link and its trace are kept
+func (ramp *Ramp) setLocked(link *Link, values rampValues) {
+	link.AdjustProps(func(props *LinkProps) {
+		if ramp.props.Latency != nil {
+			props.Latency = values.latency
+			props.FLatency = float64(values.latency) / float64(time.Millisecond) * 2.0
+		}
+		if ramp.props.DropRate != nil {
+			props.DropRate = values.dropRate
+		}
+		if ramp.props.Bandwidth != nil {
+			props.Bandwidth = values.bandwidth
+		}
+	})
+}
+
+// Moves the links back to the values they had before the ramp, taking as long as the ramp has run so far.
+// A finished ramp is reversed over its whole duration.
+func (ramp *Ramp) Reverse() bool {
+	ramp.Lock()
+	defer ramp.Unlock()
+	if ramp.state != RampRunning && ramp.state != RampFinished {
+		return false
+	}
+	now := time.Now()
+	current := make([]rampValues, 0, len(ramp.links))
+	for _, link := range ramp.links {
+		current = append(current, currentRampValues(link.GetProps()))
+	}
+	ramp.segment = rampSegment{
+		from:     current,
+		to:       ramp.original,
+		start:    now,
+		duration: min(now.Sub(ramp.segment.start), ramp.props.Duration),
+	}
+	restart := ramp.state == RampFinished
+	ramp.state = RampReversing
+	if restart {
+		go ramp.run()
+	}
+	return true
+}
+
+// Stops the ramp, leaving the links with their current values unless restore is set
+func (ramp *Ramp) Stop(restore bool) bool {
+	ramp.Lock()
+	defer ramp.Unlock()
+	if ramp.state == RampStopped {
+		return false
+	}
+	if ramp.runningLocked() {
+		close(ramp.ctx)
+	}
+	if restore {
+		for i, link := range ramp.links {
+			ramp.setLocked(link, ramp.original[i])
+		}
+	}
+	ramp.state = RampStopped
+	return true
+}
+
+func (ramp *Ramp) Running() bool {
+	ramp.Lock()
+	defer ramp.Unlock()
+	return ramp.runningLocked()
+}
+
+func (ramp *Ramp) runningLocked() bool {
+	return ramp.state == RampRunning || ramp.state == RampReversing
+}
+
+// Current state and progress, between 0 and 1, of the current segment
+func (ramp *Ramp) Status() (string, float64) {
+	ramp.Lock()
+	defer ramp.Unlock()
+	return ramp.state, ramp.segment.progress(time.Now())
+}
+
+func (ramp *Ramp) GetProps() RampProps {
+	return ramp.props
+}
@@ -11,19 +11,55 @@ import (
 type RemoteShaper struct {
 	running   bool
 	queue     chan *xdp.Frame
+	front     chan *xdp.Frame
 	incoming  chan *xdp.Frame
 	outgoing  chan *RouterFrame
 	delay     *Delay
-	props     LinkProps
+	props     *sharedProps
+	loss      lossState
+	counters  *linkCounters
+	jitter    jitterState
+	reorder   reorderState
+	buffer    *linkBuffer
 	limiter   *rate.Limiter
+	replay    *traceReplay
 	tokenSize int
-	ctx       chan struct{}
+	routines  routines
 	To        string
 	From      string
 }
 
 func (shaper *RemoteShaper) GetProps() LinkProps {
-	return shaper.props
+	return *shaper.props.load()
+}
+
+func (shaper *RemoteShaper) SetProps(props LinkProps) {
+	shaper.props.store(props)
+	shaper.limiter.SetLimit(bandwidthLimit(props.Bandwidth))
+	shaper.replay.set(props.Trace, shaper.applyTrace)
+}
+
+func (shaper *RemoteShaper) AdjustProps(change func(props *LinkProps)) {
+	adjustProps(shaper.props, shaper.limiter, change)
+}
+
+func (shaper *RemoteShaper) applyTrace(point TracePoint) {
+	applyTracePoint(shaper.props, shaper.limiter, point)
+}
+
+func (shaper *RemoteShaper) GetQueueStats() QueueStats {
+	return shaper.buffer.Stats()
+}
+
+func (shaper *RemoteShaper) GetStats() LinkStats {
+	queue := shaper.buffer.Stats()
+	stats := shaper.counters.stats(max(queue.Packets, len(shaper.queue)))
+	stats.QueueDrops += queue.TailDrops + queue.AQMDrops
+	return stats
+}
+
+func (shaper *RemoteShaper) IsDisrupted() bool {
+	return false
 }
 
 func (shaper *RemoteShaper) GetIncoming() chan *xdp.Frame {
@@ -46,15 +82,18 @@ func CreateRemoteShaper(to string, from string, incoming chan *xdp.Frame, outgoi
 	newTime := float64(time.Second) * aux
 
 	return &RemoteShaper{
+		counters:  &linkCounters{},
 		running:   false,
 		queue:     make(chan *xdp.Frame, internal.QueueSize),
+		front:     make(chan *xdp.Frame, internal.QueueSize),
+		buffer:    newLinkBuffer(),
 		incoming:  incoming,
 		outgoing:  outgoing,
-		props:     props,
+		props:     newSharedProps(props),
 		limiter:   rate.NewLimiter(rate.Every(time.Duration(newTime)), 1),
+		replay:    newTraceReplay(props.Trace),
 		tokenSize: internal.PacketSize,
 		delay:     &Delay{0},
-		ctx:       make(chan struct{}),
 		To:        to,
 		From:      from,
 	}
@@ -62,72 +101,102 @@ func CreateRemoteShaper(to string, from string, incoming chan *xdp.Frame, outgoi
 func (shaper *RemoteShaper) Stop() {
 	if shaper.running {
 		shaper.running = false
-		shaper.ctx <- struct{}{}
-		shaper.ctx <- struct{}{}
+		shaper.routines.halt()
 	}
 }
 
 func (shaper *RemoteShaper) Start() {
+	shaper.replay.start(shaper.applyTrace)
 	if !shaper.running {
 		shaper.running = true
-		go shaper.receive()
-		go shaper.send()
+		shaper.routines.run(shaper.receive, shaper.send)
 	}
 }
 
-func (shaper *RemoteShaper) receive() {
+func (shaper *RemoteShaper) receive(stop chan struct{}) {
 
 	for {
 
 		select {
-		case <-shaper.ctx:
+		case <-stop:
 			return
 
 		case frame := <-shaper.incoming:
-			frame.Time = frame.Time.Add(shaper.props.Latency)
-			frame.Time = frame.Time.Add(shaper.props.PollJitter())
+			props := shaper.props.load()
+			old := frame.Time
+			frame.Time = frame.Time.Add(shaper.jitter.poll(props, props.rng.receive))
 			frame.Time = frame.Time.Add(-shaper.delay.Value)
-			if shaper.props.PollDropRate() {
+			if shaper.loss.poll(props, props.rng.receive) {
+				shaper.counters.lossDrops.Add(1)
 				continue
 			}
-			//if len(shaper.queue) < internal.QueueSize {
-			shaper.queue <- frame
-			//}
+			frame, duplicate := impair(frame, props, props.rng.receive)
+			if duplicate != nil {
+				shaper.enqueue(duplicate, props, stop)
+			}
+			if shaper.reorder.poll(props, props.rng.receive) {
+				frame.Time = old.Add(-shaper.delay.Value)
+				sendFrame(shaper.front, frame, stop)
+				continue
+			}
+			shaper.enqueue(frame, props, stop)
 		}
 	}
 }
 
-func (shaper *RemoteShaper) send() {
+func (shaper *RemoteShaper) send(stop chan struct{}) {
 
 	for {
 		select {
-		case <-shaper.ctx:
+		case <-stop:
 			return
 
+		case frame := <-shaper.front:
+			shaper.transmit(frame)
+
 		case frame := <-shaper.queue:
-			var r *rate.Reservation
-			if shaper.tokenSize < frame.FrameSize {
-				r = shaper.limiter.Reserve()
-				if !r.OK() {
-					fmt.Println("Something went wrong")
-				}
-				shaper.tokenSize = shaper.tokenSize - frame.FrameSize + internal.PacketSize
-				frame.Time = frame.Time.Add(r.Delay())
-			} else {
-				shaper.tokenSize = shaper.tokenSize - frame.FrameSize
-			}
-			//go func() {
-			time.Sleep(time.Until(frame.Time))
-			if len(shaper.outgoing) < internal.RemoteQueueSize {
-				shaper.outgoing <- &RouterFrame{
-					To:    shaper.To,
-					From:  shaper.From,
-					Frame: frame,
-				}
+			// Reordered frames overtake every frame still waiting in the queue
+			for len(shaper.front) > 0 {
+				shaper.transmit(<-shaper.front)
 			}
-			//}()
+			shaper.buffer.pop(frame)
+			shaper.transmit(frame)
+		}
+	}
+}
+
+// Queues frame unless a limit of the link buffer was reached
+func (shaper *RemoteShaper) enqueue(frame *xdp.Frame, props *LinkProps, stop chan struct{}) {
+	if shaper.buffer.push(frame, props) && !sendFrame(shaper.queue, frame, stop) {
+		shaper.buffer.pop(frame)
+	}
+}
+
+func (shaper *RemoteShaper) transmit(frame *xdp.Frame) {
+	var r *rate.Reservation
+	if shaper.tokenSize < frame.FrameSize {
+		r = shaper.limiter.Reserve()
+		if !r.OK() {
+			fmt.Println("Something went wrong")
+		}
+		shaper.tokenSize = shaper.tokenSize - frame.FrameSize + internal.PacketSize
+		frame.Time = frame.Time.Add(r.Delay())
+	} else {
+		shaper.tokenSize = shaper.tokenSize - frame.FrameSize
+	}
+	//go func() {
+	time.Sleep(time.Until(frame.Time))
+	if len(shaper.outgoing) < internal.RemoteQueueSize {
+		shaper.outgoing <- &RouterFrame{
+			To:    shaper.To,
+			From:  shaper.From,
+			Frame: frame,
 		}
+		shaper.counters.forward(frame)
+	} else {
+		shaper.counters.queueDrops.Add(1)
 	}
+	//}()
 }
 
 func (shaper *RemoteShaper) Disrupt() bool {
@@ -139,6 +208,7 @@ func (shaper *RemoteShaper) StopDisrupt() bool {
 }
 
 func (shaper *RemoteShaper) Close() {
+	shaper.replay.stop()
 	shaper.Stop()
 	shaper.StopDisrupt()
 }
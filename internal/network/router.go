@@ -19,9 +19,11 @@ type Router struct {
 	queue           chan *xdp.Frame
 	ctx             chan struct{}
 	disrupted       disruptLogic
+	counters        *componentCounters
+	partitions      *Partitions
 }
 
-func CreateRouter(id string) *Router {
+func CreateRouter(id string, partitions *Partitions) *Router {
 
 	return &Router{
 		RWMutex:         sync.RWMutex{},
@@ -35,9 +37,19 @@ func CreateRouter(id string) *Router {
 			disrupted: false,
 			ctx:       make(chan struct{}, 1),
 		},
+		counters:   &componentCounters{},
+		partitions: partitions,
 	}
 }
 
+func (router *Router) GetStats() ComponentStats {
+	return router.counters.stats(len(router.queue))
+}
+
+func (router *Router) IsDisrupted() bool {
+	return router.disrupted.disrupted
+}
+
 func (router *Router) Incoming() chan *xdp.Frame {
 	return router.incomingChannel
 }
@@ -91,6 +103,7 @@ func (router *Router) null() {
 		case <-router.disrupted.ctx:
 			return
 		case <-router.incomingChannel:
+			router.counters.disruptedDrops.Add(1)
 			continue
 		}
 	}
@@ -119,14 +132,22 @@ func (router *Router) send() {
 		case <-router.ctx:
 			return
 		case frame := <-router.queue:
+			if router.partitions.Blocks(frame.GetMacOrigin(), frame.GetMacDestination()) {
+				router.counters.partitionDrops.Add(1)
+				continue
+			}
 			router.RLock()
 			if channel, ok := router.channels[frame.GetMacDestination()]; ok {
 				if len(channel) < internal.QueueSize {
 					channel <- frame
+					router.counters.forward(frame)
+				} else {
+					router.counters.queueDrops.Add(1)
 				}
 				router.RUnlock()
 			} else {
 				router.RUnlock()
+				router.counters.unknownFrames.Add(1)
 				routing.HandleNewMac(frame, router.id)
 			}
 		}
@@ -134,24 +155,38 @@ func (router *Router) send() {
 }
 
 func (router *Router) InjectFrame(frame *xdp.Frame) {
+	if router.partitions.Blocks(frame.GetMacOrigin(), frame.GetMacDestination()) {
+		router.counters.partitionDrops.Add(1)
+		return
+	}
 	router.RLock()
 	defer router.RUnlock()
 	if channel, ok := router.channels[frame.GetMacDestination()]; ok && len(channel) < internal.QueueSize {
 		channel <- frame
+		router.counters.forward(frame)
 	} else {
+		router.counters.queueDrops.Add(1)
 		fmt.Println("Failed to inject Frame", router.id, net.HardwareAddr(frame.MacDestination))
 	}
 }
 
 func (router *Router) RemoteInjectFrame(frame *xdp.Frame) {
+	if router.partitions.Blocks(frame.GetMacOrigin(), frame.GetMacDestination()) {
+		router.counters.partitionDrops.Add(1)
+		return
+	}
 	router.RLock()
 	if channel, ok := router.channels[frame.GetMacDestination()]; ok {
 		if len(channel) < internal.QueueSize {
 			channel <- frame
+			router.counters.forward(frame)
+		} else {
+			router.counters.queueDrops.Add(1)
 		}
 		router.RUnlock()
 	} else {
 		router.RUnlock()
+		router.counters.unknownFrames.Add(1)
 		routing.HandleNewMac(frame, router.id)
 	}
 }
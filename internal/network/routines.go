@@ -0,0 +1,95 @@
+package network
+
+import (
+	"github.com/David-Antunes/gone-proxy/xdp"
+	"sync"
+	"time"
+)
+
+// Goroutines of a shaper. A shaper runs one group at a time and a new group only starts once every goroutine of
+// the previous group has returned, so a link never has two receive or send routines.
+type routines struct {
+	sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Halts the running group, if any, and starts fns as a new group. Each fn must return once stop is closed.
+func (group *routines) run(fns ...func(stop chan struct{})) {
+	group.Lock()
+	defer group.Unlock()
+	group.haltLocked()
+	group.startLocked(fns)
+}
+
+// Replaces the running group with fns. Does nothing when no group is running.
+func (group *routines) restart(fns ...func(stop chan struct{})) {
+	group.Lock()
+	defer group.Unlock()
+	if group.stop == nil {
+		return
+	}
+	group.haltLocked()
+	group.startLocked(fns)
+}
+
+// Stops the running group and waits for its goroutines to return
+func (group *routines) halt() {
+	group.Lock()
+	defer group.Unlock()
+	group.haltLocked()
+}
+
+func (group *routines) startLocked(fns []func(stop chan struct{})) {
+	stop := make(chan struct{})
+	group.stop = stop
+	for _, fn := range fns {
+		group.wg.Add(1)
+		go func() {
+			defer group.wg.Done()
+			fn(stop)
+		}()
+	}
+}
+
+func (group *routines) haltLocked() {
+	if group.stop != nil {
+		close(group.stop)
+		group.stop = nil
+		group.wg.Wait()
+	}
+}
+
+func stopped(stop chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Returns a channel closed once stop is closed or timeout has passed
+func stopAfter(stop chan struct{}, timeout time.Duration) chan struct{} {
+	expired := make(chan struct{})
+	go func() {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-stop:
+		case <-timer.C:
+		}
+		close(expired)
+	}()
+	return expired
+}
+
+// Blocks until frame is sent on channel. Returns false if stop is closed first.
+func sendFrame(channel chan *xdp.Frame, frame *xdp.Frame, stop chan struct{}) bool {
+	select {
+	case <-stop:
+		return false
+	case channel <- frame:
+		return true
+	}
+}
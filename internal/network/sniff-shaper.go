@@ -12,19 +12,55 @@ import (
 type SniffShaper struct {
 	running   bool
 	queue     chan *xdp.Frame
+	front     chan *xdp.Frame
 	incoming  chan *xdp.Frame
 	outgoing  chan *xdp.Frame
-	props     LinkProps
+	props     *sharedProps
+	loss      lossState
+	counters  *linkCounters
+	jitter    jitterState
+	reorder   reorderState
+	buffer    *linkBuffer
 	delay     *Delay
 	limiter   *rate.Limiter
+	replay    *traceReplay
 	tokenSize int
-	ctx       chan struct{}
+	routines  routines
 	rt        *redirect_traffic.SniffComponent
-	disrupted disruptLogic
+	disrupted bool
 }
 
 func (shaper *SniffShaper) GetProps() LinkProps {
-	return shaper.props
+	return *shaper.props.load()
+}
+
+func (shaper *SniffShaper) SetProps(props LinkProps) {
+	shaper.props.store(props)
+	shaper.limiter.SetLimit(bandwidthLimit(props.Bandwidth))
+	shaper.replay.set(props.Trace, shaper.applyTrace)
+}
+
+func (shaper *SniffShaper) AdjustProps(change func(props *LinkProps)) {
+	adjustProps(shaper.props, shaper.limiter, change)
+}
+
+func (shaper *SniffShaper) applyTrace(point TracePoint) {
+	applyTracePoint(shaper.props, shaper.limiter, point)
+}
+
+func (shaper *SniffShaper) GetQueueStats() QueueStats {
+	return shaper.buffer.Stats()
+}
+
+func (shaper *SniffShaper) GetStats() LinkStats {
+	queue := shaper.buffer.Stats()
+	stats := shaper.counters.stats(max(queue.Packets, len(shaper.queue)))
+	stats.QueueDrops += queue.TailDrops + queue.AQMDrops
+	return stats
+}
+
+func (shaper *SniffShaper) IsDisrupted() bool {
+	return shaper.disrupted
 }
 
 func (shaper *SniffShaper) GetIncoming() chan *xdp.Frame {
@@ -50,63 +86,61 @@ func NewSniffShaper(incoming chan *xdp.Frame, outgoing chan *xdp.Frame, props Li
 	aux := float64(internal.PacketSize / props.Bandwidth)
 	newTime := float64(time.Second) * aux
 	return &SniffShaper{
+		counters:  &linkCounters{},
 		running:   false,
 		queue:     make(chan *xdp.Frame, internal.QueueSize),
+		front:     make(chan *xdp.Frame, internal.QueueSize),
+		buffer:    newLinkBuffer(),
 		incoming:  incoming,
 		outgoing:  outgoing,
-		props:     props,
+		props:     newSharedProps(props),
 		delay:     &Delay{0},
 		limiter:   rate.NewLimiter(rate.Every(time.Duration(newTime)), 1),
+		replay:    newTraceReplay(props.Trace),
 		tokenSize: internal.PacketSize,
-		ctx:       make(chan struct{}, 2),
 		rt:        rt,
-		disrupted: disruptLogic{
-			disrupted: false,
-			ctx:       make(chan struct{}, 1),
-		},
+		disrupted: false,
 	}
 }
 
 func (shaper *SniffShaper) Stop() {
 	if shaper.running {
 		shaper.running = false
-		shaper.ctx <- struct{}{}
-		shaper.ctx <- struct{}{}
+		shaper.routines.halt()
 	}
 }
 
 func (shaper *SniffShaper) Start() {
+	shaper.replay.start(shaper.applyTrace)
 	if !shaper.running {
 		shaper.running = true
-		go shaper.receive()
-		go shaper.send()
+		shaper.routines.run(shaper.receive, shaper.send)
 	}
 }
 
 func (shaper *SniffShaper) Disrupt() bool {
-	if !shaper.disrupted.disrupted {
-		shaper.disrupted.disrupted = true
+	if !shaper.disrupted {
+		shaper.disrupted = true
 		shaper.Stop()
-		go shaper.null()
-
-		// Clear queue for requests
-		go func() {
-			go shaper.send()
-			time.Sleep(time.Second)
-			shaper.ctx <- struct{}{}
-		}()
+		shaper.routines.run(shaper.null, shaper.drain)
 		return true
 	} else {
 		return false
 	}
 }
 
-func (shaper *SniffShaper) null() {
+// Clear queue for requests. Stopping the disruption stops the drain before the routines start again.
+func (shaper *SniffShaper) drain(stop chan struct{}) {
+	shaper.send(stopAfter(stop, time.Second))
+}
+
+func (shaper *SniffShaper) null(stop chan struct{}) {
 	for {
 		select {
-		case <-shaper.disrupted.ctx:
+		case <-stop:
 			return
 		case <-shaper.incoming:
+			shaper.counters.disruptedDrops.Add(1)
 			continue
 		}
 	}
@@ -114,103 +148,142 @@ func (shaper *SniffShaper) null() {
 
 func (shaper *SniffShaper) StopDisrupt() bool {
 
-	if shaper.disrupted.disrupted {
-		shaper.disrupted.disrupted = false
-		shaper.disrupted.ctx <- struct{}{}
+	if shaper.disrupted {
+		shaper.disrupted = false
+		shaper.routines.halt()
 		shaper.Start()
 		return true
 	}
 	return false
 }
 
-func (shaper *SniffShaper) receive() {
+func (shaper *SniffShaper) receive(stop chan struct{}) {
 
 	for {
 
 		select {
-		case <-shaper.ctx:
+		case <-stop:
 			return
 		case <-shaper.rt.Socket.GetIncoming():
 			continue
 		case frame := <-shaper.incoming:
-			if shaper.props.PollDropRate() {
+			props := shaper.props.load()
+			if shaper.loss.poll(props, props.rng.receive) {
+				shaper.counters.lossDrops.Add(1)
 				continue
 			}
-			shaper.rt.Socket.GetOutgoing() <- frame
-			frame.Time = frame.Time.Add(shaper.props.Latency)
-			frame.Time = frame.Time.Add(shaper.props.PollJitter())
+			if !sendFrame(shaper.rt.Socket.GetOutgoing(), frame, stop) {
+				return
+			}
+			old := frame.Time
+			frame.Time = frame.Time.Add(shaper.jitter.poll(props, props.rng.receive))
 			frame.Time = frame.Time.Add(-shaper.delay.Value)
-			//if len(shaper.queue) < internal.QueueSize {
-			shaper.queue <- frame
-			//}
+			frame, duplicate := impair(frame, props, props.rng.receive)
+			if duplicate != nil {
+				shaper.enqueue(duplicate, props, stop)
+			}
+			if shaper.reorder.poll(props, props.rng.receive) {
+				frame.Time = old.Add(-shaper.delay.Value)
+				sendFrame(shaper.front, frame, stop)
+				continue
+			}
+			shaper.enqueue(frame, props, stop)
 		}
 	}
 }
 
-func (shaper *SniffShaper) send() {
+func (shaper *SniffShaper) send(stop chan struct{}) {
 
 	for {
 		select {
-		case <-shaper.ctx:
+		case <-stop:
 			return
 
+		case frame := <-shaper.front:
+			shaper.transmit(frame)
+
 		case frame := <-shaper.queue:
-			var r *rate.Reservation
-			if shaper.tokenSize < frame.FrameSize {
-				r = shaper.limiter.Reserve()
-				if !r.OK() {
-					fmt.Println("Something went wrong")
-				}
-				shaper.tokenSize = shaper.tokenSize - frame.FrameSize + internal.PacketSize
-				frame.Time = frame.Time.Add(r.Delay())
-			} else {
-				shaper.tokenSize = shaper.tokenSize - frame.FrameSize
+			// Reordered frames overtake every frame still waiting in the queue
+			for len(shaper.front) > 0 {
+				shaper.transmit(<-shaper.front)
 			}
+			shaper.buffer.pop(frame)
+			shaper.transmit(frame)
+		}
+	}
+}
 
-			//go func() {
-			time.Sleep(time.Until(frame.Time))
-			if len(shaper.outgoing) < internal.ComponentQueueSize {
-				shaper.outgoing <- frame
-			}
-			//}(/**/)
+// Queues frame unless a limit of the link buffer was reached
+func (shaper *SniffShaper) enqueue(frame *xdp.Frame, props *LinkProps, stop chan struct{}) {
+	if shaper.buffer.push(frame, props) && !sendFrame(shaper.queue, frame, stop) {
+		shaper.buffer.pop(frame)
+	}
+}
+
+func (shaper *SniffShaper) transmit(frame *xdp.Frame) {
+	var r *rate.Reservation
+	if shaper.tokenSize < frame.FrameSize {
+		r = shaper.limiter.Reserve()
+		if !r.OK() {
+			fmt.Println("Something went wrong")
 		}
+		shaper.tokenSize = shaper.tokenSize - frame.FrameSize + internal.PacketSize
+		frame.Time = frame.Time.Add(r.Delay())
+	} else {
+		shaper.tokenSize = shaper.tokenSize - frame.FrameSize
 	}
+
+	//go func() {
+	time.Sleep(time.Until(frame.Time))
+	if len(shaper.outgoing) < internal.ComponentQueueSize {
+		shaper.outgoing <- frame
+		shaper.counters.forward(frame)
+	} else {
+		shaper.counters.queueDrops.Add(1)
+	}
+	//}(/**/)
 }
 
 func (shaper *SniffShaper) ConvertToNetworkShaper() *NetworkShaper {
 	if shaper.StopDisrupt() {
 		shaper.Stop()
 	}
-	return &NetworkShaper{
+	converted := &NetworkShaper{
 		running:   shaper.running,
 		queue:     shaper.queue,
+		front:     shaper.front,
+		buffer:    shaper.buffer,
 		incoming:  shaper.incoming,
 		outgoing:  shaper.outgoing,
 		props:     shaper.props,
+		loss:      shaper.loss,
+		counters:  shaper.counters,
+		jitter:    shaper.jitter,
+		reorder:   shaper.reorder,
 		limiter:   shaper.limiter,
+		replay:    shaper.replay,
 		delay:     shaper.delay,
 		tokenSize: shaper.tokenSize,
-		ctx:       shaper.ctx,
 	}
+	converted.replay.retarget(converted.applyTrace)
+	return converted
 }
+
+// Stopping a disruption restarts the routines, so it has to happen before they are stopped
 func (shaper *SniffShaper) Close() {
-	shaper.Stop()
 	shaper.StopDisrupt()
+	shaper.Stop()
+	shaper.replay.stop()
 }
+
 func (shaper *SniffShaper) Pause() {
-	if shaper.running {
-		shaper.ctx <- struct{}{}
-		shaper.ctx <- struct{}{}
-	} else if shaper.disrupted.disrupted {
-		shaper.disrupted.ctx <- struct{}{}
-	}
+	shaper.routines.halt()
 }
 
 func (shaper *SniffShaper) Unpause() {
 	if shaper.running {
-		go shaper.receive()
-		go shaper.send()
-	} else if shaper.disrupted.disrupted {
-		go shaper.null()
+		shaper.routines.run(shaper.receive, shaper.send)
+	} else if shaper.disrupted {
+		shaper.routines.run(shaper.null)
 	}
 }
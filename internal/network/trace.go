@@ -0,0 +1,189 @@
+package network
+
+import (
+	"github.com/David-Antunes/gone/internal"
+	"golang.org/x/time/rate"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	MahimahiTrace = "mahimahi"
+	CSVTrace      = "csv"
+)
+
+const (
+	// Mahimahi delivery opportunities are grouped in windows of this size
+	TraceWindow = 10 * time.Millisecond
+
+	// Each mahimahi delivery opportunity delivers one MTU sized packet
+	TraceOpportunitySize = 1500
+
+	// The limiter can't be stopped, so windows without capacity deliver one packet per second
+	minTraceBandwidth = internal.PacketSize
+)
+
+// Link conditions from Time until the next point of the trace
+type TracePoint struct {
+	Time      time.Duration
+	Bandwidth int
+	Latency   time.Duration
+	DropRate  float64
+}
+
+// Recorded link conditions. Bandwidth is in bytes per second.
+// Latency and DropRate are only replayed when the trace provides them.
+type Trace struct {
+	Format     string
+	Points     []TracePoint
+	Duration   time.Duration
+	Loop       bool
+	Offset     time.Duration
+	HasLatency bool
+	HasLoss    bool
+}
+
+func (trace *Trace) Enabled() bool {
+	return trace != nil && len(trace.Points) > 0 && trace.Duration > 0
+}
+
+// Whether the trace changes more than the bandwidth of the link
+func (trace *Trace) varies() bool {
+	return trace.Enabled() && (trace.HasLatency || trace.HasLoss)
+}
+
+// Returns the point active after elapsed time and for how long it stays active.
+// ok is false when a trace that doesn't loop has finished.
+func (trace *Trace) position(elapsed time.Duration) (int, time.Duration, bool) {
+	if trace.Loop {
+		elapsed = elapsed % trace.Duration
+	} else if elapsed >= trace.Duration {
+		return len(trace.Points) - 1, 0, false
+	}
+
+	next := sort.Search(len(trace.Points), func(i int) bool {
+		return trace.Points[i].Time > elapsed
+	})
+	current := max(next-1, 0)
+
+	if next < len(trace.Points) {
+		return current, trace.Points[next].Time - elapsed, true
+	}
+	return current, trace.Duration - elapsed, true
+}
+
+// Replays a trace on a shaper. The replay survives shaper conversions, so sniffing or intercepting a link
+// doesn't restart the trace.
+type traceReplay struct {
+	sync.Mutex
+	trace *Trace
+	begin time.Time
+	ctx   chan struct{}
+}
+
+func newTraceReplay(trace *Trace) *traceReplay {
+	return &traceReplay{
+		Mutex: sync.Mutex{},
+		trace: trace,
+		ctx:   nil,
+	}
+}
+
+// Replaces the replayed trace and starts it from the beginning.
+// Setting the trace being replayed keeps its position and only applies the current point again.
+func (replay *traceReplay) set(trace *Trace, apply func(point TracePoint)) {
+	replay.Lock()
+	defer replay.Unlock()
+	if reflect.DeepEqual(trace, replay.trace) {
+		if replay.ctx != nil {
+			replay.stopLocked()
+			replay.startLocked(apply)
+		}
+		return
+	}
+	replay.stopLocked()
+	replay.trace = trace
+	replay.begin = time.Now()
+	replay.startLocked(apply)
+}
+
+// Starts replaying the trace if it isn't already. The trace begins on the first start.
+func (replay *traceReplay) start(apply func(point TracePoint)) {
+	replay.Lock()
+	defer replay.Unlock()
+	if replay.ctx != nil {
+		return
+	}
+	if replay.begin.IsZero() {
+		replay.begin = time.Now()
+	}
+	replay.startLocked(apply)
+}
+
+// Keeps replaying the trace on a converted shaper
+func (replay *traceReplay) retarget(apply func(point TracePoint)) {
+	replay.Lock()
+	defer replay.Unlock()
+	if replay.ctx == nil {
+		return
+	}
+	replay.stopLocked()
+	replay.startLocked(apply)
+}
+
+func (replay *traceReplay) stop() {
+	replay.Lock()
+	defer replay.Unlock()
+	replay.stopLocked()
+}
+
+func (replay *traceReplay) startLocked(apply func(point TracePoint)) {
+	if !replay.trace.Enabled() {
+		return
+	}
+	replay.ctx = make(chan struct{})
+	go replayTrace(replay.trace, replay.begin, apply, replay.ctx)
+}
+
+func (replay *traceReplay) stopLocked() {
+	if replay.ctx != nil {
+		close(replay.ctx)
+		replay.ctx = nil
+	}
+}
+
+func replayTrace(trace *Trace, begin time.Time, apply func(point TracePoint), ctx chan struct{}) {
+	for {
+		current, next, ok := trace.position(time.Since(begin) + trace.Offset)
+		apply(trace.Points[current])
+		if !ok {
+			return
+		}
+
+		timer := time.NewTimer(next)
+		select {
+		case <-ctx:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Applies a trace point over the link properties and limiter of a shaper
+func applyTracePoint(shared *sharedProps, limiter *rate.Limiter, point TracePoint) {
+	shared.update(func(props *LinkProps) {
+		limiter.SetLimit(bandwidthLimit(max(point.Bandwidth, minTraceBandwidth)))
+		if props.Trace == nil {
+			return
+		}
+		if props.Trace.HasLatency {
+			props.Latency = point.Latency
+		}
+		if props.Trace.HasLoss {
+			props.DropRate = point.DropRate
+		}
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"sync"
+	"sync/atomic"
 )
 
 var proxyLog = log.New(os.Stdout, "PROXY INFO: ", log.Ltime)
@@ -17,15 +18,34 @@ type Proxy struct {
 	incoming    map[string]chan *xdp.Frame
 	outgoing    map[string]chan *xdp.Frame
 	connections map[string]net.Conn
+	counters    map[string]*macCounters
 	running     bool
 }
 
+// Rx counts the frames received from a container and Tx the frames delivered to it
+type MacStats struct {
+	RxFrames uint64
+	RxBytes  uint64
+	TxFrames uint64
+	TxBytes  uint64
+	Drops    uint64
+}
+
+type macCounters struct {
+	rxFrames atomic.Uint64
+	rxBytes  atomic.Uint64
+	txFrames atomic.Uint64
+	txBytes  atomic.Uint64
+	drops    atomic.Uint64
+}
+
 func CreateProxy() *Proxy {
 	return &Proxy{
 		Mutex:       sync.Mutex{},
 		incoming:    make(map[string]chan *xdp.Frame),
 		outgoing:    make(map[string]chan *xdp.Frame),
 		connections: make(map[string]net.Conn),
+		counters:    make(map[string]*macCounters),
 		running:     false,
 	}
 }
@@ -50,21 +70,44 @@ func (p *Proxy) AddMac(mac []byte, incoming chan *xdp.Frame, outgoing chan *xdp.
 	dec := gob.NewDecoder(conn)
 	p.incoming[string(mac)] = incoming
 	p.outgoing[string(mac)] = outgoing
+	p.connections[string(mac)] = conn
+	counters := &macCounters{}
+	p.counters[string(mac)] = counters
 	p.Unlock()
 
-	go receive(string(mac), dec, incoming)
-	go send(string(mac), enc, outgoing)
+	go receive(string(mac), dec, incoming, counters)
+	go send(string(mac), enc, outgoing, counters)
+}
+
+func (p *Proxy) GetStats() map[string]MacStats {
+	p.Lock()
+	defer p.Unlock()
+	stats := make(map[string]MacStats, len(p.counters))
+	for mac, counters := range p.counters {
+		stats[mac] = MacStats{
+			RxFrames: counters.rxFrames.Load(),
+			RxBytes:  counters.rxBytes.Load(),
+			TxFrames: counters.txFrames.Load(),
+			TxBytes:  counters.txBytes.Load(),
+			Drops:    counters.drops.Load(),
+		}
+	}
+	return stats
 }
 
 func (p *Proxy) RemoveMac(mac []byte) {
 	p.Lock()
+	if conn, ok := p.connections[string(mac)]; ok {
+		conn.Close()
+	}
 	delete(p.incoming, string(mac))
 	delete(p.outgoing, string(mac))
 	delete(p.connections, string(mac))
+	delete(p.counters, string(mac))
 	p.Unlock()
 }
 
-func receive(mac string, dec *gob.Decoder, incoming chan *xdp.Frame) {
+func receive(mac string, dec *gob.Decoder, incoming chan *xdp.Frame, counters *macCounters) {
 
 	for {
 		var frame *xdp.Frame
@@ -77,13 +120,15 @@ func receive(mac string, dec *gob.Decoder, incoming chan *xdp.Frame) {
 		if len(incoming) < internal.ProxyQueueSize {
 			//frame.Time = frame.Time.Add(-time.Now().Sub(frame.Time))
 			incoming <- frame
-			//} else {
-			//	fmt.Println("Proxy queue full")
+			counters.rxFrames.Add(1)
+			counters.rxBytes.Add(uint64(frame.FrameSize))
+		} else {
+			counters.drops.Add(1)
 		}
 	}
 }
 
-func send(mac string, enc *gob.Encoder, outgoing chan *xdp.Frame) {
+func send(mac string, enc *gob.Encoder, outgoing chan *xdp.Frame, counters *macCounters) {
 	for {
 		select {
 		case frame := <-outgoing:
@@ -92,6 +137,8 @@ func send(mac string, enc *gob.Encoder, outgoing chan *xdp.Frame) {
 				proxyLog.Println(mac+":", err)
 				return
 			}
+			counters.txFrames.Add(1)
+			counters.txBytes.Add(uint64(frame.FrameSize))
 		}
 	}
 }
@@ -0,0 +1,200 @@
+package scheduler
+
+import (
+	"errors"
+	"github.com/David-Antunes/gone/api"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var schedulerLog = log.New(os.Stdout, "SCHEDULER INFO: ", log.Ltime)
+
+// Action states
+const (
+	Pending   = "pending"
+	Done      = "done"
+	Failed    = "failed"
+	Cancelled = "cancelled"
+)
+
+// Timeline states
+const (
+	Running  = "running"
+	Finished = "finished"
+)
+
+// Operation executed Offset after the start of a timeline
+type Action struct {
+	Offset time.Duration
+	Name   string
+	Run    func() error
+}
+
+type actionState struct {
+	Action
+	status     string
+	executedAt time.Time
+	err        string
+}
+
+type timeline struct {
+	id      string
+	start   time.Time
+	status  string
+	actions []*actionState
+	ctx     chan struct{}
+}
+
+// Executes timelines of actions relative to their start instant
+type Scheduler struct {
+	sync.Mutex
+	timelines map[string]*timeline
+	order     []string
+	counter   int
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		Mutex:     sync.Mutex{},
+		timelines: make(map[string]*timeline),
+		order:     make([]string, 0),
+		counter:   0,
+	}
+}
+
+// Schedules actions relative to start. Actions with the same offset run in the given order.
+func (s *Scheduler) Schedule(start time.Time, actions []Action) string {
+	s.Lock()
+	defer s.Unlock()
+
+	states := make([]*actionState, 0, len(actions))
+	for _, action := range actions {
+		states = append(states, &actionState{
+			Action: action,
+			status: Pending,
+		})
+	}
+	sort.SliceStable(states, func(i, j int) bool {
+		return states[i].Offset < states[j].Offset
+	})
+
+	t := &timeline{
+		id:      "timeline" + strconv.Itoa(s.counter),
+		start:   start,
+		status:  Running,
+		actions: states,
+		ctx:     make(chan struct{}),
+	}
+	s.counter++
+	s.timelines[t.id] = t
+	s.order = append(s.order, t.id)
+
+	go s.run(t)
+	return t.id
+}
+
+func (s *Scheduler) run(t *timeline) {
+	for _, action := range t.actions {
+		timer := time.NewTimer(time.Until(t.start.Add(action.Offset)))
+		select {
+		case <-t.ctx:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.Lock()
+		if t.status == Cancelled {
+			s.Unlock()
+			return
+		}
+		s.Unlock()
+
+		err := action.Run()
+
+		s.Lock()
+		action.executedAt = time.Now()
+		if err != nil {
+			action.status = Failed
+			action.err = err.Error()
+			schedulerLog.Println(t.id+":", action.Name, "failed:", err)
+		} else {
+			action.status = Done
+			schedulerLog.Println(t.id+":", action.Name, "done")
+		}
+		s.Unlock()
+	}
+
+	s.Lock()
+	if t.status == Running {
+		t.status = Finished
+	}
+	s.Unlock()
+}
+
+// Cancels the pending actions of a timeline. An action already executing is not interrupted.
+func (s *Scheduler) Cancel(id string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	t, ok := s.timelines[id]
+	if !ok {
+		return errors.New("invalid timeline id: " + id)
+	}
+	if t.status != Running {
+		return errors.New(id + " is already " + t.status)
+	}
+	t.status = Cancelled
+	for _, action := range t.actions {
+		if action.status == Pending {
+			action.status = Cancelled
+		}
+	}
+	close(t.ctx)
+	return nil
+}
+
+func (s *Scheduler) Get(id string) (api.Timeline, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	t, ok := s.timelines[id]
+	if !ok {
+		return api.Timeline{}, false
+	}
+	return convertToAPITimeline(t), true
+}
+
+func (s *Scheduler) List() []api.Timeline {
+	s.Lock()
+	defer s.Unlock()
+
+	timelines := make([]api.Timeline, 0, len(s.order))
+	for _, id := range s.order {
+		timelines = append(timelines, convertToAPITimeline(s.timelines[id]))
+	}
+	return timelines
+}
+
+func convertToAPITimeline(t *timeline) api.Timeline {
+	actions := make([]api.TimelineActionStatus, 0, len(t.actions))
+	for _, action := range t.actions {
+		actions = append(actions, api.TimelineActionStatus{
+			OffsetMs:   float64(action.Offset) / float64(time.Millisecond),
+			Action:     action.Name,
+			Status:     action.status,
+			ExecutedAt: action.executedAt,
+			Error:      action.err,
+		})
+	}
+	return api.Timeline{
+		Id:      t.id,
+		Start:   t.start,
+		Status:  t.status,
+		Actions: actions,
+	}
+}
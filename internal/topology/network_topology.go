@@ -27,30 +27,37 @@ type RemoteFlowManager interface {
 }
 type Topology struct {
 	sync.Mutex
-	machineId string
-	nodes     map[string]*Node
-	macs      map[string]*Node
-	bridges   map[string]*Bridge
-	routers   map[string]*Router
-	links     map[string]*BiLink
-	fl        LocalFlowManager
-	delay     *network.DynamicDelay
+	machineId  string
+	nodes      map[string]*Node
+	macs       map[string]*Node
+	bridges    map[string]*Bridge
+	routers    map[string]*Router
+	links      map[string]*BiLink
+	fl         LocalFlowManager
+	delay      *network.DynamicDelay
+	partitions *network.Partitions
 }
 
 func CreateTopology(machineId string, fl LocalFlowManager, delay *network.DynamicDelay) *Topology {
 	return &Topology{
-		Mutex:     sync.Mutex{},
-		machineId: machineId,
-		nodes:     make(map[string]*Node),
-		macs:      make(map[string]*Node),
-		bridges:   make(map[string]*Bridge),
-		routers:   make(map[string]*Router),
-		links:     map[string]*BiLink{},
-		fl:        fl,
-		delay:     delay,
+		Mutex:      sync.Mutex{},
+		machineId:  machineId,
+		nodes:      make(map[string]*Node),
+		macs:       make(map[string]*Node),
+		bridges:    make(map[string]*Bridge),
+		routers:    make(map[string]*Router),
+		links:      map[string]*BiLink{},
+		fl:         fl,
+		delay:      delay,
+		partitions: network.NewPartitions(),
 	}
 }
 
+// Partitions shared by the bridges and routers of this machine
+func (topo *Topology) GetPartitions() *network.Partitions {
+	return topo.partitions
+}
+
 func (topo *Topology) GetRouterNumber() int {
 	return len(topo.routers)
 }
@@ -79,6 +86,46 @@ func (topo *Topology) GetBiLink(id string) (*BiLink, bool) {
 	return l, ok
 }
 
+func (topo *Topology) GetNodes() []*Node {
+	topo.Lock()
+	defer topo.Unlock()
+	nodes := make([]*Node, 0, len(topo.nodes))
+	for _, n := range topo.nodes {
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+func (topo *Topology) GetBridges() []*Bridge {
+	topo.Lock()
+	defer topo.Unlock()
+	bridges := make([]*Bridge, 0, len(topo.bridges))
+	for _, b := range topo.bridges {
+		bridges = append(bridges, b)
+	}
+	return bridges
+}
+
+func (topo *Topology) GetRouters() []*Router {
+	topo.Lock()
+	defer topo.Unlock()
+	routers := make([]*Router, 0, len(topo.routers))
+	for _, r := range topo.routers {
+		routers = append(routers, r)
+	}
+	return routers
+}
+
+func (topo *Topology) GetBiLinks() []*BiLink {
+	topo.Lock()
+	defer topo.Unlock()
+	links := make([]*BiLink, 0, len(topo.links))
+	for _, l := range topo.links {
+		links = append(links, l)
+	}
+	return links
+}
+
 func (topo *Topology) RegisterNode(id string, mac string, machineId string) (Node, error) {
 	topo.Lock()
 	defer topo.Unlock()
@@ -120,7 +167,7 @@ func (topo *Topology) RegisterBridge(id string, machineId string) (Bridge, error
 
 	var b *network.Bridge = nil
 	if topo.machineId == machineId {
-		b = network.CreateBridge()
+		b = network.CreateBridge(topo.partitions)
 		b.SetGateway(internal.GetNullChan())
 		b.Start()
 	}
@@ -148,7 +195,7 @@ func (topo *Topology) RegisterRouter(id string, machineId string) (Router, error
 	var r *network.Router = nil
 	if topo.machineId == machineId {
 
-		r = network.CreateRouter(id)
+		r = network.CreateRouter(id, topo.partitions)
 		r.Start()
 	}
 
@@ -167,6 +214,12 @@ func (topo *Topology) RegisterRouter(id string, machineId string) (Router, error
 	return *component, nil
 }
 
+// Seeds both directions of the next link from the ids registerBiLink gives them
+func (topo *Topology) seedBiLink(up network.LinkProps, down network.LinkProps) (network.LinkProps, network.LinkProps) {
+	linkId := "link" + strconv.Itoa(len(topo.links))
+	return up.Seeded(linkId + "-1"), down.Seeded(linkId + "-2")
+}
+
 func (topo *Topology) registerBiLink(toComponent Component, fromComponent Component, networkBiLink *network.BiLink) *BiLink {
 	toId := "link" + strconv.Itoa(len(topo.links)) + "-1"
 	fromId := "link" + strconv.Itoa(len(topo.links)) + "-2"
@@ -227,7 +280,7 @@ func (topo *Topology) registerRemoteBiLink(toComponent Component, fromComponent
 	return biLink
 }
 
-func (topo *Topology) ConnectNodeToBridge(nodeID string, bridgeID string, linkProps network.LinkProps) (*BiLink, error) {
+func (topo *Topology) ConnectNodeToBridge(nodeID string, bridgeID string, up network.LinkProps, down network.LinkProps) (*BiLink, error) {
 	topo.Lock()
 	defer topo.Unlock()
 
@@ -262,7 +315,8 @@ func (topo *Topology) ConnectNodeToBridge(nodeID string, bridgeID string, linkPr
 		return link, nil
 	}
 	n.NetworkNode.GetLink().Left.Close()
-	biLink := network.ConnectNodeToBridge(n.NetworkNode, b.NetworkBridge, linkProps)
+	up, down = topo.seedBiLink(up, down)
+	biLink := network.ConnectNodeToBridge(n.NetworkNode, b.NetworkBridge, up, down)
 	topoLink := topo.registerBiLink(n, b, biLink)
 	n.SetBridge(b, topoLink)
 	b.AddNode(n, topoLink)
@@ -280,7 +334,7 @@ func (topo *Topology) ConnectNodeToBridge(nodeID string, bridgeID string, linkPr
 	return topoLink, nil
 }
 
-func (topo *Topology) ConnectBridgeToRouter(bridgeID string, routerID string, linkProps network.LinkProps) (*BiLink, error) {
+func (topo *Topology) ConnectBridgeToRouter(bridgeID string, routerID string, up network.LinkProps, down network.LinkProps) (*BiLink, error) {
 	topo.Lock()
 	defer topo.Unlock()
 	var b *Bridge
@@ -315,7 +369,8 @@ func (topo *Topology) ConnectBridgeToRouter(bridgeID string, routerID string, li
 		return topoLink, nil
 	}
 
-	biLink := network.ConnectBridgeToRouter(b.NetworkBridge, r.NetworkRouter, linkProps)
+	up, down = topo.seedBiLink(up, down)
+	biLink := network.ConnectBridgeToRouter(b.NetworkBridge, r.NetworkRouter, up, down)
 	topoLink := topo.registerBiLink(b, r, biLink)
 
 	b.SetRouter(r, topoLink)
@@ -332,7 +387,7 @@ func (topo *Topology) ConnectBridgeToRouter(bridgeID string, routerID string, li
 	return topoLink, nil
 }
 
-func (topo *Topology) ConnectRouterToRouterLocal(router1 string, router2 string, linkProps network.LinkProps) (*BiLink, error) {
+func (topo *Topology) ConnectRouterToRouterLocal(router1 string, router2 string, up network.LinkProps, down network.LinkProps) (*BiLink, error) {
 
 	topo.Lock()
 	defer topo.Unlock()
@@ -357,7 +412,8 @@ func (topo *Topology) ConnectRouterToRouterLocal(router1 string, router2 string,
 		return nil, errors.New(router1 + " is already connected to " + router2)
 	}
 
-	biLink := network.ConnectRouterToRouter(r1.NetworkRouter, r2.NetworkRouter, linkProps)
+	up, down = topo.seedBiLink(up, down)
+	biLink := network.ConnectRouterToRouter(r1.NetworkRouter, r2.NetworkRouter, up, down)
 	link := topo.registerBiLink(r1, r2, biLink)
 
 	r1.AddRouter(r2, link)
@@ -423,6 +479,24 @@ func (topo *Topology) InsertNullPath(mac string, routerId string) {
 	}(mac, routerId)
 }
 
+// Reconnects a local node to the proxy after its container restarted.
+// The node keeps its channels, so its bridge link is left untouched.
+func (topo *Topology) ReattachNode(nodeId string, mac string) error {
+	topo.Lock()
+	defer topo.Unlock()
+
+	n, ok := topo.nodes[nodeId]
+	if !ok {
+		return errors.New(nodeId + " ID doesn't exist")
+	}
+	if n.MachineId != topo.machineId {
+		return errors.New(nodeId + " is not in this machine")
+	}
+	topo.fl.RemoveMac([]byte(mac))
+	topo.fl.AddMac([]byte(mac), n.NetworkNode.GetIncoming(), n.NetworkNode.GetOutgoing())
+	return nil
+}
+
 func (topo *Topology) RemoveNode(nodeId string) (*Node, error) {
 	topo.Lock()
 	defer topo.Unlock()
@@ -9,6 +9,7 @@ import (
 	"github.com/David-Antunes/gone/internal/follower"
 	"github.com/David-Antunes/gone/internal/graphDB"
 	"github.com/David-Antunes/gone/internal/leader"
+	"github.com/David-Antunes/gone/internal/network"
 	"github.com/David-Antunes/gone/internal/network/routing"
 	"github.com/David-Antunes/gone/internal/proxy"
 	"github.com/spf13/viper"
@@ -70,6 +71,7 @@ func setEnvVariables() {
 	viper.SetDefault("TIMEOUT_REMOTE_RTT_MS", 0)
 	viper.SetDefault("NETWORK_NAMESPACE", "gone_net")
 	viper.SetDefault("NUM_TESTS", 1000)
+	viper.SetDefault("SEED", 0)
 	viper.SetConfigType("env")
 	err := viper.WriteConfig()
 	if err != nil {
@@ -120,6 +122,12 @@ func main() {
 	p := viper.GetInt("PRIMARY")
 	id := viper.GetString("ID")
 	graphDB.SetCost(viper.GetInt("GRAPH_COST"))
+	seed := viper.GetInt64("SEED")
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	network.SetEmulationSeed(seed)
+	emulationLog.Println("Emulation seed:", seed)
 	primaryAddr := viper.GetString("PRIMARY_SERVER_IP")
 	primaryPort := viper.GetString("PRIMARY_SERVER_PORT")
 	serverIP := viper.GetString("SERVER_IP")